@@ -0,0 +1,127 @@
+// Package auth issues and verifies the JWT access tokens and opaque refresh
+// tokens of user accounts, and hashes their passwords
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"roadtouniversities/models"
+)
+
+// Default token lifetimes
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// Password length bounds; bcrypt ignores everything past 72 bytes
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// issuer is the iss claim of every access token
+const issuer = "roadtouniversities"
+
+// ErrInvalidToken is returned for malformed, forged or expired tokens
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the claims of an access token. The subject is the user ID.
+type Claims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Issuer signs and verifies access tokens with an HMAC secret. It is safe
+// for concurrent use.
+type Issuer struct {
+	secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// NewIssuer creates an Issuer signing with secret, using the default
+// lifetimes for zero TTLs
+func NewIssuer(secret []byte, accessTTL, refreshTTL time.Duration) *Issuer {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &Issuer{secret: secret, AccessTTL: accessTTL, RefreshTTL: refreshTTL}
+}
+
+// IssueAccess signs an access token for user, valid from now for AccessTTL
+func (i *Issuer) IssueAccess(user models.User, now time.Time) (string, error) {
+	claims := Claims{
+		Email: user.Email,
+		Role:  user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.AccessTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+}
+
+// ParseAccess verifies an access token, returning the user it was issued to
+func (i *Issuer) ParseAccess(token string) (models.User, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return i.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(issuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return models.User{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return models.User{}, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return models.User{ID: claims.Subject, Email: claims.Email, Role: claims.Role}, nil
+}
+
+// NewRefreshToken returns a random refresh token for user, valid from now
+// for RefreshTTL, and the record to store for it
+func (i *Issuer) NewRefreshToken(userID string, now time.Time) (string, models.RefreshToken, error) {
+	token, err := RandomString(32)
+	if err != nil {
+		return "", models.RefreshToken{}, err
+	}
+	return token, models.RefreshToken{Hash: HashToken(token), UserID: userID, ExpiresAt: now.Add(i.RefreshTTL)}, nil
+}
+
+// HashToken returns the stored form of a refresh token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RandomString returns n random bytes, hex-encoded
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashPassword hashes a password with bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CheckPassword reports whether password matches a bcrypt hash
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"roadtouniversities/models"
+)
+
+func TestParseAccess(t *testing.T) {
+	tokens := NewIssuer([]byte("secret"), 0, 0)
+	user := models.User{ID: "u1", Email: "student@example.com", Role: models.RoleStudent}
+	now := time.Now()
+
+	valid, err := tokens.IssueAccess(user, now)
+	if err != nil {
+		t.Fatalf("IssueAccess: %v", err)
+	}
+	got, err := tokens.ParseAccess(valid)
+	if err != nil {
+		t.Fatalf("ParseAccess: %v", err)
+	}
+	if got != user {
+		t.Fatalf("ParseAccess = %+v, want %+v", got, user)
+	}
+
+	sign := func(method jwt.SigningMethod, key any, edit func(*Claims)) string {
+		claims := Claims{Email: user.Email, Role: user.Role, RegisteredClaims: jwt.RegisteredClaims{
+			Issuer: issuer, Subject: user.ID, IssuedAt: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}}
+		edit(&claims)
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return token
+	}
+	secret := []byte("secret")
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", mustIssue(t, NewIssuer(secret, time.Minute, 0), user, now.Add(-2*time.Minute))},
+		{"other secret", mustIssue(t, NewIssuer([]byte("other"), 0, 0), user, now)},
+		{"other algorithm", sign(jwt.SigningMethodHS512, secret, func(*Claims) {})},
+		{"unsigned", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, func(*Claims) {})},
+		{"other issuer", sign(jwt.SigningMethodHS256, secret, func(c *Claims) { c.Issuer = "someone-else" })},
+		{"no expiry", sign(jwt.SigningMethodHS256, secret, func(c *Claims) { c.ExpiresAt = nil })},
+		{"no subject", sign(jwt.SigningMethodHS256, secret, func(c *Claims) { c.Subject = "" })},
+		{"tampered", valid[:len(valid)-2] + "xx"},
+		{"garbage", "not.a.token"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.ParseAccess(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("err = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func mustIssue(t *testing.T, i *Issuer, user models.User, now time.Time) string {
+	t.Helper()
+	token, err := i.IssueAccess(user, now)
+	if err != nil {
+		t.Fatalf("IssueAccess: %v", err)
+	}
+	return token
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	i := NewIssuer([]byte("secret"), 0, time.Hour)
+	now := time.Now()
+	token, record, err := i.NewRefreshToken("u1", now)
+	if err != nil {
+		t.Fatalf("NewRefreshToken: %v", err)
+	}
+	if record.Hash != HashToken(token) || record.Hash == token {
+		t.Errorf("record holds %q, want the hash of the token", record.Hash)
+	}
+	if record.UserID != "u1" || !record.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("record = %+v, want u1 expiring in an hour", record)
+	}
+	other, _, err := i.NewRefreshToken("u1", now)
+	if err != nil || other == token {
+		t.Errorf("second token = %q, %v, want a different token", other, err)
+	}
+}
+
+func TestNewIssuerDefaults(t *testing.T) {
+	i := NewIssuer([]byte("secret"), 0, -time.Hour)
+	if i.AccessTTL != DefaultAccessTTL || i.RefreshTTL != DefaultRefreshTTL {
+		t.Fatalf("TTLs = %v, %v, want the defaults", i.AccessTTL, i.RefreshTTL)
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	for password, want := range map[string]bool{"correct horse": true, "correct horse ": false, "": false} {
+		if got := CheckPassword(hash, password); got != want {
+			t.Errorf("CheckPassword(%q) = %v, want %v", password, got, want)
+		}
+	}
+	if CheckPassword("not a hash", "correct horse") {
+		t.Error("CheckPassword accepted a malformed hash")
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/notify"
+)
+
+// config holds the server settings. Each flag falls back to an environment
+// variable, then to a default.
+type config struct {
+	Port    string
+	DataDir string
+	Store   data.Config
+
+	// RecommendationWeights is a JSON or YAML file of factor weights
+	RecommendationWeights string
+	// ExchangeRates is a CSV file of exchange rates replacing the seed table
+	ExchangeRates string
+
+	// JWTSecret signs access tokens; a random secret is used when empty
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// AdminEmail and AdminPassword create the first admin account
+	AdminEmail    string
+	AdminPassword string
+
+	// Notify selects how users hear their saved searches changed
+	Notify notify.Config
+}
+
+func loadConfig() config {
+	var cfg config
+
+	flag.StringVar(&cfg.Port, "port", envOr("PORT", "8080"), "HTTP port (env PORT)")
+	flag.StringVar(&cfg.DataDir, "data-dir", os.Getenv("DATA_DIR"), "directory of JSON/YAML seed files; built-in data when empty (env DATA_DIR)")
+	flag.StringVar(&cfg.Store.Driver, "store", envOr("STORE_DRIVER", data.DriverMemory), "storage backend: memory or sqlite (env STORE_DRIVER)")
+	flag.StringVar(&cfg.Store.SQLitePath, "sqlite-path", envOr("SQLITE_PATH", "roadtouni.db"), "SQLite database file (env SQLITE_PATH)")
+	flag.StringVar(&cfg.RecommendationWeights, "recommendation-weights", os.Getenv("RECOMMENDATION_WEIGHTS"), "JSON/YAML file of recommendation factor weights; built-in weights when empty (env RECOMMENDATION_WEIGHTS)")
+	flag.StringVar(&cfg.ExchangeRates, "exchange-rates", os.Getenv("EXCHANGE_RATES"), "CSV file of exchange rates; exchange-rates.csv of the seed data when empty (env EXCHANGE_RATES)")
+	flag.StringVar(&cfg.JWTSecret, "jwt-secret", os.Getenv("JWT_SECRET"), "secret signing access tokens; random per process when empty (env JWT_SECRET)")
+	flag.DurationVar(&cfg.AccessTokenTTL, "access-token-ttl", envDuration("ACCESS_TOKEN_TTL", auth.DefaultAccessTTL), "lifetime of access tokens (env ACCESS_TOKEN_TTL)")
+	flag.DurationVar(&cfg.RefreshTokenTTL, "refresh-token-ttl", envDuration("REFRESH_TOKEN_TTL", auth.DefaultRefreshTTL), "lifetime of refresh tokens (env REFRESH_TOKEN_TTL)")
+	flag.StringVar(&cfg.AdminEmail, "admin-email", os.Getenv("ADMIN_EMAIL"), "email of an admin account created at startup (env ADMIN_EMAIL)")
+	flag.StringVar(&cfg.AdminPassword, "admin-password", os.Getenv("ADMIN_PASSWORD"), "password of that admin account (env ADMIN_PASSWORD)")
+	flag.StringVar(&cfg.Notify.Kind, "notifier", envOr("NOTIFIER", notify.KindLog), "saved-search notifications: log, email, webhook or none (env NOTIFIER)")
+	flag.StringVar(&cfg.Notify.SMTPAddr, "smtp-addr", envOr("SMTP_ADDR", "localhost:1025"), "SMTP server for email notifications (env SMTP_ADDR)")
+	flag.StringVar(&cfg.Notify.From, "smtp-from", envOr("SMTP_FROM", "noreply@roadtouniversities.local"), "sender of email notifications (env SMTP_FROM)")
+	flag.StringVar(&cfg.Notify.WebhookURL, "webhook-url", os.Getenv("NOTIFY_WEBHOOK_URL"), "URL receiving webhook notifications as JSON (env NOTIFY_WEBHOOK_URL)")
+	flag.Parse()
+
+	return cfg
+}
+
+// envOr returns the environment variable key, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envDuration parses the environment variable key as a duration, or returns
+// fallback when it is unset
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return d
+}
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"roadtouniversities/models"
+)
+
+// ErrInvalidBackground is returned for an unknown educational background
+var ErrInvalidBackground = errors.New("invalid educational background")
+
+// AdmissionRules returns the university's admission rules. Universities
+// without explicit rules accept the certificates their type allows at
+// MinGrade: Al-Azhar universities take Azhari secondary only, the rest take
+// Thanawiya, Azhari and international certificates.
+func AdmissionRules(uni models.University) []models.AdmissionRule {
+	if len(uni.Admission) > 0 {
+		return uni.Admission
+	}
+
+	minGrade := float64(uni.MinGrade)
+	if uni.Type == "azhar" {
+		return []models.AdmissionRule{
+			{Certificate: models.CertificateAzhari, MinPercentage: minGrade},
+		}
+	}
+	return []models.AdmissionRule{
+		{Certificate: models.CertificateThanawiya, MinPercentage: minGrade},
+		{Certificate: models.CertificateAzhari, MinPercentage: minGrade},
+		{Certificate: models.CertificateInternational, MinPercentage: minGrade},
+	}
+}
+
+// findRule returns the rule that applies to a certificate. A specific
+// international certificate falls back to a generic "international" rule, and
+// the generic background matches the most lenient international rule.
+func findRule(rules []models.AdmissionRule, certificate string) (models.AdmissionRule, bool) {
+	for _, rule := range rules {
+		if rule.Certificate == certificate {
+			return rule, true
+		}
+	}
+	if !models.IsInternationalCertificate(certificate) {
+		return models.AdmissionRule{}, false
+	}
+
+	var best models.AdmissionRule
+	found := false
+	for _, rule := range rules {
+		if certificate != models.CertificateInternational && rule.Certificate != models.CertificateInternational {
+			continue
+		}
+		if !models.IsInternationalCertificate(rule.Certificate) {
+			continue
+		}
+		if !found || rule.MinPercentage < best.MinPercentage {
+			best, found = rule, true
+		}
+	}
+	return best, found
+}
+
+// checkRule evaluates a student against a rule. grade is a percentage and
+// score a mark on the certificate's own scale; either may be nil.
+func checkRule(rule models.AdmissionRule, grade *int, score *float64) (bool, string) {
+	percentage, hasPercentage := 0.0, false
+	if score != nil && rule.ScoreScale > 0 {
+		percentage, hasPercentage = *score/rule.ScoreScale*100, true
+	} else if grade != nil {
+		percentage, hasPercentage = float64(*grade), true
+	}
+
+	checked := false
+	if rule.MinScore > 0 && score != nil {
+		checked = true
+		if *score < rule.MinScore {
+			return false, fmt.Sprintf("requires a %s score of %g, you have %g", rule.Certificate, rule.MinScore, *score)
+		}
+	}
+	if rule.MinScore > 0 && score == nil && hasPercentage && rule.ScoreScale > 0 {
+		checked = true
+		if required := rule.MinScore / rule.ScoreScale * 100; percentage < required {
+			return false, fmt.Sprintf("requires the equivalent of %.1f%% for %s, you have %.1f%%", required, rule.Certificate, percentage)
+		}
+	}
+	if rule.MinPercentage > 0 && hasPercentage {
+		checked = true
+		if percentage < rule.MinPercentage {
+			return false, fmt.Sprintf("requires %g%% for %s, you have %.1f%%", rule.MinPercentage, rule.Certificate, percentage)
+		}
+	}
+
+	// Without comparable grades the certificate is accepted but unverified
+	if !checked {
+		return true, describeRule(rule)
+	}
+	return true, "meets " + describeRule(rule)
+}
+
+// describeRule summarises a rule's minimums
+func describeRule(rule models.AdmissionRule) string {
+	switch {
+	case rule.MinScore > 0 && rule.ScoreScale > 0:
+		return fmt.Sprintf("%s minimum score %g of %g", rule.Certificate, rule.MinScore, rule.ScoreScale)
+	case rule.MinScore > 0:
+		return fmt.Sprintf("%s minimum score %g", rule.Certificate, rule.MinScore)
+	case rule.MinPercentage > 0:
+		return fmt.Sprintf("%s minimum %g%%", rule.Certificate, rule.MinPercentage)
+	default:
+		return rule.Certificate + " accepted"
+	}
+}
+
+// CheckEligibility explains whether a student with the given certificate and
+// grades can enter the university. Faculties with their own rules are
+// evaluated separately; the student is eligible when either the university
+// rule or at least one faculty rule is met.
+func CheckEligibility(uni models.University, certificate string, grade *int, score *float64) models.Eligibility {
+	result := models.Eligibility{Certificate: certificate}
+
+	if rule, found := findRule(AdmissionRules(uni), certificate); found {
+		result.MinPercentage = rule.MinPercentage
+		result.MinScore = rule.MinScore
+		result.Eligible, result.Reason = checkRule(rule, grade, score)
+	} else {
+		result.Reason = "does not accept " + certificate + " certificates"
+	}
+
+	keys := make([]string, 0, len(uni.DetailedFaculties))
+	for key, faculty := range uni.DetailedFaculties {
+		if len(faculty.Admission) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		faculty := uni.DetailedFaculties[key]
+		fe := models.FacultyEligibility{Faculty: key, FacultyEn: faculty.NameEn}
+		if rule, found := findRule(faculty.Admission, certificate); found {
+			fe.MinPercentage = rule.MinPercentage
+			fe.MinScore = rule.MinScore
+			fe.Eligible, fe.Reason = checkRule(rule, grade, score)
+		} else {
+			fe.Reason = "does not accept " + certificate + " certificates"
+		}
+		if fe.Eligible && !result.Eligible {
+			result.Eligible = true
+			result.Reason = "eligible for " + faculty.NameEn + " only"
+		}
+		result.Faculties = append(result.Faculties, fe)
+	}
+	return result
+}
+
+// validBackground reports whether background selects a certificate filter
+func validBackground(background string) error {
+	if background == "" || background == "all" || models.IsValidCertificate(background) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidBackground, background)
+}
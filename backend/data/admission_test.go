@@ -0,0 +1,205 @@
+package data
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestDefaultSeedHasNoAdmissionRules(t *testing.T) {
+	unis, _ := seedCatalogue(t)
+	for _, uni := range unis {
+		if len(uni.Admission) > 0 {
+			t.Errorf("default seed gives %s admission rules %+v, want none", uni.ID, uni.Admission)
+		}
+		for key, faculty := range uni.DetailedFaculties {
+			if len(faculty.Admission) > 0 {
+				t.Errorf("default seed gives %s faculty %s admission rules %+v, want none", uni.ID, key, faculty.Admission)
+			}
+		}
+	}
+}
+
+func TestAdmissionRulesDefaults(t *testing.T) {
+	tests := []struct {
+		uni  models.University
+		want []string
+	}{
+		{models.University{Type: "azhar", MinGrade: 70}, []string{models.CertificateAzhari}},
+		{models.University{Type: "public", MinGrade: 85},
+			[]string{models.CertificateThanawiya, models.CertificateAzhari, models.CertificateInternational}},
+	}
+	for _, tt := range tests {
+		rules := AdmissionRules(tt.uni)
+		var got []string
+		for _, rule := range rules {
+			got = append(got, rule.Certificate)
+			if rule.MinPercentage != float64(tt.uni.MinGrade) {
+				t.Errorf("%s rule %+v, want the minimum grade %d", tt.uni.Type, rule, tt.uni.MinGrade)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s certificates = %v, want %v", tt.uni.Type, got, tt.want)
+		}
+	}
+
+	explicit := []models.AdmissionRule{{Certificate: models.CertificateIB, MinScore: 30, ScoreScale: 45}}
+	if got := AdmissionRules(models.University{Type: "private", MinGrade: 80, Admission: explicit}); !reflect.DeepEqual(got, explicit) {
+		t.Errorf("explicit rules = %+v, want %+v", got, explicit)
+	}
+}
+
+func TestFindRule(t *testing.T) {
+	thanawiya := models.AdmissionRule{Certificate: models.CertificateThanawiya, MinPercentage: 80}
+	american := models.AdmissionRule{Certificate: models.CertificateAmerican, MinScore: 1200, ScoreScale: 1600}
+	international := models.AdmissionRule{Certificate: models.CertificateInternational, MinPercentage: 90}
+	igcse := models.AdmissionRule{Certificate: models.CertificateIGCSE, MinPercentage: 85}
+	ib := models.AdmissionRule{Certificate: models.CertificateIB, MinPercentage: 75}
+
+	tests := []struct {
+		name        string
+		rules       []models.AdmissionRule
+		certificate string
+		want        models.AdmissionRule
+		found       bool
+	}{
+		{"exact", []models.AdmissionRule{thanawiya, american, international}, models.CertificateAmerican, american, true},
+		{"specific falls back to generic", []models.AdmissionRule{thanawiya, igcse, international}, models.CertificateIB, international, true},
+		{"specific rule over generic", []models.AdmissionRule{international, igcse}, models.CertificateIGCSE, igcse, true},
+		{"specific does not use another specific", []models.AdmissionRule{thanawiya, igcse}, models.CertificateIB, models.AdmissionRule{}, false},
+		{"generic uses its own rule", []models.AdmissionRule{igcse, international}, models.CertificateInternational, international, true},
+		{"generic takes the most lenient", []models.AdmissionRule{thanawiya, igcse, ib}, models.CertificateInternational, ib, true},
+		{"national not accepted", []models.AdmissionRule{thanawiya, international}, models.CertificateAzhari, models.AdmissionRule{}, false},
+		{"no rules", nil, models.CertificateThanawiya, models.AdmissionRule{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := findRule(tt.rules, tt.certificate)
+			if got != tt.want || found != tt.found {
+				t.Errorf("findRule(%s) = %+v, %v; want %+v, %v", tt.certificate, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestCheckRule(t *testing.T) {
+	grade := func(v int) *int { return &v }
+	score := func(v float64) *float64 { return &v }
+	percentage := models.AdmissionRule{Certificate: models.CertificateThanawiya, MinPercentage: 80}
+	sat := models.AdmissionRule{Certificate: models.CertificateAmerican, MinScore: 1200, ScoreScale: 1600}
+	ib := models.AdmissionRule{Certificate: models.CertificateIB, MinPercentage: 70, ScoreScale: 45}
+
+	tests := []struct {
+		name   string
+		rule   models.AdmissionRule
+		grade  *int
+		score  *float64
+		ok     bool
+		reason string
+	}{
+		{"percentage met", percentage, grade(85), nil, true, "meets thanawiya minimum 80%"},
+		{"percentage missed", percentage, grade(75), nil, false, "requires 80% for thanawiya, you have 75.0%"},
+		{"no grade given", percentage, nil, nil, true, "thanawiya minimum 80%"},
+		{"score met", sat, nil, score(1300), true, "meets american minimum score 1200 of 1600"},
+		{"score missed", sat, grade(99), score(1100), false, "requires a american score of 1200, you have 1100"},
+		{"score from percentage missed", sat, grade(70), nil, false, "requires the equivalent of 75.0% for american, you have 70.0%"},
+		{"score from percentage met", sat, grade(80), nil, true, "meets american minimum score 1200 of 1600"},
+		{"score without a scale", models.AdmissionRule{Certificate: models.CertificateIB, MinScore: 28}, grade(90), nil,
+			true, "ib minimum score 28"},
+		{"percentage from score", ib, nil, score(36), true, "meets ib minimum 70%"},
+		{"percentage from score missed", ib, grade(90), score(27), false, "requires 70% for ib, you have 60.0%"},
+		{"no minimums", models.AdmissionRule{Certificate: models.CertificateIGCSE}, grade(50), nil, true, "igcse accepted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := checkRule(tt.rule, tt.grade, tt.score)
+			if ok != tt.ok || reason != tt.reason {
+				t.Errorf("checkRule = %v, %q; want %v, %q", ok, reason, tt.ok, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCheckEligibilitySample(t *testing.T) {
+	unis, err := LoadUniversities(os.DirFS("testdata/universities"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+	grade := func(v int) *int { return &v }
+	score := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name         string
+		id           string
+		certificate  string
+		grade        *int
+		score        *float64
+		eligible     bool
+		minimum      float64 // MinScore when the rule has one, else MinPercentage
+		reason       string
+		facultyRules []bool // eligibility for each faculty with its own rules
+	}{
+		{"SAT score", "4", models.CertificateAmerican, nil, score(1300), true, 1200, "meets american minimum score 1200 of 1600", nil},
+		{"IB score", "4", models.CertificateIB, nil, score(27), false, 28, "requires a ib score of 28, you have 27", nil},
+		{"certificate not accepted", "4", models.CertificateAzhari, grade(99), nil, false, 0, "does not accept azhari certificates", nil},
+		{"IGCSE under the international rule", "5", models.CertificateIGCSE, grade(90), nil, true, 88, "meets international minimum 88%", nil},
+		{"IGCSE under it missed", "5", models.CertificateIGCSE, grade(87), nil, false, 88, "requires 88% for international, you have 87.0%", nil},
+		{"university only", "1", models.CertificateThanawiya, grade(90), nil, true, 85, "meets thanawiya minimum 85%", []bool{false}},
+		{"university and faculty", "1", models.CertificateThanawiya, grade(96), nil, true, 85, "meets thanawiya minimum 85%", []bool{true}},
+		{"neither", "2", models.CertificateThanawiya, grade(80), nil, false, 83, "requires 83% for thanawiya, you have 80.0%", []bool{false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckEligibility(byID[tt.id], tt.certificate, tt.grade, tt.score)
+			minimum := got.MinPercentage
+			if got.MinScore > 0 {
+				minimum = got.MinScore
+			}
+			if got.Eligible != tt.eligible || got.Certificate != tt.certificate || minimum != tt.minimum || got.Reason != tt.reason {
+				t.Errorf("CheckEligibility = %+v; want eligible %v at %g: %q", got, tt.eligible, tt.minimum, tt.reason)
+			}
+			var faculties []bool
+			for _, fe := range got.Faculties {
+				faculties = append(faculties, fe.Eligible)
+			}
+			if !reflect.DeepEqual(faculties, tt.facultyRules) {
+				t.Errorf("faculty eligibility = %v, want %v", faculties, tt.facultyRules)
+			}
+		})
+	}
+}
+
+// TestCheckEligibilityThroughFaculty admits a student to one faculty whose
+// rules are more lenient than the university's
+func TestCheckEligibilityThroughFaculty(t *testing.T) {
+	uni := models.University{ID: "9", Type: "azhar", MinGrade: 70,
+		DetailedFaculties: map[string]models.Faculty{
+			"اللغات والترجمة": {NameEn: "Languages and Translation", Admission: []models.AdmissionRule{
+				{Certificate: models.CertificateInternational, MinPercentage: 60},
+			}},
+			"الطب": {NameEn: "Medicine", Admission: []models.AdmissionRule{
+				{Certificate: models.CertificateAzhari, MinPercentage: 95},
+			}},
+		}}
+	grade := 65
+	got := CheckEligibility(uni, models.CertificateIGCSE, &grade, nil)
+	want := models.Eligibility{
+		Eligible:    true,
+		Certificate: models.CertificateIGCSE,
+		Reason:      "eligible for Languages and Translation only",
+		Faculties: []models.FacultyEligibility{
+			{Faculty: "الطب", FacultyEn: "Medicine", Reason: "does not accept igcse certificates"},
+			{Faculty: "اللغات والترجمة", FacultyEn: "Languages and Translation", Eligible: true, MinPercentage: 60,
+				Reason: "meets international minimum 60%"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckEligibility = %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"roadtouniversities/models"
+)
+
+// calendarDir is the seed directory holding the admissions calendar
+const calendarDir = "calendar"
+
+// LoadCalendar reads the admissions calendar from the .json, .yaml and .yml
+// files in the calendar directory of fsys, in file order. Events must name
+// universities of unis and faculties of the faculty catalogue; every problem
+// found is returned as SeedErrors. Without the directory the calendar is
+// empty.
+func LoadCalendar(fsys fs.FS, unis []models.University, faculties *FacultyCatalog) ([]models.AdmissionEvent, error) {
+	names, err := seedFiles(fsys, calendarDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(unis))
+	for _, uni := range unis {
+		known[uni.ID] = true
+	}
+
+	var result []models.AdmissionEvent
+	var errs SeedErrors
+	seen := make(map[string]string)
+
+	for _, name := range names {
+		records, fileErr := readSeedFile(fsys, name, "an event")
+		if fileErr != nil {
+			errs = append(errs, *fileErr)
+			continue
+		}
+
+		for i, raw := range records {
+			var e models.AdmissionEvent
+			if recErr := decodeRecord(raw, &e); recErr != nil {
+				recErr.File, recErr.Record, recErr.ID = name, i, recordID(raw)
+				errs = append(errs, *recErr)
+				continue
+			}
+
+			if err := checkAdmissionEvent(e, known, faculties); err != nil {
+				var verr ValidationError
+				if !errors.As(err, &verr) {
+					errs = append(errs, SeedError{File: name, Record: i, ID: e.ID, Msg: err.Error()})
+					continue
+				}
+				for _, fe := range verr {
+					errs = append(errs, SeedError{File: name, Record: i, ID: e.ID, Field: fe.Field, Msg: fe.Message})
+				}
+				continue
+			}
+
+			if first, dup := seen[e.ID]; dup {
+				errs = append(errs, SeedError{File: name, Record: i, ID: e.ID, Field: "id", Msg: "duplicates a record in " + first})
+				continue
+			}
+			seen[e.ID] = name
+			result = append(result, e)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// CheckAdmissionEvent validates e and checks that its university is one of
+// unis and its faculty is in the faculty catalogue, returning every problem
+// as a ValidationError
+func CheckAdmissionEvent(e models.AdmissionEvent, unis []models.University, faculties *FacultyCatalog) error {
+	known := make(map[string]bool, len(unis))
+	for _, uni := range unis {
+		known[uni.ID] = true
+	}
+	return checkAdmissionEvent(e, known, faculties)
+}
+
+// checkAdmissionEvent is CheckAdmissionEvent with the university IDs indexed
+func checkAdmissionEvent(e models.AdmissionEvent, known map[string]bool, faculties *FacultyCatalog) error {
+	var verr ValidationError
+	if err := ValidateAdmissionEvent(e); err != nil && !errors.As(err, &verr) {
+		return err
+	}
+	if e.UniversityID != "" && !known[e.UniversityID] {
+		verr = append(verr, FieldError{Field: "universityId", Message: fmt.Sprintf("unknown university %q", e.UniversityID)})
+	}
+	if _, found := faculties.Discipline(e.Faculty); e.Faculty != "" && !found {
+		verr = append(verr, FieldError{Field: "faculty", Message: fmt.Sprintf("unknown faculty %q", e.Faculty)})
+	}
+	if len(verr) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// CalendarQuery selects admission events; empty fields match everything
+type CalendarQuery struct {
+	UniversityID string // also keeps nationwide events
+	FacultyID    string // also keeps events for the whole university
+	Kind         string
+	From         string // YYYY-MM-DD; keeps events ending on or after it
+	To           string // YYYY-MM-DD; keeps events starting on or before it
+}
+
+// Calendar returns the events query selects, named after their university
+// and faculty and ordered by start date, then end date
+func Calendar(events []models.AdmissionEvent, unis []models.University, faculties *FacultyCatalog, query CalendarQuery) []models.CalendarEvent {
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	result := []models.CalendarEvent{}
+	for _, e := range events {
+		end := firstNonEmpty(e.End, e.Start)
+		if (query.UniversityID != "" && e.UniversityID != "" && e.UniversityID != query.UniversityID) ||
+			(query.FacultyID != "" && e.Faculty != "" && e.Faculty != query.FacultyID) ||
+			(query.Kind != "" && e.Kind != query.Kind) ||
+			(query.From != "" && end < query.From) ||
+			(query.To != "" && e.Start > query.To) {
+			continue
+		}
+
+		ce := models.CalendarEvent{AdmissionEvent: e}
+		if uni, found := byID[e.UniversityID]; found {
+			ce.University, ce.UniversityEn = uni.Name, uni.NameEn
+		}
+		if d, found := faculties.Discipline(e.Faculty); found {
+			ce.FacultyName, ce.FacultyNameEn = d.Name, d.NameEn
+		}
+		result = append(result, ce)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return firstNonEmpty(a.End, a.Start) < firstNonEmpty(b.End, b.Start)
+	})
+	return result
+}
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestDefaultSeedHasEmptyCalendar(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	events, err := LoadCalendar(DefaultSeed(), unis, faculties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("default calendar holds %d events, want none", len(events))
+	}
+}
+
+func TestCheckAdmissionEvent(t *testing.T) {
+	unis := []models.University{{ID: "1"}}
+	valid := func(edit func(*models.AdmissionEvent)) models.AdmissionEvent {
+		e := models.AdmissionEvent{ID: "open-day", UniversityID: "1", Faculty: "medicine", Kind: models.EventInterview,
+			Title: "يوم مفتوح", TitleEn: "Open day", Start: "2027-07-01"}
+		edit(&e)
+		return e
+	}
+	tests := []struct {
+		name   string
+		event  models.AdmissionEvent
+		fields []string
+	}{
+		{"valid", valid(func(e *models.AdmissionEvent) {}), nil},
+		{"nationwide", valid(func(e *models.AdmissionEvent) { e.UniversityID, e.Faculty = "", "" }), nil},
+		{"unknown university", valid(func(e *models.AdmissionEvent) { e.UniversityID = "9" }), []string{"universityId"}},
+		{"unknown faculty", valid(func(e *models.AdmissionEvent) { e.Faculty = "astrology" }), []string{"faculty"}},
+		{"end before start", valid(func(e *models.AdmissionEvent) { e.End = "2027-06-30" }), []string{"end"}},
+		{"every problem", valid(func(e *models.AdmissionEvent) { e.Kind, e.UniversityID = "party", "9" }), []string{"kind", "universityId"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAdmissionEvent(tt.event, unis, testFacultyCatalog())
+			var verr ValidationError
+			if err != nil && !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+			var fields []string
+			for _, fe := range verr {
+				fields = append(fields, fe.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Fatalf("invalid fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
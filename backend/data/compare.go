@@ -0,0 +1,215 @@
+package data
+
+import (
+	"math"
+
+	"roadtouniversities/models"
+)
+
+// Bounds on the number of universities compared at once
+const (
+	MinCompared = 2
+	MaxCompared = 4
+)
+
+// comparisonMetrics lists the measures compared, in response order. value
+// reports false when a university does not publish the measure.
+var comparisonMetrics = []struct {
+	key    string
+	label  string
+	better string
+	value  func(uni models.University) (float64, bool)
+}{
+	{"feesMin", "Minimum annual fees", models.BetterLower, func(u models.University) (float64, bool) {
+		return float64(u.Fees.Min), u.Fees.Max > 0
+	}},
+	{"feesMax", "Maximum annual fees", models.BetterLower, func(u models.University) (float64, bool) {
+		return float64(u.Fees.Max), u.Fees.Max > 0
+	}},
+	{"rating", "Rating", models.BetterHigher, func(u models.University) (float64, bool) {
+		return u.Rating, u.Rating > 0
+	}},
+	// Neither direction is better: selective universities are harder to
+	// enter, open ones less sought after
+	{"acceptanceRate", "Acceptance rate", "", func(u models.University) (float64, bool) {
+		return float64(u.AcceptanceRate), u.AcceptanceRate > 0
+	}},
+	{"employmentRate", "Employment rate", models.BetterHigher, func(u models.University) (float64, bool) {
+		return float64(u.EmploymentRate), u.EmploymentRate > 0
+	}},
+	{"minGrade", "Minimum grade", models.BetterLower, func(u models.University) (float64, bool) {
+		return float64(u.MinGrade), u.MinGrade > 0
+	}},
+	{"students", "Students", "", func(u models.University) (float64, bool) {
+		return float64(u.Students), u.Students > 0
+	}},
+	{"established", "Established", "", func(u models.University) (float64, bool) {
+		return float64(u.Established), u.Established > 0
+	}},
+}
+
+// CompareUniversities sets unis side by side in the given order: each metric
+// with the universities best in it, the faculties all of them offer, those
+// only one offers, and the fees of faculties that at least two publish.
+// Faculties are matched through the faculty catalogue.
+func CompareUniversities(unis []models.University, faculties *FacultyCatalog) models.Comparison {
+	cmp := models.Comparison{
+		Universities:    make([]models.ComparedUniversity, len(unis)),
+		Metrics:         make([]models.ComparisonMetric, len(comparisonMetrics)),
+		CommonFaculties: []models.ComparedFaculty{},
+		UniqueFaculties: make(map[string][]models.ComparedFaculty, len(unis)),
+		FacultyFees:     []models.FacultyFeeComparison{},
+	}
+
+	for i, uni := range unis {
+		cmp.Universities[i] = models.ComparedUniversity{
+			ID:          uni.ID,
+			Name:        uni.Name,
+			NameEn:      uni.NameEn,
+			Type:        uni.Type,
+			Region:      uni.Region,
+			Location:    uni.Location,
+			LocationEn:  uni.LocationEn,
+			Established: uni.Established,
+			Students:    uni.Students,
+		}
+		cmp.UniqueFaculties[uni.ID] = []models.ComparedFaculty{}
+	}
+
+	for m, metric := range comparisonMetrics {
+		values := make([]*float64, len(unis))
+		for i, uni := range unis {
+			if v, ok := metric.value(uni); ok {
+				values[i] = &v
+			}
+		}
+		cmp.Metrics[m] = models.ComparisonMetric{
+			Key:    metric.key,
+			Label:  metric.label,
+			Better: metric.better,
+			Values: values,
+			Best:   bestOf(unis, values, metric.better),
+		}
+	}
+
+	compareFaculties(&cmp, unis, faculties)
+	return cmp
+}
+
+// bestOf returns the IDs of the universities holding the best value, or none
+// when fewer than two universities have a value to compare
+func bestOf(unis []models.University, values []*float64, better string) []string {
+	best := []string{}
+	if better == "" {
+		return best
+	}
+
+	target, count := math.Inf(1), 0
+	if better == models.BetterHigher {
+		target = math.Inf(-1)
+	}
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		count++
+		if (better == models.BetterHigher && *v > target) || (better == models.BetterLower && *v < target) {
+			target = *v
+		}
+	}
+	if count < 2 {
+		return best
+	}
+	for i, v := range values {
+		if v != nil && *v == target {
+			best = append(best, unis[i].ID)
+		}
+	}
+	return best
+}
+
+// offeredFaculty is a faculty as offered by the compared universities
+type offeredFaculty struct {
+	models.ComparedFaculty
+	offeredBy []bool
+	fees      []*models.FeesRange
+}
+
+// compareFaculties fills the common, unique and fee comparisons, keeping
+// faculties in the order they first appear
+func compareFaculties(cmp *models.Comparison, unis []models.University, faculties *FacultyCatalog) {
+	var order []string
+	byID := make(map[string]*offeredFaculty)
+
+	for i, uni := range unis {
+		faculties.eachFaculty([]models.University{uni}, func(id string, f universityFaculty) {
+			of, found := byID[id]
+			if !found {
+				of = &offeredFaculty{
+					ComparedFaculty: models.ComparedFaculty{ID: id, Name: f.name, NameEn: f.nameEn},
+					offeredBy:       make([]bool, len(unis)),
+					fees:            make([]*models.FeesRange, len(unis)),
+				}
+				if d, found := faculties.Discipline(id); found {
+					of.Name, of.NameEn = d.Name, d.NameEn
+				}
+				byID[id] = of
+				order = append(order, id)
+			}
+			of.offeredBy[i] = true
+			if detailed, found := uni.DetailedFaculties[f.name]; found && detailed.AnnualFees.Max > 0 {
+				fees := detailed.AnnualFees
+				of.fees[i] = &fees
+			}
+		})
+	}
+
+	for _, id := range order {
+		of := byID[id]
+		offering := 0
+		for _, offered := range of.offeredBy {
+			if offered {
+				offering++
+			}
+		}
+		switch {
+		case offering == len(unis):
+			cmp.CommonFaculties = append(cmp.CommonFaculties, of.ComparedFaculty)
+		case offering == 1:
+			for i, offered := range of.offeredBy {
+				if offered {
+					cmp.UniqueFaculties[unis[i].ID] = append(cmp.UniqueFaculties[unis[i].ID], of.ComparedFaculty)
+				}
+			}
+		}
+
+		if fc, ok := compareFacultyFees(of, unis); ok {
+			cmp.FacultyFees = append(cmp.FacultyFees, fc)
+		}
+	}
+}
+
+// compareFacultyFees compares a faculty's fees by their upper bounds when at
+// least two universities publish them
+func compareFacultyFees(of *offeredFaculty, unis []models.University) (models.FacultyFeeComparison, bool) {
+	upper := make([]*float64, len(unis))
+	lowest, highest, count := math.MaxInt, 0, 0
+	for i, fees := range of.fees {
+		if fees == nil {
+			continue
+		}
+		v := float64(fees.Max)
+		upper[i] = &v
+		lowest, highest = min(lowest, fees.Max), max(highest, fees.Max)
+		count++
+	}
+	if count < 2 {
+		return models.FacultyFeeComparison{}, false
+	}
+	return models.FacultyFeeComparison{
+		ComparedFaculty: of.ComparedFaculty,
+		Fees:            of.fees,
+		Cheapest:        bestOf(unis, upper, models.BetterLower),
+		Difference:      highest - lowest,
+	}, true
+}
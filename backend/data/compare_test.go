@@ -0,0 +1,109 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func compareCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo",
+			Fees: models.FeesRange{Min: 1000, Max: 3000}, Rating: 4, AcceptanceRate: 30, EmploymentRate: 80, MinGrade: 85, Students: 1000,
+			Faculties: []string{"الطب", "الهندسة"}, FacultiesEn: []string{"Medicine", "Engineering"},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {NameEn: "Engineering", AnnualFees: models.FeesRange{Min: 2000, Max: 3000}},
+			}},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "private", Region: "giza",
+			Fees: models.FeesRange{Min: 1000, Max: 5000}, Rating: 4, MinGrade: 80,
+			Faculties: []string{"كلية الهندسة", "التجارة"}, FacultiesEn: []string{"Faculty of Engineering", "Commerce"},
+			DetailedFaculties: map[string]models.Faculty{
+				"كلية الهندسة": {NameEn: "Faculty of Engineering", AnnualFees: models.FeesRange{Min: 4000, Max: 6000}},
+			}},
+		{ID: "3", Name: "جامعة ج", NameEn: "University C", Type: "public", Region: "alexandria",
+			Faculties: []string{"الهندسة", "العلوم"}, FacultiesEn: []string{"Engineering", "Science"}},
+	}
+}
+
+func TestCompareUniversities(t *testing.T) {
+	cmp := CompareUniversities(compareCatalogue(), testFacultyCatalog())
+
+	var ids []string
+	for _, uni := range cmp.Universities {
+		ids = append(ids, uni.ID)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("universities = %v, want %v in the given order", ids, want)
+	}
+
+	value := func(v float64) *float64 { return &v }
+	tests := []struct {
+		key    string
+		better string
+		values []*float64
+		best   []string
+	}{
+		{"feesMin", models.BetterLower, []*float64{value(1000), value(1000), nil}, []string{"1", "2"}},
+		{"feesMax", models.BetterLower, []*float64{value(3000), value(5000), nil}, []string{"1"}},
+		{"rating", models.BetterHigher, []*float64{value(4), value(4), nil}, []string{"1", "2"}},
+		{"acceptanceRate", "", []*float64{value(30), nil, nil}, []string{}},
+		// Only one university publishes it, so there is nothing to compare
+		{"employmentRate", models.BetterHigher, []*float64{value(80), nil, nil}, []string{}},
+		{"minGrade", models.BetterLower, []*float64{value(85), value(80), nil}, []string{"2"}},
+		{"students", "", []*float64{value(1000), nil, nil}, []string{}},
+		{"established", "", []*float64{nil, nil, nil}, []string{}},
+	}
+	if len(cmp.Metrics) != len(tests) {
+		t.Fatalf("%d metrics, want %d", len(cmp.Metrics), len(tests))
+	}
+	for i, tt := range tests {
+		got := cmp.Metrics[i]
+		if got.Key != tt.key || got.Better != tt.better || !reflect.DeepEqual(got.Values, tt.values) || !reflect.DeepEqual(got.Best, tt.best) {
+			t.Errorf("metric %d = %s (%q) best %v; want %s (%q) best %v", i, got.Key, got.Better, got.Best, tt.key, tt.better, tt.best)
+		}
+	}
+
+	engineering := models.ComparedFaculty{ID: "engineering", Name: "الهندسة", NameEn: "Engineering"}
+	if want := []models.ComparedFaculty{engineering}; !reflect.DeepEqual(cmp.CommonFaculties, want) {
+		t.Errorf("common faculties = %+v, want %+v", cmp.CommonFaculties, want)
+	}
+	unique := map[string][]models.ComparedFaculty{
+		"1": {{ID: "medicine", Name: "الطب", NameEn: "Medicine"}},
+		"2": {{ID: "commerce", Name: "التجارة", NameEn: "Commerce"}},
+		"3": {{ID: "science", Name: "العلوم", NameEn: "Science"}},
+	}
+	if !reflect.DeepEqual(cmp.UniqueFaculties, unique) {
+		t.Errorf("unique faculties = %+v, want %+v", cmp.UniqueFaculties, unique)
+	}
+
+	fees := []models.FacultyFeeComparison{{
+		ComparedFaculty: engineering,
+		Fees:            []*models.FeesRange{{Min: 2000, Max: 3000}, {Min: 4000, Max: 6000}, nil},
+		Cheapest:        []string{"1"},
+		Difference:      3000,
+	}}
+	if !reflect.DeepEqual(cmp.FacultyFees, fees) {
+		t.Errorf("faculty fees = %+v, want %+v", cmp.FacultyFees, fees)
+	}
+}
+
+// TestCompareUniversitiesNothingShared compares universities with no faculty
+// or fees in common
+func TestCompareUniversitiesNothingShared(t *testing.T) {
+	cmp := CompareUniversities([]models.University{
+		{ID: "1", Faculties: []string{"الطب"}, FacultiesEn: []string{"Medicine"}},
+		{ID: "2", Faculties: []string{"التجارة"}, FacultiesEn: []string{"Commerce"}},
+	}, testFacultyCatalog())
+	if len(cmp.CommonFaculties) != 0 || len(cmp.FacultyFees) != 0 || cmp.CommonFaculties == nil || cmp.FacultyFees == nil {
+		t.Errorf("common %+v and fees %+v, want empty lists", cmp.CommonFaculties, cmp.FacultyFees)
+	}
+	for _, metric := range cmp.Metrics {
+		if metric.Best == nil || len(metric.Best) != 0 {
+			t.Errorf("%s best = %v, want an empty list", metric.Key, metric.Best)
+		}
+	}
+	if len(cmp.UniqueFaculties["1"]) != 1 || len(cmp.UniqueFaculties["2"]) != 1 {
+		t.Errorf("unique faculties = %+v, want one each", cmp.UniqueFaculties)
+	}
+}
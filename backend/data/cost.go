@@ -0,0 +1,210 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+var (
+	// ErrInvalidCostRequest is returned for out-of-range cost overrides
+	ErrInvalidCostRequest = errors.New("invalid cost request")
+	// ErrUnknownProgram is returned when the faculty or department is not
+	// offered by the university
+	ErrUnknownProgram = errors.New("unknown program")
+)
+
+// Bounds on the length of study costed
+const (
+	defaultStudyYears = 4
+	maxStudyYears     = 10
+)
+
+// EstimateCost works out the year-by-year cost of studying at uni. Tuition
+// is the department's fees, else the upper bound of the faculty's, then the
+// university's, and rises by the annual increase each year; the other costs
+// are at today's prices. The length of study is the department's duration,
+// the longest of the faculty's departments, or four years. Fees quoted in
+// other currencies are converted to EGP at the rates in effect on the given
+// day; USD totals are added for tuition billed in dollars and on request.
+// When the request applies scholarships, the one taking the most off the
+// first year's tuition is deducted from every year's.
+func EstimateCost(uni models.University, faculties *FacultyCatalog, scholarships []models.Scholarship, rates *ExchangeRates, on time.Time, req models.CostEstimateRequest) (models.CostEstimate, error) {
+	var est models.CostEstimate
+
+	if req.Years < 0 || req.Years > maxStudyYears {
+		return est, fmt.Errorf("%w: years must be between 1 and %d", ErrInvalidCostRequest, maxStudyYears)
+	}
+	if req.AnnualIncrease != nil && (*req.AnnualIncrease < 0 || *req.AnnualIncrease > 100) {
+		return est, fmt.Errorf("%w: annualIncrease must be between 0 and 100", ErrInvalidCostRequest)
+	}
+	if (req.Housing != nil && *req.Housing < 0) || (req.Transport != nil && *req.Transport < 0) {
+		return est, fmt.Errorf("%w: housing and transport must not be negative", ErrInvalidCostRequest)
+	}
+	if req.Department != "" && req.Faculty == "" {
+		return est, fmt.Errorf("%w: department requires faculty", ErrInvalidCostRequest)
+	}
+	if req.Grade != nil && (*req.Grade < 0 || *req.Grade > 100) {
+		return est, fmt.Errorf("%w: grade must be between 0 and 100", ErrInvalidCostRequest)
+	}
+	usdRate, hasUSD := rates.Rate(models.CurrencyUSD, on)
+	if req.IncludeUSD && !hasUSD {
+		return est, fmt.Errorf("%w for %s on %s", ErrNoExchangeRate, models.CurrencyUSD, on.Format(dateLayout))
+	}
+
+	var costs models.CostModel
+	if uni.Costs != nil {
+		costs = *uni.Costs
+	}
+	if req.AnnualIncrease != nil {
+		costs.AnnualIncrease = *req.AnnualIncrease
+	}
+	if req.Housing != nil {
+		costs.Housing = *req.Housing
+	}
+	if req.Transport != nil {
+		costs.Transport = *req.Transport
+	}
+
+	inEGP, err := rates.ConvertUniversity(uni, models.CurrencyEGP, on)
+	if err != nil {
+		return est, err
+	}
+	est = models.CostEstimate{
+		UniversityID:   uni.ID,
+		University:     uni.Name,
+		UniversityEn:   uni.NameEn,
+		Years:          defaultStudyYears,
+		AnnualTuition:  inEGP.Fees.Max,
+		AnnualIncrease: costs.AnnualIncrease,
+	}
+	terms, err := costProgram(&est, uni, inEGP, faculties, req)
+	if err != nil {
+		return models.CostEstimate{}, err
+	}
+	est.BilledIn = terms.currency
+	if req.Years > 0 {
+		est.Years = req.Years
+	}
+
+	var scholarship models.Scholarship
+	var aided bool
+	if req.ApplyScholarships {
+		facultyID := ""
+		if est.Faculty != "" {
+			facultyID = faculties.FacultyID(est.Faculty, est.FacultyEn)
+		}
+		eligible := eligibleScholarships(scholarships, uni.ID, req.Grade, on)
+		var discount int
+		scholarship, discount, aided, err = rates.bestScholarship(eligible, facultyID, est.AnnualTuition, models.CurrencyEGP, on)
+		if err != nil {
+			return models.CostEstimate{}, err
+		}
+		if aided {
+			applied := appliedScholarship(scholarship, discount)
+			applied.Faculty = facultyID
+			est.Scholarship = &applied
+		}
+	}
+
+	convert := hasUSD && (req.IncludeUSD || est.BilledIn == models.CurrencyUSD)
+	if convert {
+		est.ExchangeRate = usdRate
+	}
+
+	est.Breakdown = make([]models.YearCost, est.Years)
+	for i := range est.Breakdown {
+		year := models.YearCost{
+			Year:      i + 1,
+			Tuition:   int(math.Round(float64(est.AnnualTuition) * math.Pow(1+costs.AnnualIncrease/100, float64(i)))),
+			OtherFees: costs.OtherFees,
+			Housing:   costs.Housing,
+			Transport: costs.Transport,
+		}
+		if i == 0 {
+			year.Registration = costs.RegistrationFee
+		}
+		if aided {
+			if year.Discount, err = rates.scholarshipDiscount(scholarship, year.Tuition, models.CurrencyEGP, on); err != nil {
+				return models.CostEstimate{}, err
+			}
+		}
+		year.Total = year.Tuition + year.Registration + year.OtherFees + year.Housing + year.Transport - year.Discount
+		if convert {
+			year.TotalUSD = toUSD(year.Total, usdRate)
+		}
+		est.Breakdown[i] = year
+		est.Total += year.Total
+	}
+	if convert {
+		est.TotalUSD = toUSD(est.Total, usdRate)
+	}
+	return est, nil
+}
+
+// costProgram fills in the faculty and department of est with their tuition,
+// taken from inEGP, and length of study. It returns the terms the tuition is
+// quoted in.
+func costProgram(est *models.CostEstimate, uni, inEGP models.University, faculties *FacultyCatalog, req models.CostEstimateRequest) (feeTerms, error) {
+	terms := feeTerms{uni.Fees.Currency, uni.Fees.Period}.inherit(defaultFeeTerms)
+	if req.Faculty == "" {
+		return terms, nil
+	}
+	i := resolveFaculty(uni, faculties, req.Faculty)
+	if i < 0 {
+		return terms, fmt.Errorf("%w: %s does not offer %q", ErrUnknownProgram, uni.NameEn, req.Faculty)
+	}
+	est.Faculty = uni.Faculties[i]
+	if i < len(uni.FacultiesEn) {
+		est.FacultyEn = uni.FacultiesEn[i]
+	}
+
+	faculty, detailed := uni.DetailedFaculties[est.Faculty]
+	if !detailed {
+		if req.Department != "" {
+			return terms, fmt.Errorf("%w: no departments are listed for %q at %s", ErrUnknownProgram, req.Faculty, uni.NameEn)
+		}
+		return terms, nil
+	}
+	converted := inEGP.DetailedFaculties[est.Faculty]
+	facultyTerms := feeTerms{faculty.AnnualFees.Currency, faculty.AnnualFees.Period}.inherit(terms)
+	if faculty.AnnualFees.Max > 0 {
+		est.AnnualTuition, terms = converted.AnnualFees.Max, facultyTerms
+	}
+
+	if req.Department == "" {
+		longest := 0
+		for _, dept := range faculty.Departments {
+			longest = max(longest, leadingNumber(dept.DurationEn))
+		}
+		if longest > 0 {
+			est.Years = longest
+		}
+		return terms, nil
+	}
+
+	for j, dept := range faculty.Departments {
+		if !strings.EqualFold(strings.TrimSpace(req.Department), dept.NameEn) && strings.TrimSpace(req.Department) != dept.Name {
+			continue
+		}
+		est.Department, est.DepartmentEn = dept.Name, dept.NameEn
+		if dept.Fees > 0 {
+			est.AnnualTuition = converted.Departments[j].Fees
+			terms = feeTerms{dept.Currency, dept.Period}.inherit(facultyTerms)
+		}
+		if years := leadingNumber(dept.DurationEn); years > 0 {
+			est.Years = years
+		}
+		return terms, nil
+	}
+	return terms, fmt.Errorf("%w: %q has no department %q at %s", ErrUnknownProgram, req.Faculty, req.Department, uni.NameEn)
+}
+
+func toUSD(egp int, rate float64) *float64 {
+	usd := round2(float64(egp) / rate)
+	return &usd
+}
@@ -0,0 +1,170 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestDefaultSeedHasNoCosts(t *testing.T) {
+	unis, _ := seedCatalogue(t)
+	for _, uni := range unis {
+		if uni.Costs != nil {
+			t.Errorf("default seed gives %s costs %+v, want none", uni.ID, *uni.Costs)
+		}
+	}
+}
+
+// costUniversity prices in EGP, with engineering priced in USD
+func costUniversity() models.University {
+	return models.University{
+		ID: "5", Name: "جامعة", NameEn: "University", Type: "private", Region: "cairo",
+		Fees:        models.FeesRange{Min: 100000, Max: 200000},
+		Faculties:   []string{"الطب", "الهندسة"},
+		FacultiesEn: []string{"Medicine", "Engineering"},
+		DetailedFaculties: map[string]models.Faculty{
+			"الهندسة": {
+				NameEn:     "Engineering",
+				AnnualFees: models.FeesRange{Min: 3000, Max: 4000, Currency: "USD"},
+				Departments: []models.Department{
+					{Name: "العمارة", NameEn: "Architecture", DurationEn: "5 years", Fees: 5000},
+					{Name: "المدني", NameEn: "Civil", DurationEn: "4 years"},
+				},
+			},
+		},
+		Costs: &models.CostModel{AnnualIncrease: 10, RegistrationFee: 1000, OtherFees: 500, Housing: 2000},
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	num := func(v int) *int { return &v }
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name           string
+		req            models.CostEstimateRequest
+		years, tuition int
+		billedIn       string
+		total          int
+		usd            bool
+	}{
+		// 200000 rising 10% a year over four years, plus 1000 once and 2500
+		// a year
+		{"university fees", models.CostEstimateRequest{}, 4, 200000, "EGP", 939200, false},
+		{"faculty by ID", models.CostEstimateRequest{Faculty: "engineering"}, 5, 200000, "USD", 1234520, true},
+		{"department fees", models.CostEstimateRequest{Faculty: "الهندسة", Department: "architecture"}, 5, 250000, "USD", 1539775, true},
+		{"department without fees", models.CostEstimateRequest{Faculty: "Engineering", Department: "المدني"}, 4, 200000, "USD", 939200, true},
+		{"overrides", models.CostEstimateRequest{Years: 2, AnnualIncrease: pct(0), Housing: num(0)}, 2, 200000, "EGP", 402000, false},
+		{"USD on request", models.CostEstimateRequest{Faculty: "medicine", Years: 1, IncludeUSD: true}, 1, 200000, "EGP", 203500, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.UniversityID = "5"
+			est, err := EstimateCost(costUniversity(), testFacultyCatalog(), nil, testExchangeRates(), day("2025-06-01"), tt.req)
+			if err != nil {
+				t.Fatalf("EstimateCost: %v", err)
+			}
+			if est.Years != tt.years || len(est.Breakdown) != tt.years || est.AnnualTuition != tt.tuition || est.BilledIn != tt.billedIn {
+				t.Errorf("estimate = %d years (%d listed) at %d billed in %s; want %d at %d billed in %s",
+					est.Years, len(est.Breakdown), est.AnnualTuition, est.BilledIn, tt.years, tt.tuition, tt.billedIn)
+			}
+			if est.Total != tt.total {
+				t.Errorf("total = %d, want %d", est.Total, tt.total)
+			}
+			if got := est.TotalUSD != nil; got != tt.usd {
+				t.Errorf("USD total given = %v, want %v", got, tt.usd)
+			} else if tt.usd && (est.ExchangeRate != 50 || *est.TotalUSD != float64(tt.total)/50) {
+				t.Errorf("USD total = %v at %v, want %v at 50", *est.TotalUSD, est.ExchangeRate, float64(tt.total)/50)
+			}
+		})
+	}
+}
+
+func TestEstimateCostBreakdown(t *testing.T) {
+	est, err := EstimateCost(costUniversity(), testFacultyCatalog(), nil, testExchangeRates(), day("2025-06-01"),
+		models.CostEstimateRequest{UniversityID: "5", Years: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.YearCost{
+		{Year: 1, Tuition: 200000, Registration: 1000, OtherFees: 500, Housing: 2000, Total: 203500},
+		{Year: 2, Tuition: 220000, OtherFees: 500, Housing: 2000, Total: 222500},
+	}
+	for i, year := range est.Breakdown {
+		if year != want[i] {
+			t.Errorf("year %d = %+v, want %+v", i+1, year, want[i])
+		}
+	}
+}
+
+func TestEstimateCostScholarships(t *testing.T) {
+	scholarships := []models.Scholarship{
+		{ID: "half", Type: "merit", UniversityIDs: []string{"5"}, MinGrade: 90, DiscountPercent: 50},
+		{ID: "fixed", Type: "need", UniversityIDs: []string{"5"}, DiscountAmount: 30000},
+		{ID: "elsewhere", Type: "merit", UniversityIDs: []string{"6"}, DiscountPercent: 100},
+	}
+	grade := func(v float64) *float64 { return &v }
+	tests := []struct {
+		grade     *float64
+		want      string
+		discounts []int
+	}{
+		{grade(95), "half", []int{100000, 110000}},
+		{grade(85), "fixed", []int{30000, 30000}},
+		{nil, "fixed", []int{30000, 30000}},
+	}
+	for _, tt := range tests {
+		est, err := EstimateCost(costUniversity(), testFacultyCatalog(), scholarships, testExchangeRates(), day("2025-06-01"),
+			models.CostEstimateRequest{UniversityID: "5", Years: 2, ApplyScholarships: true, Grade: tt.grade})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if est.Scholarship == nil || est.Scholarship.ID != tt.want {
+			t.Errorf("grade %v: scholarship = %+v, want %s", tt.grade, est.Scholarship, tt.want)
+			continue
+		}
+		for i, year := range est.Breakdown {
+			if year.Discount != tt.discounts[i] || year.Total != year.Tuition+year.Registration+year.OtherFees+year.Housing-year.Discount {
+				t.Errorf("grade %v: year %d = %+v, want %d off", tt.grade, i+1, year, tt.discounts[i])
+			}
+		}
+	}
+
+	est, err := EstimateCost(costUniversity(), testFacultyCatalog(), scholarships, testExchangeRates(), day("2025-06-01"),
+		models.CostEstimateRequest{UniversityID: "5", Grade: grade(95)})
+	if err != nil || est.Scholarship != nil {
+		t.Errorf("without applyScholarships: scholarship = %+v, %v; want none", est.Scholarship, err)
+	}
+}
+
+func TestEstimateCostErrors(t *testing.T) {
+	num := func(v int) *int { return &v }
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name string
+		req  models.CostEstimateRequest
+		on   string
+		want error
+	}{
+		{"too many years", models.CostEstimateRequest{Years: 11}, "2025-06-01", ErrInvalidCostRequest},
+		{"negative years", models.CostEstimateRequest{Years: -1}, "2025-06-01", ErrInvalidCostRequest},
+		{"increase over 100", models.CostEstimateRequest{AnnualIncrease: pct(101)}, "2025-06-01", ErrInvalidCostRequest},
+		{"negative housing", models.CostEstimateRequest{Housing: num(-1)}, "2025-06-01", ErrInvalidCostRequest},
+		{"negative transport", models.CostEstimateRequest{Transport: num(-1)}, "2025-06-01", ErrInvalidCostRequest},
+		{"grade over 100", models.CostEstimateRequest{Grade: pct(101)}, "2025-06-01", ErrInvalidCostRequest},
+		{"department without faculty", models.CostEstimateRequest{Department: "Civil"}, "2025-06-01", ErrInvalidCostRequest},
+		{"faculty not offered", models.CostEstimateRequest{Faculty: "commerce"}, "2025-06-01", ErrUnknownProgram},
+		{"no departments listed", models.CostEstimateRequest{Faculty: "medicine", Department: "Surgery"}, "2025-06-01", ErrUnknownProgram},
+		{"department not offered", models.CostEstimateRequest{Faculty: "engineering", Department: "Mining"}, "2025-06-01", ErrUnknownProgram},
+		{"no USD rate yet", models.CostEstimateRequest{IncludeUSD: true}, "2022-06-01", ErrNoExchangeRate},
+		{"faculty fees without a rate", models.CostEstimateRequest{Faculty: "engineering"}, "2022-06-01", ErrNoExchangeRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.UniversityID = "5"
+			if _, err := EstimateCost(costUniversity(), testFacultyCatalog(), nil, testExchangeRates(), day(tt.on), tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("EstimateCost = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// ErrNoExchangeRate is returned when fees are converted from or to a
+// currency without an exchange rate in effect
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// exchangeRatesFile is the seed file holding the exchange-rate table
+const exchangeRatesFile = "exchange-rates.csv"
+
+// Exchange-rate CSV columns, all required
+const (
+	colCurrency      = "currency"
+	colRate          = "rate"
+	colEffectiveFrom = "effective_from"
+)
+
+const dateLayout = "2006-01-02"
+
+// LoadExchangeRates reads exchange-rates.csv at the root of fsys. A directory
+// without the file yields an empty table.
+func LoadExchangeRates(fsys fs.FS) ([]models.ExchangeRate, error) {
+	f, err := fsys.Open(exchangeRatesFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadExchangeRates(f, exchangeRatesFile)
+}
+
+// ReadExchangeRates parses an exchange-rate CSV document with one row per
+// currency and effective date:
+//
+//	currency,rate,effective_from
+//	USD,48.60,2024-03-06
+//
+// Rates are in EGP per unit. Every problem found is returned as SeedErrors,
+// with Record holding the CSV line number.
+func ReadExchangeRates(r io.Reader, name string) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, SeedErrors{{File: name, Record: -1, Msg: "empty file"}}
+	}
+	if err != nil {
+		return nil, SeedErrors{{File: name, Record: -1, Msg: err.Error()}}
+	}
+
+	columns := make(map[string]int, len(header))
+	var errs SeedErrors
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		if col != colCurrency && col != colRate && col != colEffectiveFrom {
+			errs = append(errs, SeedError{File: name, Record: -1, Field: col, Msg: "unknown column"})
+			continue
+		}
+		columns[col] = i
+	}
+	for _, col := range []string{colCurrency, colRate, colEffectiveFrom} {
+		if _, found := columns[col]; !found {
+			errs = append(errs, SeedError{File: name, Record: -1, Field: col, Msg: "missing column"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var result []models.ExchangeRate
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line := -1
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			errs = append(errs, SeedError{File: name, Record: line, Msg: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(col string) string {
+			if i := columns[col]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rate := models.ExchangeRate{
+			Currency:      strings.ToUpper(field(colCurrency)),
+			EffectiveFrom: field(colEffectiveFrom),
+		}
+		fail := func(col, msg string) {
+			errs = append(errs, SeedError{File: name, Record: line, ID: rate.Currency, Field: col, Msg: msg})
+		}
+
+		valid := true
+		if !models.IsCurrencyCode(rate.Currency) || rate.Currency == models.CurrencyEGP {
+			fail(colCurrency, "must be an ISO 4217 code other than EGP")
+			valid = false
+		}
+		rate.Rate, err = strconv.ParseFloat(field(colRate), 64)
+		if err != nil || rate.Rate <= 0 || math.IsInf(rate.Rate, 0) {
+			fail(colRate, "must be a positive number of EGP")
+			valid = false
+		}
+		if _, err := time.Parse(dateLayout, rate.EffectiveFrom); err != nil {
+			fail(colEffectiveFrom, "must be a date such as 2024-03-06")
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+
+		key := rate.Currency + "\x00" + rate.EffectiveFrom
+		if first, dup := seen[key]; dup {
+			fail(colEffectiveFrom, fmt.Sprintf("duplicates the rate at line %d", first))
+			continue
+		}
+		seen[key] = line
+		result = append(result, rate)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ExchangeRates converts amounts between currencies through EGP, using the
+// rate in effect on a given day. It is immutable once built and safe for
+// concurrent use.
+type ExchangeRates struct {
+	byCurrency map[string][]models.ExchangeRate // oldest first
+}
+
+// NewExchangeRates indexes rates by currency and effective date
+func NewExchangeRates(rates []models.ExchangeRate) *ExchangeRates {
+	er := &ExchangeRates{byCurrency: make(map[string][]models.ExchangeRate)}
+	for _, rate := range rates {
+		er.byCurrency[rate.Currency] = append(er.byCurrency[rate.Currency], rate)
+	}
+	for _, list := range er.byCurrency {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].EffectiveFrom < list[j].EffectiveFrom })
+	}
+	return er
+}
+
+// Currencies returns EGP and every currency with a rate in effect on the
+// given day, sorted
+func (er *ExchangeRates) Currencies(on time.Time) []string {
+	result := []string{models.CurrencyEGP}
+	for currency := range er.byCurrency {
+		if er.Known(currency, on) {
+			result = append(result, currency)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Known reports whether currency is EGP or has a rate in effect on the
+// given day. A currency whose only rates take effect later is not known yet.
+func (er *ExchangeRates) Known(currency string, on time.Time) bool {
+	_, found := er.Rate(currency, on)
+	return found
+}
+
+// Rate returns the EGP value of one unit of currency on the given day
+func (er *ExchangeRates) Rate(currency string, on time.Time) (float64, bool) {
+	if currency == models.CurrencyEGP || currency == "" {
+		return 1, true
+	}
+	day := on.Format(dateLayout)
+	list := er.byCurrency[currency]
+	i := sort.Search(len(list), func(i int) bool { return list[i].EffectiveFrom > day })
+	if i == 0 {
+		return 0, false
+	}
+	return list[i-1].Rate, true
+}
+
+// Convert converts amount from one currency to another at the rates in
+// effect on the given day
+func (er *ExchangeRates) Convert(amount float64, from, to string, on time.Time) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := er.Rate(from, on)
+	if !ok {
+		return 0, fmt.Errorf("%w for %s on %s", ErrNoExchangeRate, from, on.Format(dateLayout))
+	}
+	toRate, ok := er.Rate(to, on)
+	if !ok {
+		return 0, fmt.Errorf("%w for %s on %s", ErrNoExchangeRate, to, on.Format(dateLayout))
+	}
+	return amount * fromRate / toRate, nil
+}
+
+// feeTerms are the currency and period fees are quoted in
+type feeTerms struct {
+	currency string
+	period   string
+}
+
+// inherit returns t with the empty parts taken from parent
+func (t feeTerms) inherit(parent feeTerms) feeTerms {
+	if t.currency == "" {
+		t.currency = parent.currency
+	}
+	if t.period == "" {
+		t.period = parent.period
+	}
+	return t
+}
+
+// defaultFeeTerms apply to university fees quoted without currency or period
+var defaultFeeTerms = feeTerms{currency: models.CurrencyEGP, period: models.PeriodAnnual}
+
+// annual converts a fee quoted on terms into an annual amount in currency
+func (er *ExchangeRates) annual(amount int, terms feeTerms, currency string, on time.Time) (int, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	v, err := er.Convert(float64(amount), terms.currency, currency, on)
+	if err != nil {
+		return 0, err
+	}
+	if terms.period == models.PeriodSemester {
+		v *= 2
+	}
+	return int(math.Round(v)), nil
+}
+
+// ConvertUniversities returns unis with every fee converted to annual
+// amounts in currency, as ConvertUniversity does
+func (er *ExchangeRates) ConvertUniversities(unis []models.University, currency string, on time.Time) ([]models.University, error) {
+	result := make([]models.University, len(unis))
+	for i, uni := range unis {
+		converted, err := er.ConvertUniversity(uni, currency, on)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+// ConvertUniversity returns a copy of uni with its fees, and those of its
+// faculties, departments and specializations, converted to annual amounts in
+// currency at the rates in effect on the given day. The English fee labels
+// are rewritten to match.
+func (er *ExchangeRates) ConvertUniversity(uni models.University, currency string, on time.Time) (models.University, error) {
+	uniTerms := feeTerms{uni.Fees.Currency, uni.Fees.Period}.inherit(defaultFeeTerms)
+	target := models.FeesRange{Currency: currency, Period: models.PeriodAnnual}
+
+	converted := cloneUniversity(uni)
+	fees, err := er.convertRange(uni.Fees, uniTerms, target, on)
+	if err != nil {
+		return models.University{}, fmt.Errorf("university %s fees: %w", uni.ID, err)
+	}
+	converted.Fees = fees
+
+	for key, faculty := range converted.DetailedFaculties {
+		terms := feeTerms{faculty.AnnualFees.Currency, faculty.AnnualFees.Period}.inherit(uniTerms)
+		faculty.AnnualFees, err = er.convertRange(faculty.AnnualFees, terms, target, on)
+		if err != nil {
+			return models.University{}, fmt.Errorf("university %s faculty %s fees: %w", uni.ID, key, err)
+		}
+		if faculty.AnnualFeesEn != "" {
+			faculty.AnnualFeesEn = formatFeesRange(faculty.AnnualFees)
+		}
+
+		for i, dept := range faculty.Departments {
+			dept.Fees, err = er.annual(dept.Fees, feeTerms{dept.Currency, dept.Period}.inherit(terms), currency, on)
+			if err != nil {
+				return models.University{}, fmt.Errorf("university %s department %s fees: %w", uni.ID, dept.NameEn, err)
+			}
+			dept.Currency, dept.Period = "", ""
+			if dept.FeesEn != "" {
+				dept.FeesEn = formatAnnualFee(dept.Fees, currency)
+			}
+			faculty.Departments[i] = dept
+		}
+		for i, spec := range faculty.Specializations {
+			spec.Fees, err = er.annual(spec.Fees, feeTerms{spec.Currency, spec.Period}.inherit(terms), currency, on)
+			if err != nil {
+				return models.University{}, fmt.Errorf("university %s specialization %s fees: %w", uni.ID, spec.NameEn, err)
+			}
+			spec.Currency, spec.Period = "", ""
+			if spec.FeesEn != "" {
+				spec.FeesEn = formatAnnualFee(spec.Fees, currency)
+			}
+			faculty.Specializations[i] = spec
+		}
+		converted.DetailedFaculties[key] = faculty
+	}
+	return converted, nil
+}
+
+// convertRange converts a fee range quoted on terms into target's currency
+func (er *ExchangeRates) convertRange(fees models.FeesRange, terms feeTerms, target models.FeesRange, on time.Time) (models.FeesRange, error) {
+	var err error
+	if target.Min, err = er.annual(fees.Min, terms, target.Currency, on); err != nil {
+		return models.FeesRange{}, err
+	}
+	if target.Max, err = er.annual(fees.Max, terms, target.Currency, on); err != nil {
+		return models.FeesRange{}, err
+	}
+	return target, nil
+}
+
+// formatFeesRange formats fees like the seed labels, e.g. "3,000 - 5,000 EGP"
+func formatFeesRange(fees models.FeesRange) string {
+	return formatAmount(fees.Min) + " - " + formatAmount(fees.Max) + " " + fees.Currency
+}
+
+// formatAnnualFee formats a fee like the seed labels, e.g. "5,000 EGP annually"
+func formatAnnualFee(amount int, currency string) string {
+	return formatAmount(amount) + " " + currency + " annually"
+}
+
+// formatAmount groups thousands with commas
+func formatAmount(n int) string {
+	if n < 0 {
+		return "-" + formatAmount(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// feeCurrencies returns the currencies the fees of uni are quoted in
+func feeCurrencies(uni models.University) []string {
+	var result []string
+	add := func(c string) {
+		if c != "" && !containsString(result, c) {
+			result = append(result, c)
+		}
+	}
+	add(uni.Fees.Currency)
+	for _, faculty := range uni.DetailedFaculties {
+		add(faculty.AnnualFees.Currency)
+		for _, dept := range faculty.Departments {
+			add(dept.Currency)
+		}
+		for _, spec := range faculty.Specializations {
+			add(spec.Currency)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// MissingRates returns the currencies the fees of unis are quoted in that
+// have no rate in er in effect on the given day
+func (er *ExchangeRates) MissingRates(unis []models.University, on time.Time) []string {
+	var missing []string
+	for _, uni := range unis {
+		for _, c := range feeCurrencies(uni) {
+			if !er.Known(c, on) && !containsString(missing, c) {
+				missing = append(missing, c)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
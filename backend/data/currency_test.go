@@ -0,0 +1,202 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+func testExchangeRates() *ExchangeRates {
+	return NewExchangeRates([]models.ExchangeRate{
+		{Currency: "USD", Rate: 50, EffectiveFrom: "2025-01-01"},
+		{Currency: "USD", Rate: 30, EffectiveFrom: "2023-01-01"},
+		{Currency: "USD", Rate: 40, EffectiveFrom: "2024-03-06"},
+		{Currency: "EUR", Rate: 55, EffectiveFrom: "2025-01-01"},
+	})
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestExchangeRatesRate(t *testing.T) {
+	er := testExchangeRates()
+	tests := []struct {
+		currency, on string
+		want         float64
+		ok           bool
+	}{
+		{"EGP", "2000-01-01", 1, true},
+		{"", "2000-01-01", 1, true},
+		{"USD", "2022-12-31", 0, false},
+		{"USD", "2023-01-01", 30, true},
+		{"USD", "2024-03-05", 30, true},
+		{"USD", "2024-03-06", 40, true},
+		{"USD", "2030-06-01", 50, true},
+		{"EUR", "2024-12-31", 0, false},
+		{"GBP", "2025-01-01", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := er.Rate(tt.currency, day(tt.on))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Rate(%s, %s) = %g, %v, want %g, %v", tt.currency, tt.on, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	er := testExchangeRates()
+	tests := []struct {
+		amount   float64
+		from, to string
+		on       string
+		want     float64
+		err      error
+	}{
+		{100, "USD", "EGP", "2024-01-01", 3000, nil},
+		{100, "USD", "EGP", "2024-06-01", 4000, nil},
+		{5000, "EGP", "USD", "2025-02-01", 100, nil},
+		{110, "EUR", "USD", "2025-02-01", 121, nil},
+		{100, "GBP", "GBP", "2025-02-01", 100, nil},
+		{100, "EUR", "EGP", "2024-06-01", 0, ErrNoExchangeRate},
+		{100, "EGP", "GBP", "2025-02-01", 0, ErrNoExchangeRate},
+	}
+	for _, tt := range tests {
+		got, err := er.Convert(tt.amount, tt.from, tt.to, day(tt.on))
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Convert(%g %s to %s on %s) = %g, %v, want %g, %v", tt.amount, tt.from, tt.to, tt.on, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestConvertUniversity(t *testing.T) {
+	uni := models.University{
+		ID:   "1",
+		Fees: models.FeesRange{Min: 2000, Max: 4000, Currency: "USD", Period: models.PeriodSemester},
+		DetailedFaculties: map[string]models.Faculty{
+			"medicine": {
+				AnnualFees:   models.FeesRange{Min: 100000, Max: 150000, Currency: "EGP", Period: models.PeriodAnnual},
+				AnnualFeesEn: "100,000 - 150,000 EGP",
+				Departments: []models.Department{
+					{NameEn: "Surgery", Fees: 120000, FeesEn: "120,000 EGP annually"},
+					{NameEn: "Exchange", Fees: 1000, Currency: "EUR", Period: models.PeriodSemester},
+				},
+			},
+			"engineering": {
+				// Inherits the university's USD per semester
+				Specializations: []models.Specialization{{NameEn: "Civil", Fees: 1500}},
+			},
+		},
+	}
+
+	got, err := testExchangeRates().ConvertUniversity(uni, models.CurrencyEGP, day("2025-03-01"))
+	if err != nil {
+		t.Fatalf("ConvertUniversity: %v", err)
+	}
+	if want := (models.FeesRange{Min: 200000, Max: 400000, Currency: "EGP", Period: models.PeriodAnnual}); got.Fees != want {
+		t.Errorf("fees = %+v, want %+v", got.Fees, want)
+	}
+	med := got.DetailedFaculties["medicine"]
+	if med.AnnualFees.Min != 100000 || med.AnnualFees.Max != 150000 || med.AnnualFeesEn != "100,000 - 150,000 EGP" {
+		t.Errorf("medicine fees = %+v %q", med.AnnualFees, med.AnnualFeesEn)
+	}
+	for i, want := range []models.Department{
+		{NameEn: "Surgery", Fees: 120000, FeesEn: "120,000 EGP annually"},
+		{NameEn: "Exchange", Fees: 110000},
+	} {
+		dept := med.Departments[i]
+		if dept.Fees != want.Fees || dept.FeesEn != want.FeesEn || dept.Currency != "" || dept.Period != "" {
+			t.Errorf("department %s = %+v, want %+v", want.NameEn, dept, want)
+		}
+	}
+	if spec := got.DetailedFaculties["engineering"].Specializations[0]; spec.Fees != 150000 || spec.Currency != "" {
+		t.Errorf("specialization = %+v, want 150000 with no currency", spec)
+	}
+	if uni.Fees.Currency != "USD" || uni.DetailedFaculties["medicine"].Departments[1].Currency != "EUR" {
+		t.Error("ConvertUniversity modified its argument")
+	}
+
+	// The same fees a year earlier convert at the rate then in effect
+	_, err = testExchangeRates().ConvertUniversity(uni, models.CurrencyEGP, day("2024-06-01"))
+	if !errors.Is(err, ErrNoExchangeRate) {
+		t.Fatalf("err = %v, want %v for the EUR department before its first rate", err, ErrNoExchangeRate)
+	}
+	delete(uni.DetailedFaculties, "medicine")
+	earlier, err := testExchangeRates().ConvertUniversity(uni, models.CurrencyEGP, day("2024-06-01"))
+	if err != nil {
+		t.Fatalf("ConvertUniversity: %v", err)
+	}
+	if earlier.Fees.Min != 160000 || earlier.Fees.Max != 320000 {
+		t.Errorf("fees in 2024 = %+v, want 160000 - 320000", earlier.Fees)
+	}
+}
+
+func TestExchangeRatesKnown(t *testing.T) {
+	er := testExchangeRates()
+	// EUR only takes effect on 2025-01-01
+	unis := []models.University{
+		{ID: "1", Fees: models.FeesRange{Currency: "EUR"}},
+		{ID: "2", Fees: models.FeesRange{Currency: "USD"}},
+		{ID: "3"},
+	}
+	tests := []struct {
+		on         string
+		known      bool
+		currencies []string
+		missing    []string
+	}{
+		{"2024-12-31", false, []string{"EGP", "USD"}, []string{"EUR"}},
+		{"2025-01-01", true, []string{"EGP", "EUR", "USD"}, nil},
+	}
+	for _, tt := range tests {
+		if got := er.Known("EUR", day(tt.on)); got != tt.known {
+			t.Errorf("Known(EUR, %s) = %v, want %v", tt.on, got, tt.known)
+		}
+		if got := er.Currencies(day(tt.on)); !reflect.DeepEqual(got, tt.currencies) {
+			t.Errorf("Currencies(%s) = %v, want %v", tt.on, got, tt.currencies)
+		}
+		if got := er.MissingRates(unis, day(tt.on)); !reflect.DeepEqual(got, tt.missing) {
+			t.Errorf("MissingRates(%s) = %v, want %v", tt.on, got, tt.missing)
+		}
+	}
+	if !er.Known("EGP", day("2000-01-01")) || er.Known("GBP", day("2030-01-01")) {
+		t.Error("Known: EGP is always known and GBP never is")
+	}
+}
+
+func TestReadExchangeRates(t *testing.T) {
+	rates, err := ReadExchangeRates(strings.NewReader("Currency, Rate, Effective_From\nusd,48.5,2025-07-01\n"), "rates.csv")
+	if err != nil {
+		t.Fatalf("ReadExchangeRates: %v", err)
+	}
+	if want := []models.ExchangeRate{{Currency: "USD", Rate: 48.5, EffectiveFrom: "2025-07-01"}}; !reflect.DeepEqual(rates, want) {
+		t.Fatalf("rates = %+v, want %+v", rates, want)
+	}
+
+	tests := []struct {
+		name, csv, field string
+	}{
+		{"missing column", "currency,rate\nUSD,48\n", colEffectiveFrom},
+		{"EGP", "currency,rate,effective_from\nEGP,1,2025-01-01\n", colCurrency},
+		{"negative rate", "currency,rate,effective_from\nUSD,-2,2025-01-01\n", colRate},
+		{"bad date", "currency,rate,effective_from\nUSD,48,01/07/2025\n", colEffectiveFrom},
+		{"duplicate", "currency,rate,effective_from\nUSD,48,2025-01-01\nUSD,49,2025-01-01\n", colEffectiveFrom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadExchangeRates(strings.NewReader(tt.csv), "rates.csv")
+			var errs SeedErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != tt.field {
+				t.Fatalf("err = %v, want one error on %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// Cut-off CSV columns. university_id, track, year and either faculty or
+// faculty_en are required, as is percentage or score with max_score. An
+// empty gender applies to every applicant.
+const (
+	colUniversityID = "university_id"
+	colFaculty      = "faculty"
+	colFacultyEn    = "faculty_en"
+	colTrack        = "track"
+	colGender       = "gender"
+	colYear         = "year"
+	colPercentage   = "percentage"
+	colScore        = "score"
+	colMaxScore     = "max_score"
+)
+
+var cutoffColumnNames = []string{
+	colUniversityID, colFaculty, colFacultyEn, colTrack, colGender, colYear, colPercentage, colScore, colMaxScore,
+}
+
+// firstCutoffYear is the earliest year accepted in cut-off data
+const firstCutoffYear = 1990
+
+// LoadCutoffs reads every cutoffs*.csv file at the root of fsys. Records are
+// resolved against unis, their faculties identified through faculties, and
+// validated; every problem found is returned as
+// SeedErrors, with Record holding the CSV line number. A directory without
+// cut-off files yields no records.
+func LoadCutoffs(fsys fs.FS, unis []models.University, faculties *FacultyCatalog) ([]models.Cutoff, error) {
+	names, err := fs.Glob(fsys, "cutoffs*.csv")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var result []models.Cutoff
+	var errs SeedErrors
+	seen := make(map[string]string)
+	for _, name := range names {
+		f, err := fsys.Open(name)
+		if err != nil {
+			errs = append(errs, SeedError{File: name, Record: -1, Msg: err.Error()})
+			continue
+		}
+		cutoffs, err := parseCutoffs(f, path.Base(name), unis, faculties, seen)
+		f.Close()
+
+		var seedErrs SeedErrors
+		if errors.As(err, &seedErrs) {
+			errs = append(errs, seedErrs...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cutoffs...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ImportCutoffs parses one cut-off CSV document, resolving and validating its
+// records against unis like LoadCutoffs
+func ImportCutoffs(r io.Reader, name string, unis []models.University, faculties *FacultyCatalog) ([]models.Cutoff, error) {
+	return parseCutoffs(r, name, unis, faculties, make(map[string]string))
+}
+
+// parseCutoffs reads a CSV document. seen tracks record keys across files
+// to reject duplicates.
+func parseCutoffs(r io.Reader, name string, unis []models.University, faculties *FacultyCatalog, seen map[string]string) ([]models.Cutoff, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, SeedErrors{{File: name, Record: -1, Msg: "empty file"}}
+	}
+	if err != nil {
+		return nil, SeedErrors{{File: name, Record: -1, Msg: err.Error()}}
+	}
+
+	columns := make(map[string]int, len(header))
+	var errs SeedErrors
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		if !containsString(cutoffColumnNames, col) {
+			errs = append(errs, SeedError{File: name, Record: -1, Field: col, Msg: "unknown column"})
+			continue
+		}
+		columns[col] = i
+	}
+	for _, col := range []string{colUniversityID, colTrack, colYear} {
+		if _, found := columns[col]; !found {
+			errs = append(errs, SeedError{File: name, Record: -1, Field: col, Msg: "missing column"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	var result []models.Cutoff
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line := -1
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			errs = append(errs, SeedError{File: name, Record: line, Msg: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(col string) string {
+			if i, found := columns[col]; found && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		c, fieldErrs := resolveCutoff(field, byID, faculties)
+		for _, fe := range fieldErrs {
+			errs = append(errs, SeedError{File: name, Record: line, ID: c.UniversityID, Field: fe.Field, Msg: fe.Message})
+		}
+		if len(fieldErrs) > 0 {
+			continue
+		}
+
+		key := strings.Join([]string{c.UniversityID, c.FacultyID, c.Track, c.Gender, strconv.Itoa(c.Year)}, "\x00")
+		if first, dup := seen[key]; dup {
+			errs = append(errs, SeedError{File: name, Record: line, ID: c.UniversityID, Msg: "duplicates the cut-off at " + first})
+			continue
+		}
+		seen[key] = fmt.Sprintf("%s line %d", name, line)
+		result = append(result, c)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// resolveCutoff builds a cut-off from a CSV row, taking the faculty names
+// from the catalogue
+func resolveCutoff(field func(col string) string, unis map[string]models.University, faculties *FacultyCatalog) (models.Cutoff, ValidationError) {
+	v := &validator{}
+	c := models.Cutoff{
+		UniversityID: field(colUniversityID),
+		Track:        field(colTrack),
+		Gender:       strings.ToLower(field(colGender)),
+	}
+
+	uni, found := unis[c.UniversityID]
+	switch {
+	case c.UniversityID == "":
+		v.add(colUniversityID, "is required")
+	case !found:
+		v.add(colUniversityID, "unknown university")
+	default:
+		faculty, facultyEn := field(colFaculty), field(colFacultyEn)
+		i := -1
+		if faculty != "" || facultyEn != "" {
+			i = findFaculty(uni, faculty, facultyEn)
+		}
+		switch {
+		case faculty == "" && facultyEn == "":
+			v.add(colFaculty, "faculty or faculty_en is required")
+		case i < 0:
+			v.add(colFaculty, "%q is not a faculty of university %s", firstNonEmpty(facultyEn, faculty), uni.ID)
+		default:
+			c.Faculty = uni.Faculties[i]
+			if i < len(uni.FacultiesEn) {
+				c.FacultyEn = uni.FacultiesEn[i]
+			}
+			c.FacultyID = faculties.FacultyID(c.Faculty, c.FacultyEn)
+		}
+	}
+
+	if !models.IsValidTrack(c.Track) {
+		v.add(colTrack, "must be one of %s", strings.Join(models.Tracks, ", "))
+	}
+	if c.Gender != "" && !models.IsValidGender(c.Gender) {
+		v.add(colGender, "must be empty or one of %s", strings.Join(models.Genders, ", "))
+	}
+
+	year, err := strconv.Atoi(field(colYear))
+	if maxYear := time.Now().Year() + 1; err != nil || year < firstCutoffYear || year > maxYear {
+		v.add(colYear, "must be a year between %d and %d", firstCutoffYear, maxYear)
+	}
+	c.Year = year
+
+	parse := func(col string) float64 {
+		raw := field(col)
+		if raw == "" {
+			return 0
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || n < 0 {
+			v.add(col, "must be a non-negative number")
+			return 0
+		}
+		return n
+	}
+	c.Percentage = parse(colPercentage)
+	c.Score = parse(colScore)
+	c.MaxScore = parse(colMaxScore)
+
+	switch {
+	case c.Score > 0 && c.MaxScore <= 0:
+		v.add(colMaxScore, "is required with score")
+	case c.Score > c.MaxScore:
+		v.add(colScore, "must not exceed max_score")
+	case c.Percentage == 0 && c.Score > 0:
+		c.Percentage = math.Round(c.Score/c.MaxScore*10000) / 100
+	}
+	if c.Percentage <= 0 || c.Percentage > 100 {
+		v.add(colPercentage, "must be between 0 and 100, or derived from score and max_score")
+	}
+
+	return c, v.errs
+}
+
+// findFaculty returns the index of the named faculty in the university's
+// lists, or -1
+func findFaculty(uni models.University, faculty, facultyEn string) int {
+	for i, name := range uni.Faculties {
+		nameEn := ""
+		if i < len(uni.FacultiesEn) {
+			nameEn = uni.FacultiesEn[i]
+		}
+		if (faculty != "" && facultyKey(faculty) == facultyKey(name)) ||
+			(facultyEn != "" && facultyKey(facultyEn) == facultyKey(nameEn)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// CutoffQuery selects cut-off series; empty fields match everything
+type CutoffQuery struct {
+	UniversityID string
+	FacultyID    string
+	Track        string
+	Year         int // keeps only this year's point; changes still use the year before
+}
+
+// CutoffHistory groups cut-offs into one series per university faculty,
+// track and gender, with year-over-year changes, keeping the series query
+// selects. cutoffs must be ordered as ListCutoffs returns them.
+func CutoffHistory(cutoffs []models.Cutoff, unis []models.University, query CutoffQuery) []models.CutoffSeries {
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	var result []models.CutoffSeries
+	for start := 0; start < len(cutoffs); {
+		end := start + 1
+		for end < len(cutoffs) && sameSeries(cutoffs[start], cutoffs[end]) {
+			end++
+		}
+		first := cutoffs[start]
+		group := cutoffs[start:end]
+		start = end
+
+		if (query.UniversityID != "" && first.UniversityID != query.UniversityID) ||
+			(query.FacultyID != "" && first.FacultyID != query.FacultyID) ||
+			(query.Track != "" && first.Track != query.Track) {
+			continue
+		}
+
+		series := newCutoffSeries(group, byID[first.UniversityID])
+		if query.Year != 0 {
+			var points []models.CutoffPoint
+			for _, p := range series.Points {
+				if p.Year == query.Year {
+					points = append(points, p)
+				}
+			}
+			if len(points) == 0 {
+				continue
+			}
+			series.Points = points
+		}
+		result = append(result, series)
+	}
+	return result
+}
+
+func sameSeries(a, b models.Cutoff) bool {
+	return a.UniversityID == b.UniversityID && a.FacultyID == b.FacultyID &&
+		a.Track == b.Track && a.Gender == b.Gender
+}
+
+// newCutoffSeries builds a series from one group of year-ordered cut-offs
+func newCutoffSeries(group []models.Cutoff, uni models.University) models.CutoffSeries {
+	first := group[0]
+	series := models.CutoffSeries{
+		UniversityID: first.UniversityID,
+		University:   uni.Name,
+		UniversityEn: uni.NameEn,
+		FacultyID:    first.FacultyID,
+		Faculty:      first.Faculty,
+		FacultyEn:    first.FacultyEn,
+		Track:        first.Track,
+		Gender:       first.Gender,
+		Points:       make([]models.CutoffPoint, len(group)),
+	}
+
+	for i, c := range group {
+		p := models.CutoffPoint{Year: c.Year, Percentage: c.Percentage, Score: c.Score, MaxScore: c.MaxScore}
+		if i > 0 && group[i-1].Year == c.Year-1 {
+			change := roundPoints(c.Percentage - group[i-1].Percentage)
+			p.Change = &change
+		}
+		series.Points[i] = p
+	}
+
+	latest := series.Points[len(series.Points)-1]
+	series.Latest = &latest
+	if last := group[len(group)-1]; last.Year > first.Year {
+		avg := roundPoints((last.Percentage - first.Percentage) / float64(last.Year-first.Year))
+		series.AverageChange = &avg
+	}
+	return series
+}
+
+// LatestCutoff returns the most recent cut-off of a university faculty for a
+// track, or for any track when track is empty. Cut-offs for the other gender
+// are skipped when gender is given. When several share the latest year, such
+// as one per track or gender, the lowest is taken.
+func LatestCutoff(cutoffs []models.Cutoff, universityID, facultyID, track, gender string) (models.Cutoff, bool) {
+	var best models.Cutoff
+	found := false
+	for _, c := range cutoffs {
+		if c.UniversityID != universityID || c.FacultyID != facultyID || (track != "" && c.Track != track) ||
+			(gender != "" && c.Gender != "" && c.Gender != gender) {
+			continue
+		}
+		if !found || c.Year > best.Year || (c.Year == best.Year && c.Percentage < best.Percentage) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+func roundPoints(v float64) float64 {
+	return math.Round(v*100) / 100
+}
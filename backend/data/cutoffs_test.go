@@ -0,0 +1,77 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func seedCatalogue(t *testing.T) ([]models.University, *FacultyCatalog) {
+	t.Helper()
+	unis, err := LoadUniversities(DefaultSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	disciplines, err := LoadDisciplines(DefaultSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unis, NewFacultyCatalog(disciplines)
+}
+
+func TestDefaultSeedHasNoCutoffs(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	cutoffs, err := LoadCutoffs(DefaultSeed(), unis, faculties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cutoffs) != 0 {
+		t.Errorf("default seed holds %d cut-offs, want none", len(cutoffs))
+	}
+}
+
+func TestLoadCutoffsSample(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	cutoffs, err := LoadCutoffs(os.DirFS("testdata"), unis, faculties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cutoffs) == 0 {
+		t.Fatal("sample fixture yields no cut-offs")
+	}
+	for _, co := range cutoffs {
+		if co.FacultyID == "" {
+			t.Errorf("%s %s %d: faculty not resolved", co.UniversityID, co.FacultyEn, co.Year)
+		}
+	}
+}
+
+func TestImportCutoffsErrors(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	tests := []struct {
+		name, csv, field string
+	}{
+		{"unknown column", "university_id,track,year,colour\n1,science,2024,red\n", "colour"},
+		{"missing column", "university_id,year\n1,2024\n", "track"},
+		{"unknown university", "university_id,faculty_en,track,year,percentage\nnope,Medicine,science,2024,90\n", "university_id"},
+		{"unknown track", "university_id,faculty_en,track,year,percentage\n1,Medicine,arts,2024,90\n", "track"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportCutoffs(strings.NewReader(tt.csv), "import.csv", unis, faculties)
+			var errs SeedErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("err = %v, want SeedErrors", err)
+			}
+			for _, se := range errs {
+				if se.Field == tt.field {
+					return
+				}
+			}
+			t.Errorf("errors %v do not name field %q", errs, tt.field)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+var (
+	// ErrInvalidPercentage is returned for a missing or out-of-range grade
+	ErrInvalidPercentage = errors.New("invalid percentage")
+	// ErrInvalidTrack is returned for an unknown secondary school track
+	ErrInvalidTrack = errors.New("invalid track")
+)
+
+// Classification margins in percentage points above or below the cut-off.
+// A student is safe with safeMargin to spare, likely when at or above the
+// cut-off and a reach when within reachMargin below it.
+const (
+	safeMargin  = 3.0
+	reachMargin = 3.0
+)
+
+// facultyTracks lists the tracks admitted by the faculties of the catalogue
+// that do not admit every track, by catalogue ID
+var facultyTracks = map[string][]string{
+	"medicine":            {models.TrackScience},
+	"dentistry":           {models.TrackScience},
+	"pharmacy":            {models.TrackScience},
+	"nursing":             {models.TrackScience},
+	"physical-therapy":    {models.TrackScience},
+	"veterinary-medicine": {models.TrackScience},
+	"biotechnology":       {models.TrackScience},
+	"engineering":         {models.TrackMath},
+	"computer-science":    {models.TrackMath},
+	"science":             {models.TrackScience, models.TrackMath},
+	"agriculture":         {models.TrackScience, models.TrackMath},
+}
+
+// FacultyTracks returns the tracks a faculty admits by its catalogue ID.
+// Faculties missing from the catalogue admit every track.
+func FacultyTracks(facultyID string) []string {
+	if tracks, found := facultyTracks[facultyID]; found {
+		return tracks
+	}
+	return models.Tracks
+}
+
+// cutoff is the grade a university or faculty is estimated to require
+type cutoff struct {
+	value  float64
+	source string
+}
+
+// admissionTarget is a university, or one faculty of it, being estimated
+type admissionTarget struct {
+	uni       models.University
+	faculty   string
+	facultyEn string
+}
+
+// EstimateAdmission classifies the student's chance at each university
+// matching req, or at each requested faculty of it. For Thanawiya students
+// the cut-off is the faculty's latest Tansik cut-off from history when there
+// is one; otherwise it comes from the faculty's admission rule, then the
+// university's. The margins needed widen for selective universities.
+func EstimateAdmission(unis []models.University, faculties *FacultyCatalog, history []models.Cutoff, req models.AdmissionEstimateRequest) (models.AdmissionEstimateResponse, error) {
+	var resp models.AdmissionEstimateResponse
+
+	if req.Percentage == nil || *req.Percentage < 0 || *req.Percentage > 100 {
+		return resp, fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidPercentage)
+	}
+	if !models.IsValidCertificate(req.Certificate) {
+		return resp, fmt.Errorf("%w: unknown certificate %q", ErrInvalidBackground, req.Certificate)
+	}
+	if req.Track != "" && !models.IsValidTrack(req.Track) {
+		return resp, fmt.Errorf("%w: %q", ErrInvalidTrack, req.Track)
+	}
+	if req.Gender != "" && !models.IsValidGender(req.Gender) {
+		return resp, fmt.Errorf("%w: %q", ErrInvalidGender, req.Gender)
+	}
+
+	wanted := make(map[string]string, len(req.Faculties))
+	matched := make(map[string]bool, len(req.Faculties))
+	for _, name := range req.Faculties {
+		if key := facultyKey(name); key != "" {
+			wanted[key] = name
+		}
+	}
+
+	for _, uni := range unis {
+		if req.SelectedType != "" && req.SelectedType != "all" && uni.Type != req.SelectedType {
+			continue
+		}
+		if req.SelectedRegion != "" && req.SelectedRegion != "all" && uni.Region != req.SelectedRegion {
+			continue
+		}
+
+		if len(wanted) == 0 {
+			resp.Estimates = append(resp.Estimates, estimate(admissionTarget{uni: uni}, faculties, history, req))
+			continue
+		}
+		for i, name := range uni.Faculties {
+			nameEn := ""
+			if i < len(uni.FacultiesEn) {
+				nameEn = uni.FacultiesEn[i]
+			}
+			requested, found := wanted[facultyKey(name)]
+			if !found {
+				requested, found = wanted[facultyKey(nameEn)]
+			}
+			if !found {
+				continue
+			}
+			matched[requested] = true
+			resp.Estimates = append(resp.Estimates, estimate(admissionTarget{uni: uni, faculty: name, facultyEn: nameEn}, faculties, history, req))
+		}
+	}
+
+	for _, name := range req.Faculties {
+		if !matched[name] {
+			resp.UnmatchedFaculties = append(resp.UnmatchedFaculties, name)
+		}
+	}
+
+	sortEstimates(resp.Estimates)
+	resp.Summary = make(map[string]int, len(models.Chances))
+	for _, chance := range models.Chances {
+		resp.Summary[chance] = 0
+	}
+	for _, e := range resp.Estimates {
+		resp.Summary[e.Classification]++
+	}
+	return resp, nil
+}
+
+// facultyKey normalises a faculty name for matching, so "Faculty of
+// Medicine", "medicine" and "كلية الطب" all compare equal to their catalogue
+// names
+func facultyKey(name string) string {
+	name = strings.TrimSpace(name)
+	if rest, found := strings.CutPrefix(strings.ToLower(name), "faculty of "); found {
+		name = rest
+	}
+	name = strings.TrimPrefix(name, "كلية ")
+	return strings.Join(search.Tokenize(name), " ")
+}
+
+// estimate classifies the student's chance at one target
+func estimate(target admissionTarget, faculties *FacultyCatalog, history []models.Cutoff, req models.AdmissionEstimateRequest) models.AdmissionEstimate {
+	uni := target.uni
+	e := models.AdmissionEstimate{
+		UniversityID:   uni.ID,
+		University:     uni.Name,
+		UniversityEn:   uni.NameEn,
+		Faculty:        target.faculty,
+		FacultyEn:      target.facultyEn,
+		AcceptanceRate: uni.AcceptanceRate,
+	}
+
+	rule, cut, found := targetCutoff(target, req.Certificate)
+	if !found {
+		e.Classification = models.ChanceOutOfRange
+		e.Reasons = []string{"does not accept " + req.Certificate + " certificates"}
+		return e
+	}
+	var trend *float64
+	if req.Certificate == models.CertificateThanawiya && target.facultyEn != "" {
+		if latest, found := LatestCutoff(history, uni.ID, faculties.FacultyID(target.faculty, target.facultyEn), req.Track, req.Gender); found {
+			cut = cutoff{
+				value:  latest.Percentage,
+				source: fmt.Sprintf("%d Tansik cut-off for the %s track", latest.Year, latest.Track),
+			}
+			// The trend of the series the cut-off belongs to, not one for
+			// the other gender
+			query := CutoffQuery{UniversityID: uni.ID, FacultyID: latest.FacultyID, Track: latest.Track}
+			for _, series := range CutoffHistory(history, nil, query) {
+				if series.Gender == latest.Gender {
+					trend = series.AverageChange
+					break
+				}
+			}
+		}
+	}
+	e.Cutoff, e.CutoffSource = round1(cut.value), cut.source
+
+	percentage := *req.Percentage
+	if req.CertificateScore != nil && rule.ScoreScale > 0 {
+		percentage = *req.CertificateScore / rule.ScoreScale * 100
+	}
+	e.Margin = round1(percentage - cut.value)
+
+	// Only Egyptian secondary certificates are split into tracks
+	if req.Track != "" && target.facultyEn != "" &&
+		(req.Certificate == models.CertificateThanawiya || req.Certificate == models.CertificateAzhari) {
+		tracks := FacultyTracks(faculties.FacultyID(target.faculty, target.facultyEn))
+		if !containsString(tracks, req.Track) {
+			e.Classification = models.ChanceOutOfRange
+			e.Reasons = []string{fmt.Sprintf("%s admits the %s track only, not %s", target.facultyEn, strings.Join(tracks, " or "), req.Track)}
+			return e
+		}
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%s admits the %s track", target.facultyEn, req.Track))
+	}
+
+	if cut.value <= 0 {
+		e.Classification = models.ChanceLikely
+		e.Reasons = append(e.Reasons, "no minimum grade is published for "+req.Certificate)
+		return e
+	}
+	e.Reasons = append(e.Reasons, fmt.Sprintf("cut-off %.1f%% from the %s", cut.value, cut.source))
+	if trend != nil && *trend != 0 {
+		direction := "rising"
+		if *trend < 0 {
+			direction = "falling"
+		}
+		e.Reasons = append(e.Reasons, fmt.Sprintf("cut-offs have been %s by %.2f points a year", direction, math.Abs(*trend)))
+	}
+
+	adj := selectivity(uni.AcceptanceRate)
+	safe := safeMargin + adj
+	if adj != 0 {
+		e.Reasons = append(e.Reasons, fmt.Sprintf("acceptance rate %d%% moves the safe margin to %.1f points", uni.AcceptanceRate, safe))
+	}
+
+	switch {
+	case e.Margin >= safe:
+		e.Classification = models.ChanceSafe
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%.1f points above the cut-off", e.Margin))
+	case e.Margin >= 0:
+		e.Classification = models.ChanceLikely
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%.1f points above the cut-off, less than the %.1f-point safe margin", e.Margin, safe))
+	case e.Margin >= -reachMargin:
+		e.Classification = models.ChanceReach
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%.1f points below the cut-off", -e.Margin))
+	default:
+		e.Classification = models.ChanceOutOfRange
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%.1f points below the cut-off", -e.Margin))
+	}
+	return e
+}
+
+// targetCutoff finds the rule for the certificate and the cut-off it implies
+func targetCutoff(target admissionTarget, certificate string) (models.AdmissionRule, cutoff, bool) {
+	if faculty, found := target.uni.DetailedFaculties[target.faculty]; found {
+		if rule, found := findRule(faculty.Admission, certificate); found {
+			return rule, ruleCutoff(rule, "faculty admission rule"), true
+		}
+	}
+
+	source := "university admission rule"
+	if len(target.uni.Admission) == 0 {
+		source = "university minimum grade"
+	}
+	rule, found := findRule(AdmissionRules(target.uni), certificate)
+	if !found {
+		return rule, cutoff{}, false
+	}
+	return rule, ruleCutoff(rule, source), true
+}
+
+// ruleCutoff expresses a rule's minimum as a percentage
+func ruleCutoff(rule models.AdmissionRule, source string) cutoff {
+	value := rule.MinPercentage
+	if value == 0 && rule.MinScore > 0 && rule.ScoreScale > 0 {
+		value = rule.MinScore / rule.ScoreScale * 100
+	}
+	return cutoff{value: value, source: source}
+}
+
+// selectivity widens the safe margin at universities admitting few
+// applicants and narrows it at open ones
+func selectivity(acceptanceRate int) float64 {
+	switch {
+	case acceptanceRate <= 0:
+		return 0
+	case acceptanceRate < 20:
+		return 2
+	case acceptanceRate < 35:
+		return 1
+	case acceptanceRate >= 60:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// sortEstimates orders estimates from safest to least certain, then by
+// margin
+func sortEstimates(estimates []models.AdmissionEstimate) {
+	rank := make(map[string]int, len(models.Chances))
+	for i, chance := range models.Chances {
+		rank[chance] = i
+	}
+	sort.SliceStable(estimates, func(i, j int) bool {
+		a, b := estimates[i], estimates[j]
+		if rank[a.Classification] != rank[b.Classification] {
+			return rank[a.Classification] < rank[b.Classification]
+		}
+		if a.Margin != b.Margin {
+			return a.Margin > b.Margin
+		}
+		return lessID(a.UniversityID, b.UniversityID)
+	})
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
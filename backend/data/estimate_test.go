@@ -0,0 +1,264 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func testFacultyCatalog() *FacultyCatalog {
+	return NewFacultyCatalog([]models.Discipline{
+		{ID: "medicine", Name: "الطب", NameEn: "Medicine"},
+		{ID: "engineering", Name: "الهندسة", NameEn: "Engineering"},
+		{ID: "science", Name: "العلوم", NameEn: "Science"},
+		{ID: "computer-science", Name: "الحاسبات والمعلومات", NameEn: "Computer Science",
+			Aliases: []string{"Computers and Artificial Intelligence"}},
+		{ID: "commerce", Name: "التجارة", NameEn: "Commerce"},
+	})
+}
+
+func TestFacultyTracks(t *testing.T) {
+	fc := testFacultyCatalog()
+	tests := []struct {
+		name, nameEn string
+		want         []string
+	}{
+		{"الطب", "Medicine", []string{models.TrackScience}},
+		{"الهندسة", "Faculty of Engineering", []string{models.TrackMath}},
+		{"", "Computers and Artificial Intelligence", []string{models.TrackMath}},
+		{"العلوم", "Science", []string{models.TrackScience, models.TrackMath}},
+		{"التجارة", "Commerce", models.Tracks},
+		// Names merely containing a keyword admit every track
+		{"الاقتصاد والعلوم السياسية", "Economics and Political Science", models.Tracks},
+		{"", "Social Sciences", models.Tracks},
+		{"", "Engineering Management", models.Tracks},
+	}
+	for _, tt := range tests {
+		id := fc.FacultyID(tt.name, tt.nameEn)
+		if got := FacultyTracks(id); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FacultyTracks(%q) for %q = %v, want %v", id, tt.nameEn, got, tt.want)
+		}
+	}
+}
+
+func estimateCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo", MinGrade: 90,
+			Faculties: []string{"الطب", "التجارة"}, FacultiesEn: []string{"Medicine", "Commerce"}},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "private", Region: "alexandria", MinGrade: 80,
+			AcceptanceRate: 15, Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"}},
+		{ID: "3", Name: "جامعة ج", NameEn: "University C", Type: "private", Region: "cairo", MinGrade: 70,
+			AcceptanceRate: 70},
+		{ID: "4", Name: "جامعة د", NameEn: "University D", Type: "azhar", Region: "cairo", MinGrade: 60},
+	}
+}
+
+func TestEstimateAdmission(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name string
+		req  models.AdmissionEstimateRequest
+		want map[string]string // classification by university ID and faculty
+	}{
+		{
+			name: "margins",
+			req:  models.AdmissionEstimateRequest{Percentage: pct(91), Certificate: models.CertificateThanawiya},
+			want: map[string]string{
+				// Likely at the cut-off, safe with 3 points to spare; the
+				// safe margin widens to 5 at 15% acceptance and narrows to 2
+				// at 70%
+				"1": models.ChanceLikely, "2": models.ChanceSafe, "3": models.ChanceSafe,
+				"4": models.ChanceOutOfRange,
+			},
+		},
+		{
+			name: "reach within three points below",
+			req:  models.AdmissionEstimateRequest{Percentage: pct(87), Certificate: models.CertificateThanawiya},
+			want: map[string]string{"1": models.ChanceReach, "2": models.ChanceSafe, "3": models.ChanceSafe, "4": models.ChanceOutOfRange},
+		},
+		{
+			name: "selective university needs a wider margin",
+			req:  models.AdmissionEstimateRequest{Percentage: pct(84), Certificate: models.CertificateThanawiya, SelectedType: "private"},
+			want: map[string]string{"2": models.ChanceLikely, "3": models.ChanceSafe},
+		},
+		{
+			name: "faculties and tracks",
+			req: models.AdmissionEstimateRequest{Percentage: pct(95), Certificate: models.CertificateThanawiya,
+				Track: models.TrackLiterary, Faculties: []string{"Faculty of Medicine", "كلية التجارة", "الهندسة"}},
+			want: map[string]string{
+				"1/Medicine": models.ChanceOutOfRange, "1/Commerce": models.ChanceSafe,
+				"2/Engineering": models.ChanceOutOfRange,
+			},
+		},
+		{
+			name: "region filter",
+			req:  models.AdmissionEstimateRequest{Percentage: pct(95), Certificate: models.CertificateAzhari, SelectedRegion: "alexandria"},
+			want: map[string]string{"2": models.ChanceSafe},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := EstimateAdmission(estimateCatalogue(), testFacultyCatalog(), nil, tt.req)
+			if err != nil {
+				t.Fatalf("EstimateAdmission: %v", err)
+			}
+			got := make(map[string]string, len(resp.Estimates))
+			for _, e := range resp.Estimates {
+				key := e.UniversityID
+				if e.FacultyEn != "" {
+					key += "/" + e.FacultyEn
+				}
+				got[key] = e.Classification
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("classifications = %v, want %v", got, tt.want)
+			}
+			total := 0
+			for _, n := range resp.Summary {
+				total += n
+			}
+			if total != len(resp.Estimates) || len(resp.Summary) != len(models.Chances) {
+				t.Fatalf("summary %v does not cover %d estimates", resp.Summary, len(resp.Estimates))
+			}
+		})
+	}
+}
+
+func TestEstimateAdmissionOrder(t *testing.T) {
+	pct := 91.0
+	resp, err := EstimateAdmission(estimateCatalogue(), testFacultyCatalog(), nil,
+		models.AdmissionEstimateRequest{Percentage: &pct, Certificate: models.CertificateThanawiya})
+	if err != nil {
+		t.Fatalf("EstimateAdmission: %v", err)
+	}
+	var order []string
+	for _, e := range resp.Estimates {
+		order = append(order, e.UniversityID)
+	}
+	// Safe first by margin, then likely, then out of range
+	if want := []string{"3", "2", "1", "4"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+func TestEstimateAdmissionHistory(t *testing.T) {
+	history := []models.Cutoff{
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Year: 2022, Percentage: 93},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Year: 2023, Percentage: 95},
+	}
+	pct := 96.0
+	resp, err := EstimateAdmission(estimateCatalogue(), testFacultyCatalog(), history, models.AdmissionEstimateRequest{
+		Percentage: &pct, Certificate: models.CertificateThanawiya, Track: models.TrackScience,
+		Faculties: []string{"medicine", "pharmacy"},
+	})
+	if err != nil {
+		t.Fatalf("EstimateAdmission: %v", err)
+	}
+	if len(resp.Estimates) != 1 {
+		t.Fatalf("estimates = %+v, want one", resp.Estimates)
+	}
+	e := resp.Estimates[0]
+	if e.Cutoff != 95 || e.Margin != 1 || e.Classification != models.ChanceLikely {
+		t.Fatalf("estimate = %+v, want the 2023 cut-off of 95 and likely", e)
+	}
+	if !reflect.DeepEqual(resp.UnmatchedFaculties, []string{"pharmacy"}) {
+		t.Fatalf("unmatched = %v, want [pharmacy]", resp.UnmatchedFaculties)
+	}
+}
+
+func TestEstimateAdmissionByGender(t *testing.T) {
+	history := []models.Cutoff{
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderFemale, Year: 2022, Percentage: 95},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderFemale, Year: 2023, Percentage: 94},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderMale, Year: 2022, Percentage: 90},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderMale, Year: 2023, Percentage: 92},
+	}
+	tests := []struct {
+		gender string
+		cutoff float64
+		trend  string
+	}{
+		{models.GenderFemale, 94, "cut-offs have been falling by 1.00 points a year"},
+		{models.GenderMale, 92, "cut-offs have been rising by 2.00 points a year"},
+		// Without a gender the lowest cut-off is taken, with its own trend
+		{"", 92, "cut-offs have been rising by 2.00 points a year"},
+	}
+	for _, tt := range tests {
+		pct := 93.0
+		resp, err := EstimateAdmission(estimateCatalogue(), testFacultyCatalog(), history, models.AdmissionEstimateRequest{
+			Percentage: &pct, Certificate: models.CertificateThanawiya, Track: models.TrackScience, Gender: tt.gender,
+			Faculties: []string{"medicine"},
+		})
+		if err != nil {
+			t.Fatalf("EstimateAdmission: %v", err)
+		}
+		if len(resp.Estimates) != 1 {
+			t.Fatalf("estimates = %+v, want one", resp.Estimates)
+		}
+		e := resp.Estimates[0]
+		if e.Cutoff != tt.cutoff || !containsString(e.Reasons, tt.trend) {
+			t.Errorf("gender %q: cut-off %g with reasons %q; want %g and %q", tt.gender, e.Cutoff, e.Reasons, tt.cutoff, tt.trend)
+		}
+	}
+}
+
+func TestLatestCutoff(t *testing.T) {
+	history := []models.Cutoff{
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Year: 2022, Percentage: 91},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderFemale, Year: 2023, Percentage: 94},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderMale, Year: 2023, Percentage: 92},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackMath, Year: 2024, Percentage: 90},
+		{UniversityID: "2", FacultyID: "medicine", Track: models.TrackScience, Year: 2024, Percentage: 80},
+	}
+	tests := []struct {
+		track, gender string
+		want          float64
+		year          int
+	}{
+		{models.TrackScience, models.GenderFemale, 94, 2023},
+		{models.TrackScience, models.GenderMale, 92, 2023},
+		{models.TrackScience, "", 92, 2023},
+		{"", models.GenderFemale, 90, 2024},
+		{models.TrackLiterary, "", 0, 0},
+	}
+	for _, tt := range tests {
+		got, found := LatestCutoff(history, "1", "medicine", tt.track, tt.gender)
+		if found != (tt.year != 0) || got.Percentage != tt.want || got.Year != tt.year {
+			t.Errorf("LatestCutoff(%q, %q) = %+v, %v; want %g in %d", tt.track, tt.gender, got, found, tt.want, tt.year)
+		}
+	}
+}
+
+func TestEstimateAdmissionErrors(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name string
+		req  models.AdmissionEstimateRequest
+		want error
+	}{
+		{"missing percentage", models.AdmissionEstimateRequest{Certificate: models.CertificateThanawiya}, ErrInvalidPercentage},
+		{"percentage above 100", models.AdmissionEstimateRequest{Percentage: pct(101), Certificate: models.CertificateThanawiya}, ErrInvalidPercentage},
+		{"negative percentage", models.AdmissionEstimateRequest{Percentage: pct(-1), Certificate: models.CertificateThanawiya}, ErrInvalidPercentage},
+		{"unknown certificate", models.AdmissionEstimateRequest{Percentage: pct(90), Certificate: "gcse"}, ErrInvalidBackground},
+		{"unknown track", models.AdmissionEstimateRequest{Percentage: pct(90), Certificate: models.CertificateThanawiya, Track: "arts"}, ErrInvalidTrack},
+		{"unknown gender", models.AdmissionEstimateRequest{Percentage: pct(90), Certificate: models.CertificateThanawiya, Gender: "other"}, ErrInvalidGender},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EstimateAdmission(estimateCatalogue(), testFacultyCatalog(), nil, tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectivity(t *testing.T) {
+	for rate, want := range map[int]float64{0: 0, 10: 2, 19: 2, 20: 1, 34: 1, 35: 0, 59: 0, 60: -1, 90: -1} {
+		if got := selectivity(rate); got != want {
+			t.Errorf("selectivity(%d) = %g, want %g", rate, got, want)
+		}
+	}
+}
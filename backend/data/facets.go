@@ -0,0 +1,130 @@
+package data
+
+import (
+	"sort"
+
+	"roadtouniversities/models"
+)
+
+// searchFilter identifies one of the filters applied by SearchUniversities
+type searchFilter uint
+
+const (
+	filterType searchFilter = 1 << iota
+	filterRegion
+	filterFees
+	filterQuery
+	filterGrade
+)
+
+// Histogram bucket edges for the fee and grade facets. Each bucket runs from
+// its edge up to the next one; the last is open-ended.
+var (
+	feeFacetEdges   = []int{0, 10000, 50000, 100000, 200000}
+	gradeFacetEdges = []int{0, 60, 70, 80, 90}
+)
+
+// facetCounter accumulates facet counts from the filters each university
+// failed
+type facetCounter struct {
+	facets     models.SearchFacets
+	faculties  map[string]int
+	facultyEn  map[string]string
+	feeCount   []int
+	gradeCount []int
+}
+
+func newFacetCounter() *facetCounter {
+	fc := &facetCounter{
+		facets: models.SearchFacets{
+			Types:   make(map[string]int, len(models.UniversityTypes)),
+			Regions: make(map[string]int, len(models.Regions)),
+		},
+		faculties:  make(map[string]int),
+		facultyEn:  make(map[string]string),
+		feeCount:   make([]int, len(feeFacetEdges)),
+		gradeCount: make([]int, len(gradeFacetEdges)),
+	}
+	// Known values are listed even when nothing matches them
+	for _, t := range models.UniversityTypes {
+		fc.facets.Types[t] = 0
+	}
+	for _, r := range models.Regions {
+		fc.facets.Regions[r] = 0
+	}
+	return fc
+}
+
+// add counts uni in every facet whose own filter is the only one it failed,
+// or in all facets when it failed none
+func (fc *facetCounter) add(uni models.University, failed searchFilter) {
+	passesExcept := func(f searchFilter) bool {
+		return failed&^f == 0
+	}
+
+	if passesExcept(filterType) {
+		fc.facets.Types[uni.Type]++
+	}
+	if passesExcept(filterRegion) {
+		fc.facets.Regions[uni.Region]++
+	}
+	if passesExcept(filterFees) {
+		fc.feeCount[bucketIndex(feeFacetEdges, uni.Fees.Max)]++
+	}
+	if passesExcept(filterGrade) {
+		fc.gradeCount[bucketIndex(gradeFacetEdges, uni.MinGrade)]++
+	}
+	if failed == 0 {
+		for i, name := range uni.Faculties {
+			fc.faculties[name]++
+			if i < len(uni.FacultiesEn) && fc.facultyEn[name] == "" {
+				fc.facultyEn[name] = uni.FacultiesEn[i]
+			}
+		}
+	}
+}
+
+// result returns the finished facets, faculties ordered by descending count
+func (fc *facetCounter) result() models.SearchFacets {
+	facets := fc.facets
+	facets.Faculties = make([]models.FacetCount, 0, len(fc.faculties))
+	for name, count := range fc.faculties {
+		facets.Faculties = append(facets.Faculties, models.FacetCount{
+			Value:   name,
+			ValueEn: fc.facultyEn[name],
+			Count:   count,
+		})
+	}
+	sort.Slice(facets.Faculties, func(i, j int) bool {
+		a, b := facets.Faculties[i], facets.Faculties[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+	facets.Fees = buckets(feeFacetEdges, fc.feeCount)
+	facets.Grades = buckets(gradeFacetEdges, fc.gradeCount)
+	return facets
+}
+
+// bucketIndex returns the bucket of edges holding v; values below the first
+// edge fall in the first bucket
+func bucketIndex(edges []int, v int) int {
+	i := sort.SearchInts(edges, v+1) - 1
+	if i < 0 {
+		return 0
+	}
+	return i
+}
+
+func buckets(edges, counts []int) []models.FacetBucket {
+	result := make([]models.FacetBucket, len(edges))
+	for i, edge := range edges {
+		result[i] = models.FacetBucket{Min: edge, Count: counts[i]}
+		if i+1 < len(edges) {
+			max := edges[i+1]
+			result[i].Max = &max
+		}
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func facetCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Type: "public", Region: "cairo", Fees: models.FeesRange{Max: 5000}, MinGrade: 85,
+			Faculties: []string{"الطب", "الهندسة"}, FacultiesEn: []string{"Medicine", "Engineering"}},
+		{ID: "2", Type: "private", Region: "cairo", Fees: models.FeesRange{Max: 120000}, MinGrade: 70,
+			Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"}},
+		{ID: "3", Type: "public", Region: "alexandria", Fees: models.FeesRange{Max: 8000}, MinGrade: 90,
+			Faculties: []string{"الطب"}},
+		{ID: "4", Type: "private", Region: "delta", Fees: models.FeesRange{Max: 60000}, MinGrade: 65,
+			Faculties: []string{"الحاسبات"}, FacultiesEn: []string{"Computers"}},
+	}
+}
+
+// facetCounts flattens bucket counts for comparison
+func facetCounts(buckets []models.FacetBucket) []int {
+	counts := make([]int, len(buckets))
+	for i, b := range buckets {
+		counts[i] = b.Count
+	}
+	return counts
+}
+
+func TestSearchFacets(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		name      string
+		params    models.SearchParams
+		ids       []string
+		types     map[string]int
+		regions   map[string]int
+		faculties []models.FacetCount
+		fees      []int
+		grades    []int
+	}{
+		{
+			name:    "no filters",
+			ids:     []string{"1", "2", "3", "4"},
+			types:   map[string]int{"public": 2, "private": 2, "national": 0, "azhar": 0},
+			regions: map[string]int{"cairo": 2, "alexandria": 1, "delta": 1, "upper-egypt": 0, "suez-canal": 0},
+			faculties: []models.FacetCount{
+				{Value: "الطب", ValueEn: "Medicine", Count: 2},
+				{Value: "الهندسة", ValueEn: "Engineering", Count: 2},
+				{Value: "الحاسبات", ValueEn: "Computers", Count: 1},
+			},
+			fees:   []int{2, 0, 1, 1, 0},
+			grades: []int{0, 1, 1, 1, 1},
+		},
+		{
+			name:    "a facet ignores its own filter",
+			params:  models.SearchParams{SelectedType: "public"},
+			ids:     []string{"1", "3"},
+			types:   map[string]int{"public": 2, "private": 2, "national": 0, "azhar": 0},
+			regions: map[string]int{"cairo": 1, "alexandria": 1, "delta": 0, "upper-egypt": 0, "suez-canal": 0},
+			faculties: []models.FacetCount{
+				{Value: "الطب", ValueEn: "Medicine", Count: 2},
+				{Value: "الهندسة", ValueEn: "Engineering", Count: 1},
+			},
+			fees:   []int{2, 0, 0, 0, 0},
+			grades: []int{0, 0, 0, 1, 1},
+		},
+		{
+			name:    "other filters still apply",
+			params:  models.SearchParams{SelectedType: "public", SelectedRegion: "cairo"},
+			ids:     []string{"1"},
+			types:   map[string]int{"public": 1, "private": 1, "national": 0, "azhar": 0},
+			regions: map[string]int{"cairo": 1, "alexandria": 1, "delta": 0, "upper-egypt": 0, "suez-canal": 0},
+			faculties: []models.FacetCount{
+				{Value: "الطب", ValueEn: "Medicine", Count: 1},
+				{Value: "الهندسة", ValueEn: "Engineering", Count: 1},
+			},
+			fees:   []int{1, 0, 0, 0, 0},
+			grades: []int{0, 0, 0, 1, 0},
+		},
+		{
+			name:      "no results",
+			params:    models.SearchParams{FilterByFees: intPtr(10000), FilterByGrade: intPtr(80)},
+			ids:       []string{},
+			types:     map[string]int{"public": 0, "private": 0, "national": 0, "azhar": 0},
+			regions:   map[string]int{"cairo": 0, "alexandria": 0, "delta": 0, "upper-egypt": 0, "suez-canal": 0},
+			faculties: []models.FacetCount{},
+			fees:      []int{0, 0, 1, 1, 0},
+			grades:    []int{0, 0, 0, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SearchUniversities(facetCatalogue(), nil, tt.params)
+			if err != nil {
+				t.Fatalf("SearchUniversities: %v", err)
+			}
+			if got := universityIDs(result.Universities); !reflect.DeepEqual(got, tt.ids) {
+				t.Fatalf("results = %v, want %v", got, tt.ids)
+			}
+			f := result.Facets
+			if !reflect.DeepEqual(f.Types, tt.types) {
+				t.Errorf("types = %v, want %v", f.Types, tt.types)
+			}
+			if !reflect.DeepEqual(f.Regions, tt.regions) {
+				t.Errorf("regions = %v, want %v", f.Regions, tt.regions)
+			}
+			if !reflect.DeepEqual(f.Faculties, tt.faculties) {
+				t.Errorf("faculties = %+v, want %+v", f.Faculties, tt.faculties)
+			}
+			if got := facetCounts(f.Fees); !reflect.DeepEqual(got, tt.fees) {
+				t.Errorf("fees = %v, want %v", got, tt.fees)
+			}
+			if got := facetCounts(f.Grades); !reflect.DeepEqual(got, tt.grades) {
+				t.Errorf("grades = %v, want %v", got, tt.grades)
+			}
+		})
+	}
+}
+
+func TestBucketIndex(t *testing.T) {
+	tests := []struct {
+		v    int
+		want int
+	}{
+		{-5, 0}, {0, 0}, {9999, 0}, {10000, 1}, {49999, 1}, {50000, 2}, {200000, 4}, {1000000, 4},
+	}
+	for _, tt := range tests {
+		if got := bucketIndex(feeFacetEdges, tt.v); got != tt.want {
+			t.Errorf("bucketIndex(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFacetBuckets(t *testing.T) {
+	got := buckets([]int{0, 60, 90}, []int{1, 2, 3})
+	if len(got) != 3 || got[0].Max == nil || *got[0].Max != 60 || *got[1].Max != 90 || got[2].Max != nil {
+		t.Fatalf("buckets = %+v, want [0,60) [60,90) [90,…)", got)
+	}
+	if got[2].Min != 90 || got[2].Count != 3 {
+		t.Fatalf("last bucket = %+v", got[2])
+	}
+}
@@ -0,0 +1,261 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"unicode"
+
+	"roadtouniversities/models"
+)
+
+// disciplinesDir is the seed directory holding the faculty catalogue
+const disciplinesDir = "faculties"
+
+// LoadDisciplines reads the faculty catalogue from the .json, .yaml and .yml
+// files in the faculties directory of fsys, in file order. IDs, names and
+// aliases must be unique across the catalogue; every problem found is
+// returned as SeedErrors. Without the directory the catalogue is empty.
+func LoadDisciplines(fsys fs.FS) ([]models.Discipline, error) {
+	names, err := seedFiles(fsys, disciplinesDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.Discipline
+	var errs SeedErrors
+	seenIDs := make(map[string]string)
+	seenNames := make(map[string]string)
+
+	for _, name := range names {
+		records, fileErr := readSeedFile(fsys, name, "a faculty")
+		if fileErr != nil {
+			errs = append(errs, *fileErr)
+			continue
+		}
+
+		for i, raw := range records {
+			var d models.Discipline
+			if recErr := decodeRecord(raw, &d); recErr != nil {
+				recErr.File, recErr.Record, recErr.ID = name, i, recordID(raw)
+				errs = append(errs, *recErr)
+				continue
+			}
+
+			if err := ValidateDiscipline(d); err != nil {
+				var verr ValidationError
+				if errors.As(err, &verr) {
+					for _, fe := range verr {
+						errs = append(errs, SeedError{File: name, Record: i, ID: d.ID, Field: fe.Field, Msg: fe.Message})
+					}
+					continue
+				}
+				errs = append(errs, SeedError{File: name, Record: i, ID: d.ID, Msg: err.Error()})
+				continue
+			}
+
+			if first, dup := seenIDs[d.ID]; dup {
+				errs = append(errs, SeedError{File: name, Record: i, ID: d.ID, Field: "id", Msg: "duplicates a record in " + first})
+				continue
+			}
+			seenIDs[d.ID] = name
+
+			clash := false
+			for j, n := range disciplineNames(d) {
+				key := facultyKey(n)
+				if other, dup := seenNames[key]; dup && other != d.ID {
+					field := fmt.Sprintf("aliases[%d]", j-2)
+					switch j {
+					case 0:
+						field = "name"
+					case 1:
+						field = "nameEn"
+					}
+					errs = append(errs, SeedError{File: name, Record: i, ID: d.ID, Field: field, Msg: fmt.Sprintf("%q is already a name of %s", n, other)})
+					clash = true
+				}
+				seenNames[key] = d.ID
+			}
+			if !clash {
+				result = append(result, d)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+func disciplineNames(d models.Discipline) []string {
+	return append([]string{d.Name, d.NameEn}, d.Aliases...)
+}
+
+// FacultySlug derives an ID from a faculty name missing from the catalogue,
+// e.g. "Sharia & Law" → "sharia-and-law"
+func FacultySlug(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", " and ")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// FacultyCatalog links the faculties of universities to disciplines. It is
+// immutable once built and safe for concurrent use.
+type FacultyCatalog struct {
+	disciplines []models.Discipline
+	byID        map[string]int
+	byName      map[string]int
+}
+
+// NewFacultyCatalog indexes disciplines by ID, name and alias
+func NewFacultyCatalog(disciplines []models.Discipline) *FacultyCatalog {
+	fc := &FacultyCatalog{
+		disciplines: disciplines,
+		byID:        make(map[string]int, len(disciplines)),
+		byName:      make(map[string]int),
+	}
+	for i, d := range disciplines {
+		fc.byID[d.ID] = i
+		for _, name := range disciplineNames(d) {
+			if key := facultyKey(name); key != "" {
+				fc.byName[key] = i
+			}
+		}
+	}
+	return fc
+}
+
+// Disciplines returns the catalogue in order
+func (fc *FacultyCatalog) Disciplines() []models.Discipline {
+	return fc.disciplines
+}
+
+// Discipline returns the discipline with the given ID
+func (fc *FacultyCatalog) Discipline(id string) (models.Discipline, bool) {
+	if i, found := fc.byID[id]; found {
+		return fc.disciplines[i], true
+	}
+	return models.Discipline{}, false
+}
+
+// Resolve finds the discipline of a university faculty by its Arabic name,
+// then its English name, since English names such as "Arts" are ambiguous
+func (fc *FacultyCatalog) Resolve(name, nameEn string) (models.Discipline, bool) {
+	for _, n := range []string{name, nameEn} {
+		if i, found := fc.byName[facultyKey(n)]; found && n != "" {
+			return fc.disciplines[i], true
+		}
+	}
+	return models.Discipline{}, false
+}
+
+// FacultyID returns the ID of a university faculty: its discipline's, or a
+// slug of its name when the catalogue does not know it
+func (fc *FacultyCatalog) FacultyID(name, nameEn string) string {
+	if d, found := fc.Resolve(name, nameEn); found {
+		return d.ID
+	}
+	return FacultySlug(firstNonEmpty(nameEn, name))
+}
+
+// universityFaculty is one faculty of a university
+type universityFaculty struct {
+	uni    models.University
+	name   string
+	nameEn string
+}
+
+// eachFaculty calls fn for every faculty of unis with its ID, in university
+// order
+func (fc *FacultyCatalog) eachFaculty(unis []models.University, fn func(id string, f universityFaculty)) {
+	for _, uni := range unis {
+		for i, name := range uni.Faculties {
+			nameEn := ""
+			if i < len(uni.FacultiesEn) {
+				nameEn = uni.FacultiesEn[i]
+			}
+			fn(fc.FacultyID(name, nameEn), universityFaculty{uni: uni, name: name, nameEn: nameEn})
+		}
+	}
+}
+
+// GetAllFaculties lists every discipline of the catalogue, then the
+// faculties it does not know, each with the number of universities offering
+// it
+func GetAllFaculties(unis []models.University, fc *FacultyCatalog) []models.FacultySummary {
+	result := make([]models.FacultySummary, len(fc.disciplines))
+	index := make(map[string]int, len(fc.disciplines))
+	for i, d := range fc.disciplines {
+		result[i] = models.FacultySummary{Discipline: d}
+		index[d.ID] = i
+	}
+
+	counted := make(map[string]bool)
+	fc.eachFaculty(unis, func(id string, f universityFaculty) {
+		i, found := index[id]
+		if !found {
+			i = len(result)
+			index[id] = i
+			result = append(result, models.FacultySummary{Discipline: uncataloguedDiscipline(id, f)})
+		}
+		if key := id + "\x00" + f.uni.ID; !counted[key] {
+			counted[key] = true
+			result[i].UniversityCount++
+		}
+	})
+	return result
+}
+
+// GetFacultyByID returns a discipline with every university offering it
+func GetFacultyByID(unis []models.University, fc *FacultyCatalog, id string) (models.FacultyDetail, bool) {
+	d, found := fc.Discipline(id)
+	detail := models.FacultyDetail{Discipline: d, Universities: []models.FacultyOffering{}}
+
+	fc.eachFaculty(unis, func(fid string, f universityFaculty) {
+		if fid != id {
+			return
+		}
+		if !found {
+			detail.Discipline, found = uncataloguedDiscipline(id, f), true
+		}
+		detail.Universities = append(detail.Universities, newFacultyOffering(f))
+	})
+	return detail, found
+}
+
+// uncataloguedDiscipline stands in for a faculty the catalogue does not know
+func uncataloguedDiscipline(id string, f universityFaculty) models.Discipline {
+	return models.Discipline{ID: id, Name: f.name, NameEn: f.nameEn}
+}
+
+func newFacultyOffering(f universityFaculty) models.FacultyOffering {
+	o := models.FacultyOffering{
+		UniversityID:   f.uni.ID,
+		University:     f.uni.Name,
+		UniversityEn:   f.uni.NameEn,
+		UniversityType: f.uni.Type,
+		Region:         f.uni.Region,
+		Name:           f.name,
+		NameEn:         f.nameEn,
+		AnnualFees:     f.uni.Fees,
+	}
+
+	detailed, found := f.uni.DetailedFaculties[f.name]
+	if !found {
+		return o
+	}
+	o.Description, o.DescriptionEn = detailed.Description, detailed.DescriptionEn
+	if detailed.AnnualFees.Max > 0 {
+		o.AnnualFees, o.AnnualFeesEn = detailed.AnnualFees, detailed.AnnualFeesEn
+	}
+	o.Departments = detailed.Departments
+	o.Specializations = detailed.Specializations
+	return o
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestFacultySlug(t *testing.T) {
+	tests := map[string]string{
+		"Sharia & Law":          "sharia-and-law",
+		"  Mass Communication ": "mass-communication",
+		"Arts (English)":        "arts-english",
+		"هندسة البترول":         "هندسة-البترول",
+		"":                      "",
+	}
+	for name, want := range tests {
+		if got := FacultySlug(name); got != want {
+			t.Errorf("FacultySlug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFacultyCatalogResolve(t *testing.T) {
+	fc := testFacultyCatalog()
+	tests := []struct {
+		name, nameEn string
+		want         string // discipline ID, "" when unresolved
+	}{
+		{"الطب", "", "medicine"},
+		{"", "Medicine", "medicine"},
+		{"كلية الهندسة", "Faculty of Engineering", "engineering"},
+		{"", "faculty of engineering", "engineering"},
+		{"", "Computers and Artificial Intelligence", "computer-science"},
+		// The Arabic name is tried before the English one
+		{"الحاسبات والمعلومات", "Science", "computer-science"},
+		{"كلية الألسن", "Science", "science"},
+		{"كلية الألسن", "Al-Alsun", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		d, found := fc.Resolve(tt.name, tt.nameEn)
+		if found != (tt.want != "") || d.ID != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, %v; want %q", tt.name, tt.nameEn, d.ID, found, tt.want)
+		}
+	}
+}
+
+func TestFacultyCatalogDiscipline(t *testing.T) {
+	fc := testFacultyCatalog()
+	if d, found := fc.Discipline("computer-science"); !found || d.NameEn != "Computer Science" || len(d.Aliases) != 1 {
+		t.Errorf("Discipline(computer-science) = %+v, %v", d, found)
+	}
+	if d, found := fc.Discipline("Medicine"); found {
+		t.Errorf("Discipline found %+v by name, want IDs only", d)
+	}
+	if got := len(fc.Disciplines()); got != 5 {
+		t.Errorf("%d disciplines, want 5", got)
+	}
+}
+
+func TestFacultyCatalogFacultyID(t *testing.T) {
+	fc := testFacultyCatalog()
+	tests := []struct {
+		name, nameEn, want string
+	}{
+		{"كلية الطب", "Faculty of Medicine", "medicine"},
+		// Faculties the catalogue does not know fall back to a slug of the
+		// English name, then the Arabic one
+		{"الشريعة والقانون", "Sharia & Law", "sharia-and-law"},
+		{"هندسة البترول", "", "هندسة-البترول"},
+	}
+	for _, tt := range tests {
+		if got := fc.FacultyID(tt.name, tt.nameEn); got != tt.want {
+			t.Errorf("FacultyID(%q, %q) = %q, want %q", tt.name, tt.nameEn, got, tt.want)
+		}
+	}
+}
+
+func facultyCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo",
+			Fees:      models.FeesRange{Min: 1000, Max: 3000},
+			Faculties: []string{"كلية الطب", "الهندسة"}, FacultiesEn: []string{"Faculty of Medicine", "Engineering"},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {DescriptionEn: "Founded 1816", AnnualFees: models.FeesRange{Min: 2000, Max: 4000},
+					Departments: []models.Department{{Name: "المدني", NameEn: "Civil"}}},
+			}},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "azhar", Region: "delta",
+			Fees:      models.FeesRange{Min: 5000, Max: 6000},
+			Faculties: []string{"الهندسة", "الشريعة والقانون"}, FacultiesEn: []string{"Engineering", "Sharia & Law"},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {Departments: []models.Department{{Name: "العمارة", NameEn: "Architecture"}}},
+			}},
+		// Without English names, an unknown faculty takes its Arabic name's
+		// slug
+		{ID: "3", Name: "جامعة ج", NameEn: "University C", Type: "public", Region: "suez-canal",
+			Faculties: []string{"كلية الهندسة", "هندسة البترول"}, FacultiesEn: []string{"Faculty of Engineering"}},
+	}
+}
+
+func TestGetAllFaculties(t *testing.T) {
+	got := GetAllFaculties(facultyCatalogue(), testFacultyCatalog())
+	want := []struct {
+		id, nameEn string
+		count      int
+	}{
+		{"medicine", "Medicine", 1},
+		{"engineering", "Engineering", 3},
+		{"science", "Science", 0},
+		{"computer-science", "Computer Science", 0},
+		{"commerce", "Commerce", 0},
+		{"sharia-and-law", "Sharia & Law", 1},
+		{"هندسة-البترول", "", 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d faculties, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].ID != w.id || got[i].NameEn != w.nameEn || got[i].UniversityCount != w.count {
+			t.Errorf("faculty %d = %s (%q) at %d universities, want %s (%q) at %d",
+				i, got[i].ID, got[i].NameEn, got[i].UniversityCount, w.id, w.nameEn, w.count)
+		}
+	}
+
+	// A university listing a faculty under two names counts once
+	unis := []models.University{{ID: "1", Faculties: []string{"الهندسة", "كلية الهندسة"}}}
+	if got := GetAllFaculties(unis, testFacultyCatalog()); got[1].UniversityCount != 1 {
+		t.Errorf("engineering counted at %d universities, want 1", got[1].UniversityCount)
+	}
+}
+
+func TestGetFacultyByID(t *testing.T) {
+	got, found := GetFacultyByID(facultyCatalogue(), testFacultyCatalog(), "engineering")
+	if !found || got.Name != "الهندسة" || got.NameEn != "Engineering" {
+		t.Fatalf("GetFacultyByID(engineering) = %+v, %v", got.Discipline, found)
+	}
+	want := []models.FacultyOffering{
+		{UniversityID: "1", University: "جامعة أ", UniversityEn: "University A", UniversityType: "public", Region: "cairo",
+			Name: "الهندسة", NameEn: "Engineering", DescriptionEn: "Founded 1816", AnnualFees: models.FeesRange{Min: 2000, Max: 4000},
+			Departments: []models.Department{{Name: "المدني", NameEn: "Civil"}}},
+		// Without fees of its own the faculty takes the university's
+		{UniversityID: "2", University: "جامعة ب", UniversityEn: "University B", UniversityType: "azhar", Region: "delta",
+			Name: "الهندسة", NameEn: "Engineering", AnnualFees: models.FeesRange{Min: 5000, Max: 6000},
+			Departments: []models.Department{{Name: "العمارة", NameEn: "Architecture"}}},
+		{UniversityID: "3", University: "جامعة ج", UniversityEn: "University C", UniversityType: "public", Region: "suez-canal",
+			Name: "كلية الهندسة", NameEn: "Faculty of Engineering"},
+	}
+	if !reflect.DeepEqual(got.Universities, want) {
+		t.Errorf("offerings = %+v\nwant %+v", got.Universities, want)
+	}
+
+	got, found = GetFacultyByID(facultyCatalogue(), testFacultyCatalog(), "sharia-and-law")
+	if want := (models.Discipline{ID: "sharia-and-law", Name: "الشريعة والقانون", NameEn: "Sharia & Law"}); !found ||
+		!reflect.DeepEqual(got.Discipline, want) || len(got.Universities) != 1 {
+		t.Errorf("uncatalogued faculty = %+v, %v; want %+v offered once", got, found, want)
+	}
+
+	got, found = GetFacultyByID(facultyCatalogue(), testFacultyCatalog(), "science")
+	if !found || got.Universities == nil || len(got.Universities) != 0 {
+		t.Errorf("faculty nobody offers = %+v, %v; want it with no universities", got, found)
+	}
+	if _, found := GetFacultyByID(facultyCatalogue(), testFacultyCatalog(), "astrology"); found {
+		t.Error("found a faculty neither catalogued nor offered")
+	}
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"roadtouniversities/models"
+)
+
+// icalLineLimit is the longest content line RFC 5545 allows, in octets
+const icalLineLimit = 75
+
+// WriteICalendar writes events as an RFC 5545 calendar of all-day events,
+// titled and described in lang (ar or en, falling back to the other when a
+// text is missing). stamp is the DTSTAMP of every event.
+func WriteICalendar(w io.Writer, events []models.CalendarEvent, lang string, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Road to Universities//Admissions Calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", icalText(pick(lang, "مواعيد القبول", "Admissions calendar")))
+
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		start, err := time.Parse(dateLayout, e.Start)
+		if err != nil {
+			return err
+		}
+		end := start
+		if e.End != "" {
+			if end, err = time.Parse(dateLayout, e.End); err != nil {
+				return err
+			}
+		}
+
+		summary := pick(lang, e.Title, e.TitleEn)
+		if university := pick(lang, e.University, e.UniversityEn); university != "" {
+			summary += " - " + university
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", e.ID+"@roadtouniversities")
+		line("DTSTAMP", dtstamp)
+		// DTEND is exclusive for all-day events
+		line("DTSTART;VALUE=DATE", start.Format("20060102"))
+		line("DTEND;VALUE=DATE", end.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY", icalText(summary))
+		if description := pick(lang, e.Description, e.DescriptionEn); description != "" {
+			line("DESCRIPTION", icalText(description))
+		}
+		if location := pick(lang, e.Location, e.LocationEn); location != "" {
+			line("LOCATION", icalText(location))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("CATEGORIES", strings.ToUpper(e.Kind))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// pick returns the Arabic or English text for lang, or the other when it is
+// empty
+func pick(lang, ar, en string) string {
+	if lang == "ar" {
+		return firstNonEmpty(ar, en)
+	}
+	return firstNonEmpty(en, ar)
+}
+
+// icalEscaper escapes TEXT values
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalText(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+// writeFolded writes a content line with CRLF endings, folding it into
+// continuation lines of at most icalLineLimit octets without splitting a
+// UTF-8 sequence
+func writeFolded(w *bufio.Writer, s string) {
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts to the limit
+		limit = icalLineLimit - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
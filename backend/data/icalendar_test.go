@@ -0,0 +1,124 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"roadtouniversities/models"
+)
+
+func TestWriteFolded(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		lines int
+	}{
+		{"short", "SUMMARY:Open day", 1},
+		{"at the limit", "SUMMARY:" + strings.Repeat("a", icalLineLimit-len("SUMMARY:")), 1},
+		{"one octet over", "SUMMARY:" + strings.Repeat("a", icalLineLimit-len("SUMMARY:")+1), 2},
+		{"continuations hold one octet less", strings.Repeat("a", icalLineLimit+2*(icalLineLimit-1)), 3},
+		// Two-octet runes must not be split at the fold
+		{"arabic", "SUMMARY:" + strings.Repeat("ب", 60), 2},
+		{"four-octet runes", "X:" + strings.Repeat("😀", 40), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+			writeFolded(w, tt.in)
+			w.Flush()
+
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end in CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("folded into %d lines, want %d: %q", len(lines), tt.lines, lines)
+			}
+			for i, line := range lines {
+				if len(line) > icalLineLimit {
+					t.Errorf("line %d is %d octets, over %d", i, len(line), icalLineLimit)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+			}
+			// Unfolding removes each CRLF and the space after it
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.in {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.in)
+			}
+		})
+	}
+}
+
+func TestICalText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Open day", "Open day"},
+		{"Cairo, Giza; Alexandria", `Cairo\, Giza\; Alexandria`},
+		{`C:\path`, `C:\\path`},
+		{"one\ntwo\r\nthree", `one\ntwo\nthree`},
+	}
+	for _, tt := range tests {
+		if got := icalText(tt.in); got != tt.want {
+			t.Errorf("icalText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteICalendar(t *testing.T) {
+	events := []models.CalendarEvent{
+		{
+			AdmissionEvent: models.AdmissionEvent{ID: "interviews", UniversityID: "1", Kind: models.EventInterview,
+				Title: "المقابلات", TitleEn: "Interviews", DescriptionEn: "Bring your ID, and a pen",
+				Start: "2027-07-30", End: "2027-08-01"},
+			University: "جامعة أ", UniversityEn: "University A",
+		},
+		{AdmissionEvent: models.AdmissionEvent{ID: "phase-1", Kind: models.EventTansikPhase, Title: "المرحلة الأولى",
+			Start: "2027-12-31"}},
+	}
+	stamp := time.Date(2027, 1, 2, 3, 4, 5, 0, time.FixedZone("EET", 2*60*60))
+
+	var buf bytes.Buffer
+	if err := WriteICalendar(&buf, events, "en", stamp); err != nil {
+		t.Fatalf("WriteICalendar: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Admissions calendar\r\n",
+		"UID:interviews@roadtouniversities\r\n",
+		"DTSTAMP:20270102T010405Z\r\n",
+		// DTEND is the day after the inclusive end
+		"DTSTART;VALUE=DATE:20270730\r\nDTEND;VALUE=DATE:20270802\r\n",
+		"SUMMARY:Interviews - University A\r\n",
+		`DESCRIPTION:Bring your ID\, and a pen` + "\r\n",
+		"CATEGORIES:INTERVIEW\r\n",
+		// Without an end the event lasts its start day, across the year end;
+		// the Arabic title stands in for a missing English one
+		"DTSTART;VALUE=DATE:20271231\r\nDTEND;VALUE=DATE:20280101\r\nSUMMARY:المرحلة الأولى\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != len(events) {
+		t.Errorf("%d events written, want %d", n, len(events))
+	}
+
+	buf.Reset()
+	if err := WriteICalendar(&buf, events[:1], "ar", stamp); err != nil {
+		t.Fatalf("WriteICalendar: %v", err)
+	}
+	if !strings.Contains(buf.String(), "SUMMARY:المقابلات - جامعة أ\r\n") {
+		t.Errorf("Arabic calendar lacks the Arabic summary:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,808 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// MemoryStore keeps the catalogue in process memory
+type MemoryStore struct {
+	mu           sync.RWMutex
+	universities []models.University
+	cutoffs      []models.Cutoff
+	disciplines  []models.Discipline
+	scholarships []models.Scholarship
+	events       []models.AdmissionEvent
+	users        []models.User
+	refresh      map[string]models.RefreshToken
+	favorites    []models.Favorite
+	shortlists   []models.Shortlist
+	searches     []models.SavedSearch
+	reviews      []models.Review
+	moderation   []models.ModerationAction
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// ListUniversities returns every university in insertion order
+func (s *MemoryStore) ListUniversities() ([]models.University, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.University, len(s.universities))
+	for i, uni := range s.universities {
+		result[i] = cloneUniversity(uni)
+	}
+	return result, nil
+}
+
+// GetUniversity returns a university by ID
+func (s *MemoryStore) GetUniversity(id string) (models.University, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i := s.indexOf(id); i >= 0 {
+		return cloneUniversity(s.universities[i]), true, nil
+	}
+	return models.University{}, false, nil
+}
+
+// PutUniversity creates or replaces a university by ID
+func (s *MemoryStore) PutUniversity(uni models.University) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uni = cloneUniversity(uni)
+	if i := s.indexOf(uni.ID); i >= 0 {
+		s.universities[i] = uni
+		return nil
+	}
+	s.universities = append(s.universities, uni)
+	return nil
+}
+
+// SetRatings sets the rating of the universities keyed by ID
+func (s *MemoryStore) SetRatings(ratings map[string]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, rating := range ratings {
+		if i := s.indexOf(id); i >= 0 {
+			s.universities[i].Rating = rating
+		}
+	}
+	return nil
+}
+
+// CreateUniversity adds a university, returning ErrUniversityExists when its
+// ID is in use
+func (s *MemoryStore) CreateUniversity(uni models.University) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexOf(uni.ID) >= 0 {
+		return ErrUniversityExists
+	}
+	s.universities = append(s.universities, cloneUniversity(uni))
+	return nil
+}
+
+// DeleteUniversity removes a university with its cut-offs, admission
+// events, favorites and shortlist items, reporting whether it existed
+func (s *MemoryStore) DeleteUniversity(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return false, nil
+	}
+	s.universities = append(s.universities[:i], s.universities[i+1:]...)
+
+	kept := s.cutoffs[:0]
+	for _, c := range s.cutoffs {
+		if c.UniversityID != id {
+			kept = append(kept, c)
+		}
+	}
+	s.cutoffs = kept
+
+	events := s.events[:0]
+	for _, e := range s.events {
+		if e.UniversityID != id {
+			events = append(events, e)
+		}
+	}
+	s.events = events
+
+	favorites := s.favorites[:0]
+	for _, f := range s.favorites {
+		if f.UniversityID != id {
+			favorites = append(favorites, f)
+		}
+	}
+	s.favorites = favorites
+
+	for i, list := range s.shortlists {
+		items := make([]models.ShortlistItem, 0, len(list.Items))
+		for _, item := range list.Items {
+			if item.UniversityID != id {
+				items = append(items, item)
+			}
+		}
+		s.shortlists[i].Items = items
+	}
+
+	reviews := s.reviews[:0]
+	for _, r := range s.reviews {
+		if r.UniversityID != id {
+			reviews = append(reviews, r)
+		}
+	}
+	s.reviews = reviews
+	return true, nil
+}
+
+// ListCutoffs returns every cut-off ordered by university, faculty ID, track,
+// gender and year
+func (s *MemoryStore) ListCutoffs() ([]models.Cutoff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	order := make(map[string]int, len(s.universities))
+	for i, uni := range s.universities {
+		order[uni.ID] = i
+	}
+
+	result := append([]models.Cutoff(nil), s.cutoffs...)
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.UniversityID != b.UniversityID {
+			return order[a.UniversityID] < order[b.UniversityID]
+		}
+		if a.FacultyID != b.FacultyID {
+			return a.FacultyID < b.FacultyID
+		}
+		if a.Track != b.Track {
+			return a.Track < b.Track
+		}
+		if a.Gender != b.Gender {
+			return a.Gender < b.Gender
+		}
+		return a.Year < b.Year
+	})
+	return result, nil
+}
+
+// PutCutoffs creates or replaces cut-offs keyed by university, faculty ID,
+// track, gender and year
+func (s *MemoryStore) PutCutoffs(cutoffs []models.Cutoff) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range cutoffs {
+		replaced := false
+		for i, existing := range s.cutoffs {
+			if existing.UniversityID == c.UniversityID && existing.FacultyID == c.FacultyID &&
+				existing.Track == c.Track && existing.Gender == c.Gender && existing.Year == c.Year {
+				s.cutoffs[i], replaced = c, true
+				break
+			}
+		}
+		if !replaced {
+			s.cutoffs = append(s.cutoffs, c)
+		}
+	}
+	return nil
+}
+
+// ListDisciplines returns every discipline in insertion order
+func (s *MemoryStore) ListDisciplines() ([]models.Discipline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Discipline, len(s.disciplines))
+	for i, d := range s.disciplines {
+		d.Aliases = cloneStrings(d.Aliases)
+		result[i] = d
+	}
+	return result, nil
+}
+
+// PutDisciplines creates or replaces disciplines by ID
+func (s *MemoryStore) PutDisciplines(disciplines []models.Discipline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range disciplines {
+		d.Aliases = cloneStrings(d.Aliases)
+		replaced := false
+		for i, existing := range s.disciplines {
+			if existing.ID == d.ID {
+				s.disciplines[i], replaced = d, true
+				break
+			}
+		}
+		if !replaced {
+			s.disciplines = append(s.disciplines, d)
+		}
+	}
+	return nil
+}
+
+// ListScholarships returns every scholarship in insertion order
+func (s *MemoryStore) ListScholarships() ([]models.Scholarship, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Scholarship, len(s.scholarships))
+	for i, sch := range s.scholarships {
+		result[i] = cloneScholarship(sch)
+	}
+	return result, nil
+}
+
+// PutScholarships creates or replaces scholarships by ID
+func (s *MemoryStore) PutScholarships(scholarships []models.Scholarship) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sch := range scholarships {
+		sch = cloneScholarship(sch)
+		replaced := false
+		for i, existing := range s.scholarships {
+			if existing.ID == sch.ID {
+				s.scholarships[i], replaced = sch, true
+				break
+			}
+		}
+		if !replaced {
+			s.scholarships = append(s.scholarships, sch)
+		}
+	}
+	return nil
+}
+
+// ListEvents returns every admission event in insertion order
+func (s *MemoryStore) ListEvents() ([]models.AdmissionEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.AdmissionEvent{}, s.events...), nil
+}
+
+// PutEvents creates or replaces admission events by ID
+func (s *MemoryStore) PutEvents(events []models.AdmissionEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		replaced := false
+		for i, existing := range s.events {
+			if existing.ID == e.ID {
+				s.events[i], replaced = e, true
+				break
+			}
+		}
+		if !replaced {
+			s.events = append(s.events, e)
+		}
+	}
+	return nil
+}
+
+// DeleteEvent removes an admission event, reporting whether it existed
+func (s *MemoryStore) DeleteEvent(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range s.events {
+		if e.ID == id {
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListUsers returns every account in creation order
+func (s *MemoryStore) ListUsers() ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.User{}, s.users...), nil
+}
+
+// GetUser returns an account by ID
+func (s *MemoryStore) GetUser(id string) (models.User, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, true, nil
+		}
+	}
+	return models.User{}, false, nil
+}
+
+// GetUserByEmail returns an account by email
+func (s *MemoryStore) GetUserByEmail(email string) (models.User, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, true, nil
+		}
+	}
+	return models.User{}, false, nil
+}
+
+// CreateUser adds an account, returning ErrEmailTaken when its email is in
+// use
+func (s *MemoryStore) CreateUser(user models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Email == user.Email {
+			return ErrEmailTaken
+		}
+	}
+	s.users = append(s.users, user)
+	return nil
+}
+
+// SetUserRole changes the role of an account, reporting whether it exists
+func (s *MemoryStore) SetUserRole(id, role string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, u := range s.users {
+		if u.ID == id {
+			s.users[i].Role = role
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PutRefreshToken stores a refresh token
+func (s *MemoryStore) PutRefreshToken(token models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refresh == nil {
+		s.refresh = make(map[string]models.RefreshToken)
+	}
+	s.refresh[token.Hash] = token
+	return nil
+}
+
+// TakeRefreshToken removes and returns a refresh token by hash
+func (s *MemoryStore) TakeRefreshToken(hash string) (models.RefreshToken, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, found := s.refresh[hash]
+	delete(s.refresh, hash)
+	return token, found, nil
+}
+
+// ListFavorites returns the favorites of a user in the order added
+func (s *MemoryStore) ListFavorites(userID string) ([]models.Favorite, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Favorite
+	for _, f := range s.favorites {
+		if f.UserID == userID {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// AddFavorite stores a favorite, reporting false when the user already has
+// the university
+func (s *MemoryStore) AddFavorite(fav models.Favorite) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, f := range s.favorites {
+		if f.UserID == fav.UserID && f.UniversityID == fav.UniversityID {
+			return false, nil
+		}
+	}
+	s.favorites = append(s.favorites, fav)
+	return true, nil
+}
+
+// RemoveFavorite removes a favorite, reporting whether it existed
+func (s *MemoryStore) RemoveFavorite(userID, universityID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.favorites {
+		if f.UserID == userID && f.UniversityID == universityID {
+			s.favorites = append(s.favorites[:i], s.favorites[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListShortlists returns the shortlists of a user in creation order
+func (s *MemoryStore) ListShortlists(userID string) ([]models.Shortlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Shortlist
+	for _, list := range s.shortlists {
+		if list.UserID == userID {
+			result = append(result, cloneShortlist(list))
+		}
+	}
+	return result, nil
+}
+
+// GetShortlist returns a shortlist of a user by ID
+func (s *MemoryStore) GetShortlist(userID, id string) (models.Shortlist, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, list := range s.shortlists {
+		if list.UserID == userID && list.ID == id {
+			return cloneShortlist(list), true, nil
+		}
+	}
+	return models.Shortlist{}, false, nil
+}
+
+// PutShortlist creates or replaces a shortlist by ID and user
+func (s *MemoryStore) PutShortlist(list models.Shortlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list = cloneShortlist(list)
+	for i, existing := range s.shortlists {
+		if existing.ID == list.ID {
+			if existing.UserID != list.UserID {
+				return fmt.Errorf("shortlist %s belongs to another user", list.ID)
+			}
+			s.shortlists[i] = list
+			return nil
+		}
+	}
+	s.shortlists = append(s.shortlists, list)
+	return nil
+}
+
+// DeleteShortlist removes a shortlist of a user, reporting whether it
+// existed
+func (s *MemoryStore) DeleteShortlist(userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, list := range s.shortlists {
+		if list.UserID == userID && list.ID == id {
+			s.shortlists = append(s.shortlists[:i], s.shortlists[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListSavedSearches returns the saved searches of a user in creation order
+func (s *MemoryStore) ListSavedSearches(userID string) ([]models.SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.SavedSearch
+	for _, search := range s.searches {
+		if search.UserID == userID {
+			result = append(result, cloneSavedSearch(search))
+		}
+	}
+	return result, nil
+}
+
+// ListWatchedSearches returns every saved search with notifications on, in
+// creation order
+func (s *MemoryStore) ListWatchedSearches() ([]models.SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.SavedSearch
+	for _, search := range s.searches {
+		if search.Notify {
+			result = append(result, cloneSavedSearch(search))
+		}
+	}
+	return result, nil
+}
+
+// GetSavedSearch returns a saved search of a user by ID
+func (s *MemoryStore) GetSavedSearch(userID, id string) (models.SavedSearch, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, search := range s.searches {
+		if search.UserID == userID && search.ID == id {
+			return cloneSavedSearch(search), true, nil
+		}
+	}
+	return models.SavedSearch{}, false, nil
+}
+
+// PutSavedSearch creates or replaces a saved search by ID and user
+func (s *MemoryStore) PutSavedSearch(search models.SavedSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search = cloneSavedSearch(search)
+	for i, existing := range s.searches {
+		if existing.ID == search.ID {
+			if existing.UserID != search.UserID {
+				return fmt.Errorf("saved search %s belongs to another user", search.ID)
+			}
+			s.searches[i] = search
+			return nil
+		}
+	}
+	s.searches = append(s.searches, search)
+	return nil
+}
+
+// SetSearchMatches records the results of a saved search as of checkedAt,
+// reporting whether it exists
+func (s *MemoryStore) SetSearchMatches(id string, matches []models.SearchMatch, checkedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, search := range s.searches {
+		if search.ID == id {
+			s.searches[i].Matches = append([]models.SearchMatch{}, matches...)
+			s.searches[i].CheckedAt = checkedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteSavedSearch removes a saved search of a user, reporting whether it
+// existed
+func (s *MemoryStore) DeleteSavedSearch(userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, search := range s.searches {
+		if search.UserID == userID && search.ID == id {
+			s.searches = append(s.searches[:i], s.searches[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListReviews returns the reviews passing filter in submission order
+func (s *MemoryStore) ListReviews(filter ReviewFilter) ([]models.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Review
+	for _, r := range s.reviews {
+		if filter.Matches(r) {
+			result = append(result, cloneReview(r))
+		}
+	}
+	return result, nil
+}
+
+// GetReview returns a review by ID
+func (s *MemoryStore) GetReview(id string) (models.Review, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.reviews {
+		if r.ID == id {
+			return cloneReview(r), true, nil
+		}
+	}
+	return models.Review{}, false, nil
+}
+
+// PutReview creates or replaces a review by ID
+func (s *MemoryStore) PutReview(r models.Review) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r = cloneReview(r)
+	for i, existing := range s.reviews {
+		if existing.ID == r.ID {
+			s.reviews[i] = r
+			return nil
+		}
+	}
+	s.reviews = append(s.reviews, r)
+	return nil
+}
+
+// DeleteReview removes a review, reporting whether it existed
+func (s *MemoryStore) DeleteReview(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.reviews {
+		if r.ID == id {
+			s.reviews = append(s.reviews[:i], s.reviews[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListModerationActions returns the audit entries passing filter in the
+// order recorded
+func (s *MemoryStore) ListModerationActions(filter ModerationFilter) ([]models.ModerationAction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.ModerationAction
+	for _, a := range s.moderation {
+		if filter.Matches(a) {
+			a.Flags = cloneFlags(a.Flags)
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+// AddModerationAction records an audit entry
+func (s *MemoryStore) AddModerationAction(action models.ModerationAction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action.Flags = cloneFlags(action.Flags)
+	s.moderation = append(s.moderation, action)
+	return nil
+}
+
+// ModerateReview sets the status of review r and records the audit entry
+// of the decision under one lock, provided the review is unchanged
+func (s *MemoryStore) ModerateReview(r models.Review, action models.ModerationAction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.reviews {
+		if existing.ID != r.ID {
+			continue
+		}
+		if !existing.UpdatedAt.Equal(r.UpdatedAt) || existing.Status != action.PreviousStatus {
+			return ErrReviewChanged
+		}
+		s.reviews[i].Status, s.reviews[i].StatusReason = r.Status, r.StatusReason
+		action.Flags = cloneFlags(action.Flags)
+		s.moderation = append(s.moderation, action)
+		return nil
+	}
+	return ErrReviewChanged
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func (s *MemoryStore) indexOf(id string) int {
+	for i, uni := range s.universities {
+		if uni.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// cloneUniversity deep-copies the slices and maps of a university so callers
+// cannot mutate the store's copy
+func cloneUniversity(uni models.University) models.University {
+	uni.Faculties = cloneStrings(uni.Faculties)
+	uni.FacultiesEn = cloneStrings(uni.FacultiesEn)
+	uni.Specialties = cloneStrings(uni.Specialties)
+	uni.Languages = cloneStrings(uni.Languages)
+	uni.Admission = cloneRules(uni.Admission)
+	if uni.Costs != nil {
+		costs := *uni.Costs
+		uni.Costs = &costs
+	}
+
+	if len(uni.DetailedFaculties) == 0 {
+		uni.DetailedFaculties = nil
+	} else {
+		faculties := make(map[string]models.Faculty, len(uni.DetailedFaculties))
+		for key, faculty := range uni.DetailedFaculties {
+			faculty.Departments = append([]models.Department(nil), faculty.Departments...)
+			for i := range faculty.Departments {
+				faculty.Departments[i].Degrees = cloneStrings(faculty.Departments[i].Degrees)
+				faculty.Departments[i].DegreesEn = cloneStrings(faculty.Departments[i].DegreesEn)
+			}
+			faculty.Specializations = append([]models.Specialization(nil), faculty.Specializations...)
+			faculty.Admission = cloneRules(faculty.Admission)
+			faculties[key] = faculty
+		}
+		uni.DetailedFaculties = faculties
+	}
+	return uni
+}
+
+func cloneStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
+
+func cloneRules(rules []models.AdmissionRule) []models.AdmissionRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	return append([]models.AdmissionRule(nil), rules...)
+}
+
+func cloneScholarship(s models.Scholarship) models.Scholarship {
+	s.UniversityIDs = cloneStrings(s.UniversityIDs)
+	s.Faculties = cloneStrings(s.Faculties)
+	s.Documents = cloneStrings(s.Documents)
+	s.DocumentsEn = cloneStrings(s.DocumentsEn)
+	return s
+}
+
+func cloneShortlist(list models.Shortlist) models.Shortlist {
+	list.Items = append([]models.ShortlistItem{}, list.Items...)
+	return list
+}
+
+// cloneSavedSearch copies the results and the pointer fields of the search
+// parameters of a saved search
+func cloneSavedSearch(search models.SavedSearch) models.SavedSearch {
+	search.Matches = append([]models.SearchMatch{}, search.Matches...)
+	search.Params = cloneSearchParams(search.Params)
+	return search
+}
+
+func cloneReview(r models.Review) models.Review {
+	r.Flags = cloneFlags(r.Flags)
+	return r
+}
+
+// cloneFlags copies flags, leaving none as nil like the SQLite store
+func cloneFlags(flags []models.ReviewFlag) []models.ReviewFlag {
+	if len(flags) == 0 {
+		return nil
+	}
+	return append([]models.ReviewFlag{}, flags...)
+}
+
+func cloneSearchParams(p models.SearchParams) models.SearchParams {
+	if p.FilterByFees != nil {
+		fees := *p.FilterByFees
+		p.FilterByFees = &fees
+	}
+	if p.FilterByGrade != nil {
+		grade := *p.FilterByGrade
+		p.FilterByGrade = &grade
+	}
+	if p.CertificateScore != nil {
+		score := *p.CertificateScore
+		p.CertificateScore = &score
+	}
+	return p
+}
@@ -0,0 +1,12 @@
+package data_test
+
+import (
+	"testing"
+
+	"roadtouniversities/data"
+	"roadtouniversities/data/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) data.Store { return data.NewMemoryStore() })
+}
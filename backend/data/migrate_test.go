@@ -0,0 +1,94 @@
+package data
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestSQLiteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reopen.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	uni := models.University{ID: "1", Name: "جامعة القاهرة", NameEn: "Cairo University", Type: "public", Region: "cairo"}
+	if err := s.PutUniversity(uni); err != nil {
+		t.Fatalf("PutUniversity: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Re-opening applies nothing twice and keeps the data
+	s, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("re-open: %v", err)
+	}
+	defer s.Close()
+	if _, found, err := s.GetUniversity("1"); err != nil || !found {
+		t.Fatalf("GetUniversity after re-open = %v, %v", found, err)
+	}
+	assertMigrated(t, s.db)
+}
+
+func TestSQLiteUpgrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrade.db")
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	// A database created before any later migration existed
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+	if err := applyMigration(db, migrations[0]); err != nil {
+		t.Fatalf("apply %s: %v", migrations[0].Name, err)
+	}
+	if _, err := db.Exec(`INSERT INTO universities (id, name, name_en, type, region, rating)
+		VALUES ('1', 'جامعة القاهرة', 'Cairo University', 'public', 'cairo', 4.1)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	db.Close()
+
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer s.Close()
+	uni, found, err := s.GetUniversity("1")
+	if err != nil || !found {
+		t.Fatalf("GetUniversity after upgrade = %v, %v", found, err)
+	}
+	if uni.NameEn != "Cairo University" || uni.Rating != 4.1 {
+		t.Fatalf("university after upgrade = %+v", uni)
+	}
+	assertMigrated(t, s.db)
+}
+
+// assertMigrated checks that every migration was recorded exactly once
+func assertMigrated(t *testing.T, db *sql.DB) {
+	t.Helper()
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	var count, latest int
+	if err := db.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &latest); err != nil {
+		t.Fatalf("read schema_migrations: %v", err)
+	}
+	if want := migrations[len(migrations)-1].Version; count != len(migrations) || latest != want {
+		t.Fatalf("schema_migrations has %d entries up to %d, want %d up to %d", count, latest, len(migrations), want)
+	}
+}
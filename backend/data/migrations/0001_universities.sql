@@ -0,0 +1,28 @@
+CREATE TABLE universities (
+    id                 TEXT PRIMARY KEY,
+    name               TEXT NOT NULL,
+    name_en            TEXT NOT NULL,
+    type               TEXT NOT NULL,
+    location           TEXT NOT NULL DEFAULT '',
+    location_en        TEXT NOT NULL DEFAULT '',
+    region             TEXT NOT NULL,
+    established        INTEGER NOT NULL DEFAULT 0,
+    rating             REAL NOT NULL DEFAULT 0,
+    fees_min           INTEGER NOT NULL DEFAULT 0,
+    fees_max           INTEGER NOT NULL DEFAULT 0,
+    faculties          TEXT NOT NULL DEFAULT '[]',
+    faculties_en       TEXT NOT NULL DEFAULT '[]',
+    specialties        TEXT NOT NULL DEFAULT '[]',
+    description        TEXT NOT NULL DEFAULT '',
+    description_en     TEXT NOT NULL DEFAULT '',
+    image              TEXT NOT NULL DEFAULT '',
+    min_grade          INTEGER NOT NULL DEFAULT 0,
+    max_grade          INTEGER NOT NULL DEFAULT 0,
+    students           INTEGER NOT NULL DEFAULT 0,
+    acceptance_rate    INTEGER NOT NULL DEFAULT 0,
+    employment_rate    INTEGER NOT NULL DEFAULT 0,
+    detailed_faculties TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_universities_type ON universities (type);
+CREATE INDEX idx_universities_region ON universities (region);
@@ -0,0 +1,2 @@
+-- Per-certificate admission rules; faculty rules live inside detailed_faculties
+ALTER TABLE universities ADD COLUMN admission TEXT NOT NULL DEFAULT '[]';
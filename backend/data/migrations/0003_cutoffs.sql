@@ -0,0 +1,15 @@
+-- Yearly coordination (Tansik) cut-offs per university faculty and track
+CREATE TABLE cutoffs (
+    university_id TEXT NOT NULL REFERENCES universities (id) ON DELETE CASCADE,
+    faculty_id    TEXT NOT NULL,
+    faculty       TEXT NOT NULL,
+    faculty_en    TEXT NOT NULL,
+    track         TEXT NOT NULL,
+    year          INTEGER NOT NULL,
+    percentage    REAL NOT NULL,
+    score         REAL NOT NULL DEFAULT 0,
+    max_score     REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (university_id, faculty_id, track, year)
+);
+
+CREATE INDEX idx_cutoffs_faculty ON cutoffs (faculty_id, year);
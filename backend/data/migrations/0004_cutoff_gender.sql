@@ -0,0 +1,23 @@
+-- Cut-offs may differ by gender; an empty gender applies to every applicant.
+-- SQLite cannot change a primary key in place, so the table is rebuilt.
+CREATE TABLE cutoffs_new (
+    university_id TEXT NOT NULL REFERENCES universities (id) ON DELETE CASCADE,
+    faculty_id    TEXT NOT NULL,
+    faculty       TEXT NOT NULL,
+    faculty_en    TEXT NOT NULL,
+    track         TEXT NOT NULL,
+    gender        TEXT NOT NULL DEFAULT '',
+    year          INTEGER NOT NULL,
+    percentage    REAL NOT NULL,
+    score         REAL NOT NULL DEFAULT 0,
+    max_score     REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (university_id, faculty_id, track, gender, year)
+);
+
+INSERT INTO cutoffs_new (university_id, faculty_id, faculty, faculty_en, track, year, percentage, score, max_score)
+    SELECT university_id, faculty_id, faculty, faculty_en, track, year, percentage, score, max_score FROM cutoffs;
+
+DROP TABLE cutoffs;
+ALTER TABLE cutoffs_new RENAME TO cutoffs;
+
+CREATE INDEX idx_cutoffs_faculty ON cutoffs (faculty_id, year);
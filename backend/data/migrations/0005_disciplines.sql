@@ -0,0 +1,10 @@
+-- Canonical faculty catalogue; university faculties link to it by name
+CREATE TABLE disciplines (
+    id             TEXT PRIMARY KEY,
+    name           TEXT NOT NULL,
+    name_en        TEXT NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    description_en TEXT NOT NULL DEFAULT '',
+    field          TEXT NOT NULL,
+    aliases        TEXT NOT NULL DEFAULT '[]'
+);
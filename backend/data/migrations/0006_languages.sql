@@ -0,0 +1,2 @@
+-- Languages of instruction
+ALTER TABLE universities ADD COLUMN languages TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+-- Cost of study besides tuition, as JSON; null when unknown
+ALTER TABLE universities ADD COLUMN costs TEXT NOT NULL DEFAULT 'null';
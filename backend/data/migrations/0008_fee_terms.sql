@@ -0,0 +1,3 @@
+-- Currency (ISO 4217) and period of university fees; empty means EGP, annual
+ALTER TABLE universities ADD COLUMN fees_currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE universities ADD COLUMN fees_period TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+-- Scholarship and financial aid catalogue. Universities, faculties and
+-- documents are JSON arrays; faculties is empty when every faculty is covered.
+CREATE TABLE scholarships (
+    id               TEXT PRIMARY KEY,
+    name             TEXT NOT NULL,
+    name_en          TEXT NOT NULL,
+    provider         TEXT NOT NULL,
+    provider_en      TEXT NOT NULL,
+    type             TEXT NOT NULL,
+    university_ids   TEXT NOT NULL DEFAULT '[]',
+    faculties        TEXT NOT NULL DEFAULT '[]',
+    min_grade        REAL NOT NULL DEFAULT 0,
+    discount_percent REAL NOT NULL DEFAULT 0,
+    discount_amount  INTEGER NOT NULL DEFAULT 0,
+    currency         TEXT NOT NULL DEFAULT '',
+    deadline         TEXT NOT NULL DEFAULT '',
+    documents        TEXT NOT NULL DEFAULT '[]',
+    documents_en     TEXT NOT NULL DEFAULT '[]'
+);
@@ -0,0 +1,19 @@
+-- Admissions calendar. Nationwide events such as Tansik phases have no
+-- university; a university's events go with it.
+CREATE TABLE events (
+    id             TEXT PRIMARY KEY,
+    university_id  TEXT REFERENCES universities (id) ON DELETE CASCADE,
+    faculty        TEXT NOT NULL DEFAULT '',
+    kind           TEXT NOT NULL,
+    title          TEXT NOT NULL,
+    title_en       TEXT NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    description_en TEXT NOT NULL DEFAULT '',
+    start_date     TEXT NOT NULL,
+    end_date       TEXT NOT NULL DEFAULT '',
+    location       TEXT NOT NULL DEFAULT '',
+    location_en    TEXT NOT NULL DEFAULT '',
+    url            TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_events_start ON events (start_date);
@@ -0,0 +1,29 @@
+-- Favorites and shortlists of accounts. Both go with their user; a
+-- university's favorites and shortlist items go with it. Items keep their
+-- order by position. Times are RFC 3339 in UTC.
+CREATE TABLE favorites (
+    user_id       TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    university_id TEXT NOT NULL REFERENCES universities (id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL,
+    PRIMARY KEY (user_id, university_id)
+);
+
+CREATE TABLE shortlists (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_shortlists_user ON shortlists (user_id);
+
+CREATE TABLE shortlist_items (
+    shortlist_id  TEXT NOT NULL REFERENCES shortlists (id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    university_id TEXT NOT NULL REFERENCES universities (id) ON DELETE CASCADE,
+    faculty       TEXT NOT NULL DEFAULT '',
+    department    TEXT NOT NULL DEFAULT '',
+    note          TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (shortlist_id, position)
+);
@@ -0,0 +1,17 @@
+-- Saved searches of accounts, with the search parameters and the results as
+-- of the last check held as JSON. They go with their user. Times are
+-- RFC 3339 in UTC.
+CREATE TABLE saved_searches (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    params     TEXT NOT NULL DEFAULT '{}',
+    currency   TEXT NOT NULL DEFAULT 'EGP',
+    notify     INTEGER NOT NULL DEFAULT 1,
+    matches    TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    checked_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches (user_id);
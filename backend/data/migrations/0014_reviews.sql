@@ -0,0 +1,22 @@
+-- Student reviews of universities and their faculties, with their moderation
+-- state. Sub-ratings are 0 when left out. Reviews go with their user and
+-- their university. Times are RFC 3339 in UTC.
+CREATE TABLE reviews (
+    id             TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    university_id  TEXT NOT NULL REFERENCES universities (id) ON DELETE CASCADE,
+    faculty        TEXT NOT NULL DEFAULT '',
+    overall        INTEGER NOT NULL,
+    teaching       INTEGER NOT NULL DEFAULT 0,
+    facilities     INTEGER NOT NULL DEFAULT 0,
+    housing        INTEGER NOT NULL DEFAULT 0,
+    career_support INTEGER NOT NULL DEFAULT 0,
+    title          TEXT NOT NULL DEFAULT '',
+    body           TEXT NOT NULL,
+    status         TEXT NOT NULL DEFAULT 'pending',
+    created_at     TEXT NOT NULL,
+    updated_at     TEXT NOT NULL
+);
+
+CREATE INDEX idx_reviews_university ON reviews (university_id, status);
+CREATE INDEX idx_reviews_user ON reviews (user_id);
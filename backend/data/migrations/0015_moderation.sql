@@ -0,0 +1,22 @@
+-- Review moderation: the flags raised by the abuse pre-filter and the reason
+-- for the last decision on a review, and the audit trail of decisions. Audit
+-- entries keep their review and university IDs after those are deleted.
+-- Times are RFC 3339 in UTC.
+ALTER TABLE reviews ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE reviews ADD COLUMN flags TEXT NOT NULL DEFAULT '[]';
+
+CREATE TABLE moderation_actions (
+    id              TEXT PRIMARY KEY,
+    review_id       TEXT NOT NULL,
+    university_id   TEXT NOT NULL,
+    moderator_id    TEXT NOT NULL REFERENCES users (id),
+    action          TEXT NOT NULL,
+    previous_status TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    flags           TEXT NOT NULL DEFAULT '[]',
+    created_at      TEXT NOT NULL
+);
+
+CREATE INDEX idx_moderation_actions_review ON moderation_actions (review_id);
+CREATE INDEX idx_moderation_actions_moderator ON moderation_actions (moderator_id);
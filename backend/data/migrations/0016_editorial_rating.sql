@@ -0,0 +1,4 @@
+-- The rating set by editors, kept apart from the rating computed from it and
+-- approved reviews. Ratings stored so far become the editorial ones.
+ALTER TABLE universities ADD COLUMN editorial_rating REAL NOT NULL DEFAULT 0;
+UPDATE universities SET editorial_rating = rating;
@@ -0,0 +1,212 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+// moderationDir holds the word lists of the abuse pre-filter, one .txt file
+// per language
+const moderationDir = "moderation"
+
+// MaxModerationReason bounds the reason given for a moderation decision
+const MaxModerationReason = 500
+
+// ModerationFilter narrows the audit entries a store lists; empty fields
+// match any entry
+type ModerationFilter struct {
+	ReviewID    string
+	ModeratorID string
+}
+
+// Matches reports whether a passes the filter
+func (f ModerationFilter) Matches(a models.ModerationAction) bool {
+	return (f.ReviewID == "" || a.ReviewID == f.ReviewID) &&
+		(f.ModeratorID == "" || a.ModeratorID == f.ModeratorID)
+}
+
+// arabicProclitics may precede a listed word in a review
+var arabicProclitics = []string{"وال", "بال", "فال", "ال", "و"}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// numberPattern finds runs of digits possibly broken up by spaces,
+	// dashes, dots or brackets, as phone numbers are written
+	numberPattern = regexp.MustCompile(`\+?\d[\d\s().-]*\d`)
+	// phonePattern matches the digits of such a run when they make an
+	// Egyptian phone number: a mobile number starting 010, 011, 012 or 015,
+	// or any number after the country code +20 or 0020. Years, year ranges,
+	// grades and fees do not.
+	phonePattern = regexp.MustCompile(`^01[0125]\d{8}$|^(?:\+|00)20\d{8,10}$`)
+)
+
+// AbuseFilter flags reviews for moderators: profanity from its word lists,
+// personal data such as phone numbers and email addresses, and text already
+// submitted in another review. It only flags; moderators decide.
+type AbuseFilter struct {
+	// phrases are the normalised words of every listed word or phrase
+	phrases [][]string
+}
+
+// NewAbuseFilter creates a filter flagging the given words and phrases
+func NewAbuseFilter(words []string) *AbuseFilter {
+	f := &AbuseFilter{}
+	seen := make(map[string]bool)
+	for _, w := range words {
+		phrase := moderationWords(w)
+		key := strings.Join(phrase, " ")
+		if len(phrase) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		f.phrases = append(f.phrases, phrase)
+	}
+	return f
+}
+
+// LoadAbuseFilter reads the word lists in the moderation directory of fsys:
+// .txt files with one word or phrase per line and # comments. A missing
+// directory gives a filter without word lists.
+func LoadAbuseFilter(fsys fs.FS) (*AbuseFilter, error) {
+	entries, err := fs.ReadDir(fsys, moderationDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewAbuseFilter(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.ToLower(path.Ext(entry.Name())) == ".txt" {
+			names = append(names, path.Join(moderationDir, entry.Name()))
+		}
+	}
+	sort.Strings(names)
+
+	var words []string
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				words = append(words, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return NewAbuseFilter(words), nil
+}
+
+// Check flags review, comparing its text with others for duplicates
+func (f *AbuseFilter) Check(review models.Review, others []models.Review) []models.ReviewFlag {
+	text := review.Title + "\n" + review.Body
+	var flags []models.ReviewFlag
+
+	words := moderationWords(text)
+	for _, phrase := range f.phrases {
+		if containsPhrase(words, phrase) {
+			flags = append(flags, models.ReviewFlag{Kind: models.FlagProfanity, Detail: strings.Join(phrase, " ")})
+		}
+	}
+
+	ascii := asciiDigits(text)
+	if emailPattern.MatchString(ascii) {
+		flags = append(flags, models.ReviewFlag{Kind: models.FlagPersonalData, Detail: "email address"})
+	}
+	for _, match := range numberPattern.FindAllString(ascii, -1) {
+		if phonePattern.MatchString(phoneDigits(match)) {
+			flags = append(flags, models.ReviewFlag{Kind: models.FlagPersonalData, Detail: "phone number"})
+			break
+		}
+	}
+
+	body := strings.Join(moderationWords(review.Body), " ")
+	for _, other := range others {
+		if other.ID != review.ID && body != "" && strings.Join(moderationWords(other.Body), " ") == body {
+			flags = append(flags, models.ReviewFlag{Kind: models.FlagDuplicate, Detail: "same text as review " + other.ID})
+			break
+		}
+	}
+	return flags
+}
+
+// moderationWords splits text into normalised words for matching, folding
+// Arabic variants and case
+func moderationWords(text string) []string {
+	return strings.FieldsFunc(search.NormalizeArabic(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsPhrase reports whether phrase occurs in words as consecutive words
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, w := range phrase {
+			if !wordMatches(words[i+j], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// wordMatches reports whether word is listed, alone or after an Arabic
+// proclitic
+func wordMatches(word, listed string) bool {
+	if word == listed {
+		return true
+	}
+	for _, prefix := range arabicProclitics {
+		if rest, found := strings.CutPrefix(word, prefix); found && rest == listed {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiDigits rewrites Arabic-Indic and Persian digits as ASCII ones
+func asciiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		}
+		return r
+	}, s)
+}
+
+// phoneDigits drops the separators from a number as written, keeping its
+// digits and a leading +
+func phoneDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r == '+' {
+			return r
+		}
+		return -1
+	}, s)
+}
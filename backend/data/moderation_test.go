@@ -0,0 +1,98 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"roadtouniversities/models"
+)
+
+func TestAbuseFilterCheck(t *testing.T) {
+	f := NewAbuseFilter([]string{"idiot", "waste of money", "حمار"})
+	copied := models.Review{ID: "r1", Body: "The labs are old, but the professors care a lot."}
+	tests := []struct {
+		name   string
+		review models.Review
+		want   []models.ReviewFlag
+	}{
+		{"clean", models.Review{Body: "Good teaching and a friendly campus."}, nil},
+		{"word in any case", models.Review{Title: "IDIOT admins", Body: "Registration was slow."},
+			[]models.ReviewFlag{{Kind: models.FlagProfanity, Detail: "idiot"}}},
+		{"not inside another word", models.Review{Body: "Idiotic rules, but fair exams."}, nil},
+		{"phrase across punctuation", models.Review{Body: "A waste, of money overall."},
+			[]models.ReviewFlag{{Kind: models.FlagProfanity, Detail: "waste of money"}}},
+		{"Arabic after a proclitic", models.Review{Body: "والحمار اللي في شؤون الطلاب"},
+			[]models.ReviewFlag{{Kind: models.FlagProfanity, Detail: "حمار"}}},
+		{"email address", models.Review{Body: "Write to me at student.one@example.com for notes."},
+			[]models.ReviewFlag{{Kind: models.FlagPersonalData, Detail: "email address"}}},
+		{"copied text", models.Review{ID: "r2", Body: "the labs are old but the PROFESSORS care a lot"},
+			[]models.ReviewFlag{{Kind: models.FlagDuplicate, Detail: "same text as review r1"}}},
+		{"edited review is not its own duplicate", copied, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Check(tt.review, []models.Review{copied}); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Check = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAbuseFilter(t *testing.T) {
+	f, err := LoadAbuseFilter(fstest.MapFS{
+		"moderation/en.txt":      {Data: []byte("# comment\nidiot\n\nidiot\n")},
+		"moderation/ar.txt":      {Data: []byte("حمار\n")},
+		"moderation/notes.md":    {Data: []byte("ignored\n")},
+		"moderation/extra/x.txt": {Data: []byte("nested\n")},
+	})
+	if err != nil {
+		t.Fatalf("LoadAbuseFilter: %v", err)
+	}
+	if want := [][]string{{"حمار"}, {"idiot"}}; !reflect.DeepEqual(f.phrases, want) {
+		t.Errorf("phrases = %v, want %v", f.phrases, want)
+	}
+
+	f, err = LoadAbuseFilter(fstest.MapFS{})
+	if err != nil || len(f.phrases) != 0 {
+		t.Errorf("LoadAbuseFilter without word lists = %v, %v; want an empty filter", f.phrases, err)
+	}
+}
+
+func TestDefaultSeedAbuseFilter(t *testing.T) {
+	f, err := LoadAbuseFilter(DefaultSeed())
+	if err != nil {
+		t.Fatalf("LoadAbuseFilter: %v", err)
+	}
+	if len(f.phrases) == 0 {
+		t.Error("default seed has no moderation word lists")
+	}
+}
+
+func TestAbuseFilterPhoneNumbers(t *testing.T) {
+	f := NewAbuseFilter(nil)
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"Call me on 01012345678 for notes.", true},
+		{"WhatsApp 0122 345 6789 any time.", true},
+		{"My number is (015) 1234-5678.", true},
+		{"Reach me at +20 10 1234 5678.", true},
+		{"Or 0020-2-2345-6789 at home.", true},
+		{"رقمي ٠١١٢٣٤٥٦٧٨٩ للتواصل", true},
+		{"I studied there in 2023-2024 and 2024-2025.", false},
+		{"Fees rose to 120,000 EGP, about 2,400 USD, in 2025.", false},
+		{"Got 98.5% in thanaweya, ranked 1234567 nationally.", false},
+		{"An eleven digit ID 12345678901 is not a phone.", false},
+	}
+	for _, tt := range tests {
+		got := false
+		for _, flag := range f.Check(models.Review{Body: tt.body}, nil) {
+			got = got || flag.Kind == models.FlagPersonalData
+		}
+		if got != tt.want {
+			t.Errorf("%q flagged as personal data = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
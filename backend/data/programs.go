@@ -0,0 +1,287 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+// ErrInvalidDegreeType is returned for an unknown degree type filter
+var ErrInvalidDegreeType = errors.New("invalid degree type")
+
+// programSortFields maps program sortBy keys to the field they order by
+var programSortFields = map[string]func(p models.Program) float64{
+	"fees":     func(p models.Program) float64 { return float64(p.AnnualFees) },
+	"duration": func(p models.Program) float64 { return float64(p.DurationYears) },
+	"minGrade": func(p models.Program) float64 { return float64(p.MinGrade) },
+	"rating":   func(p models.Program) float64 { return p.Rating },
+}
+
+// program is a flattened program with the records it came from
+type program struct {
+	models.Program
+	uni        models.University
+	facultyKey string
+}
+
+// ListPrograms flattens every department of every detailed faculty into a
+// program, in catalogue order with faculties sorted by name
+func ListPrograms(unis []models.University) []models.Program {
+	var programs []models.Program
+	for _, p := range flattenPrograms(unis) {
+		programs = append(programs, p.Program)
+	}
+	return programs
+}
+
+func flattenPrograms(unis []models.University) []program {
+	var programs []program
+	for _, uni := range unis {
+		keys := make([]string, 0, len(uni.DetailedFaculties))
+		for key := range uni.DetailedFaculties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			faculty := uni.DetailedFaculties[key]
+			for _, dept := range faculty.Departments {
+				programs = append(programs, program{
+					Program:    newProgram(uni, key, faculty, dept),
+					uni:        uni,
+					facultyKey: key,
+				})
+			}
+		}
+	}
+	return programs
+}
+
+func newProgram(uni models.University, key string, faculty models.Faculty, dept models.Department) models.Program {
+	p := models.Program{
+		UniversityID:   uni.ID,
+		University:     uni.Name,
+		UniversityEn:   uni.NameEn,
+		UniversityType: uni.Type,
+		Region:         uni.Region,
+		City:           search.City(uni.Location),
+		CityEn:         search.City(uni.LocationEn),
+		Faculty:        key,
+		FacultyEn:      faculty.NameEn,
+		Department:     dept.Name,
+		DepartmentEn:   dept.NameEn,
+		Duration:       dept.Duration,
+		DurationEn:     dept.DurationEn,
+		DurationYears:  leadingNumber(dept.DurationEn),
+		AnnualFees:     dept.Fees,
+		AnnualFeesEn:   dept.FeesEn,
+		Degrees:        dept.Degrees,
+		DegreesEn:      dept.DegreesEn,
+		DegreeTypes:    degreeTypes(dept.DegreesEn),
+		MinGrade:       uni.MinGrade,
+		Rating:         uni.Rating,
+	}
+
+	// Fall back to the faculty's, then the university's, upper fee bound
+	if p.AnnualFees == 0 {
+		p.AnnualFees, p.AnnualFeesEn = faculty.AnnualFees.Max, faculty.AnnualFeesEn
+	}
+	if p.AnnualFees == 0 {
+		p.AnnualFees = uni.Fees.Max
+	}
+
+	// A faculty's own Thanawiya minimum overrides the university's
+	if rule, found := findRule(faculty.Admission, models.CertificateThanawiya); found && rule.MinPercentage > 0 {
+		p.MinGrade = int(rule.MinPercentage)
+	}
+	return p
+}
+
+// leadingNumber returns the first integer in s, or 0 when there is none, so
+// "6 years" gives 6
+func leadingNumber(s string) int {
+	start := strings.IndexFunc(s, unicode.IsDigit)
+	if start < 0 {
+		return 0
+	}
+	end := start
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[start:end])
+	return n
+}
+
+// degreeTypes classifies English degree names such as "Bachelor of Science"
+func degreeTypes(degrees []string) []string {
+	var types []string
+	add := func(t string) {
+		for _, existing := range types {
+			if existing == t {
+				return
+			}
+		}
+		types = append(types, t)
+	}
+
+	for _, degree := range degrees {
+		d := strings.ToLower(degree)
+		switch {
+		case strings.Contains(d, "bachelor"), strings.Contains(d, "licence"):
+			add(models.DegreeBachelor)
+		case strings.Contains(d, "master"), strings.Contains(d, "mba"):
+			add(models.DegreeMaster)
+		case strings.Contains(d, "doctor"), strings.Contains(d, "phd"):
+			add(models.DegreeDoctorate)
+		case strings.Contains(d, "diploma"):
+			add(models.DegreeDiploma)
+		}
+	}
+	return types
+}
+
+// SearchPrograms filters the programs of unis by params and orders them by
+// params.SortBy, which accepts fees, duration, minGrade and rating. Every
+// query term must appear in the program's department, faculty, degree,
+// university or city names. Without a sortBy, programs keep catalogue
+// order.
+func SearchPrograms(unis []models.University, params models.ProgramSearchParams) ([]models.Program, error) {
+	sortKeys, err := parseSortKeys(params.SortBy, params.SortOrder, func(field string) bool {
+		_, known := programSortFields[field]
+		return known
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := validBackground(params.EducationalBackground); err != nil {
+		return nil, err
+	}
+	if params.DegreeType != "" && params.DegreeType != "all" && !models.IsValidDegreeType(params.DegreeType) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDegreeType, params.DegreeType)
+	}
+	background := params.EducationalBackground
+	if background == "all" {
+		background = ""
+	}
+	queryTerms := search.Tokenize(params.SearchQuery)
+	city := search.NormalizeArabic(strings.TrimSpace(params.City))
+
+	var results []models.Program
+	for _, p := range flattenPrograms(unis) {
+		if params.SelectedType != "" && params.SelectedType != "all" && p.UniversityType != params.SelectedType {
+			continue
+		}
+		if params.SelectedRegion != "" && params.SelectedRegion != "all" && p.Region != params.SelectedRegion {
+			continue
+		}
+		if city != "" && search.NormalizeArabic(p.City) != city && search.NormalizeArabic(p.CityEn) != city {
+			continue
+		}
+		if params.DegreeType != "" && params.DegreeType != "all" && !containsString(p.DegreeTypes, params.DegreeType) {
+			continue
+		}
+		if params.FilterByFees != nil && p.AnnualFees > *params.FilterByFees {
+			continue
+		}
+		// Programs without a duration in years, such as graduate
+		// specializations, never pass a duration ceiling
+		if params.FilterByDuration != nil && (p.DurationYears == 0 || p.DurationYears > *params.FilterByDuration) {
+			continue
+		}
+		if len(queryTerms) > 0 && !matchesAllTerms(p.Program, queryTerms) {
+			continue
+		}
+
+		if background != "" {
+			eligible, reason := programEligibility(p, background, params.FilterByGrade, params.CertificateScore)
+			if !eligible {
+				continue
+			}
+			p.EligibilityReason = reason
+		} else if params.FilterByGrade != nil && p.MinGrade > *params.FilterByGrade {
+			continue
+		}
+
+		results = append(results, p.Program)
+	}
+
+	sortPrograms(results, sortKeys)
+	return results, nil
+}
+
+// programEligibility checks a student against the program's faculty rules,
+// or the university's when the faculty has none
+func programEligibility(p program, certificate string, grade *int, score *float64) (bool, string) {
+	rules := p.uni.DetailedFaculties[p.facultyKey].Admission
+	if len(rules) == 0 {
+		rules = AdmissionRules(p.uni)
+	}
+	rule, found := findRule(rules, certificate)
+	if !found {
+		return false, "does not accept " + certificate + " certificates"
+	}
+	return checkRule(rule, grade, score)
+}
+
+// matchesAllTerms reports whether every term occurs in the program's names
+func matchesAllTerms(p models.Program, terms []string) bool {
+	texts := []string{
+		p.Department, p.DepartmentEn, p.Faculty, p.FacultyEn,
+		p.University, p.UniversityEn, p.City, p.CityEn,
+	}
+	texts = append(texts, p.Degrees...)
+	texts = append(texts, p.DegreesEn...)
+
+	have := make(map[string]bool)
+	for _, text := range texts {
+		for _, term := range search.Tokenize(text) {
+			have[term] = true
+		}
+	}
+	for _, term := range terms {
+		if !have[term] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortPrograms stably orders programs by keys, breaking ties by university
+// ID, then faculty. A faculty's departments keep the order they are listed
+// in.
+func sortPrograms(programs []models.Program, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(programs, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := programSortFields[key.Field](programs[i]), programSortFields[key.Field](programs[j])
+			if a == b {
+				continue
+			}
+			if key.Desc {
+				return a > b
+			}
+			return a < b
+		}
+		if programs[i].UniversityID != programs[j].UniversityID {
+			return lessID(programs[i].UniversityID, programs[j].UniversityID)
+		}
+		return programs[i].Faculty < programs[j].Faculty
+	})
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
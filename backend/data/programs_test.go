@@ -0,0 +1,173 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+// programCatalogue lists its universities out of ID order, as a store may
+func programCatalogue() []models.University {
+	return []models.University{
+		{ID: "10", Name: "جامعة الإسكندرية", NameEn: "Alexandria University", Type: "public", Region: "alexandria",
+			Location: "الإسكندرية، مصر", LocationEn: "Alexandria, Egypt", MinGrade: 80, Rating: 4.1,
+			Fees: models.FeesRange{Min: 1000, Max: 5000},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {NameEn: "Faculty of Engineering", Departments: []models.Department{
+					{Name: "هندسة الحاسب", NameEn: "Computer Engineering", DurationEn: "5 years", Fees: 5000,
+						DegreesEn: []string{"Bachelor of Science in Computer Engineering"}},
+					{Name: "الهندسة المدنية", NameEn: "Civil Engineering", DurationEn: "5 years",
+						DegreesEn: []string{"Bachelor of Science in Civil Engineering"}},
+				}},
+			}},
+		{ID: "2", Name: "جامعة القاهرة", NameEn: "Cairo University", Type: "public", Region: "cairo",
+			Location: "الجيزة، مصر", LocationEn: "Giza, Egypt", MinGrade: 85, Rating: 4.5,
+			Fees: models.FeesRange{Min: 2000, Max: 6000},
+			DetailedFaculties: map[string]models.Faculty{
+				"الطب": {NameEn: "Faculty of Medicine", AnnualFees: models.FeesRange{Max: 8000},
+					Admission: []models.AdmissionRule{{Certificate: models.CertificateThanawiya, MinPercentage: 95}},
+					Departments: []models.Department{
+						{Name: "الطب والجراحة", NameEn: "Medicine and Surgery", DurationEn: "6 years",
+							DegreesEn: []string{"Bachelor of Medicine and Surgery"}},
+						{Name: "الجراحة العامة", NameEn: "General Surgery",
+							DegreesEn: []string{"Master of Surgery"}},
+					}},
+				"الحاسبات والمعلومات": {NameEn: "Faculty of Computers and Artificial Intelligence", Departments: []models.Department{
+					{Name: "علوم الحاسب", NameEn: "Computer Science", DurationEn: "4 years", Fees: 5000,
+						DegreesEn: []string{"Bachelor of Computer Science"}},
+				}},
+			}},
+		{ID: "3", Name: "الجامعة الأمريكية بالقاهرة", NameEn: "American University in Cairo", Type: "private", Region: "cairo",
+			Location: "القاهرة الجديدة، مصر", LocationEn: "New Cairo, Egypt", MinGrade: 85, Rating: 4.5,
+			Fees: models.FeesRange{Min: 200000, Max: 300000},
+			Admission: []models.AdmissionRule{
+				{Certificate: models.CertificateThanawiya, MinPercentage: 80},
+				{Certificate: models.CertificateAmerican, MinScore: 1200, ScoreScale: 1600},
+			},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {NameEn: "School of Sciences and Engineering", Departments: []models.Department{
+					{Name: "هندسة الحاسب", NameEn: "Computer Engineering", DurationEn: "5 years", Fees: 300000,
+						DegreesEn: []string{"Bachelor of Science in Computer Engineering"}},
+				}},
+			}},
+	}
+}
+
+// programNames lists the English department names with their university IDs
+func programNames(programs []models.Program) []string {
+	names := make([]string, len(programs))
+	for i, p := range programs {
+		names[i] = p.UniversityID + " " + p.DepartmentEn
+	}
+	return names
+}
+
+func TestSearchPrograms(t *testing.T) {
+	num := func(v int) *int { return &v }
+	score := func(v float64) *float64 { return &v }
+	all := []string{
+		"10 Computer Engineering", "10 Civil Engineering",
+		"2 Computer Science", "2 Medicine and Surgery", "2 General Surgery",
+		"3 Computer Engineering",
+	}
+	tests := []struct {
+		name   string
+		params models.ProgramSearchParams
+		want   []string
+	}{
+		{"catalogue order", models.ProgramSearchParams{}, all},
+		{"every term", models.ProgramSearchParams{SearchQuery: "computer engineering"},
+			[]string{"10 Computer Engineering", "3 Computer Engineering"}},
+		{"Arabic term", models.ProgramSearchParams{SearchQuery: "الجراحة"},
+			[]string{"2 Medicine and Surgery", "2 General Surgery"}},
+		{"type", models.ProgramSearchParams{SelectedType: "private"}, []string{"3 Computer Engineering"}},
+		{"region", models.ProgramSearchParams{SelectedRegion: "alexandria"},
+			[]string{"10 Computer Engineering", "10 Civil Engineering"}},
+		{"city", models.ProgramSearchParams{City: "Giza"},
+			[]string{"2 Computer Science", "2 Medicine and Surgery", "2 General Surgery"}},
+		{"degree type", models.ProgramSearchParams{DegreeType: models.DegreeMaster}, []string{"2 General Surgery"}},
+		// Departments without fees fall back to the faculty's, then the
+		// university's
+		{"fees", models.ProgramSearchParams{FilterByFees: num(5000)},
+			[]string{"10 Computer Engineering", "10 Civil Engineering", "2 Computer Science"}},
+		// Programs without a duration in years never pass
+		{"duration", models.ProgramSearchParams{FilterByDuration: num(5)},
+			[]string{"10 Computer Engineering", "10 Civil Engineering", "2 Computer Science", "3 Computer Engineering"}},
+		// The faculty's own minimum overrides the university's
+		{"grade", models.ProgramSearchParams{FilterByGrade: num(90)},
+			[]string{"10 Computer Engineering", "10 Civil Engineering", "2 Computer Science", "3 Computer Engineering"}},
+		{"certificate", models.ProgramSearchParams{EducationalBackground: models.CertificateAmerican, CertificateScore: score(1300)},
+			[]string{"10 Computer Engineering", "10 Civil Engineering", "2 Computer Science", "3 Computer Engineering"}},
+		{"certificate score too low", models.ProgramSearchParams{EducationalBackground: models.CertificateAmerican, CertificateScore: score(1100)},
+			[]string{"10 Computer Engineering", "10 Civil Engineering", "2 Computer Science"}},
+		{"all backgrounds", models.ProgramSearchParams{EducationalBackground: "all", DegreeType: "all"}, all},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			programs, err := SearchPrograms(programCatalogue(), tt.params)
+			if err != nil {
+				t.Fatalf("SearchPrograms: %v", err)
+			}
+			if got := programNames(programs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("programs = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchProgramsSort(t *testing.T) {
+	tests := []struct {
+		sortBy, sortOrder string
+		want              []string
+	}{
+		// Ties go by university ID, numerically, then faculty
+		{"fees", "", []string{
+			"2 Computer Science", "10 Computer Engineering", "10 Civil Engineering",
+			"2 Medicine and Surgery", "2 General Surgery", "3 Computer Engineering",
+		}},
+		{"rating", "desc", []string{
+			"2 Computer Science", "2 Medicine and Surgery", "2 General Surgery", "3 Computer Engineering",
+			"10 Computer Engineering", "10 Civil Engineering",
+		}},
+		{"minGrade:desc,fees:asc", "", []string{
+			"2 Medicine and Surgery", "2 General Surgery", "2 Computer Science", "3 Computer Engineering",
+			"10 Computer Engineering", "10 Civil Engineering",
+		}},
+		{"duration", "", []string{
+			"2 General Surgery", "2 Computer Science", "3 Computer Engineering", "10 Computer Engineering",
+			"10 Civil Engineering", "2 Medicine and Surgery",
+		}},
+	}
+	for _, tt := range tests {
+		programs, err := SearchPrograms(programCatalogue(), models.ProgramSearchParams{SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+		if err != nil {
+			t.Fatalf("SearchPrograms(%s): %v", tt.sortBy, err)
+		}
+		if got := programNames(programs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sorted by %s %s = %q\nwant %q", tt.sortBy, tt.sortOrder, got, tt.want)
+		}
+	}
+}
+
+func TestSearchProgramsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params models.ProgramSearchParams
+		want   error
+	}{
+		{"unknown sort key", models.ProgramSearchParams{SortBy: "students"}, ErrInvalidSort},
+		{"relevance is not a program key", models.ProgramSearchParams{SortBy: SortRelevance}, ErrInvalidSort},
+		{"unknown order", models.ProgramSearchParams{SortBy: "fees", SortOrder: "up"}, ErrInvalidSort},
+		{"unknown degree type", models.ProgramSearchParams{DegreeType: "associate"}, ErrInvalidDegreeType},
+		{"unknown background", models.ProgramSearchParams{EducationalBackground: "gcse"}, ErrInvalidBackground},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SearchPrograms(programCatalogue(), tt.params); !errors.Is(err, tt.want) {
+				t.Errorf("SearchPrograms = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,416 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+var (
+	// ErrInvalidWeights is returned for unknown factors or negative weights
+	ErrInvalidWeights = errors.New("invalid weights")
+	// ErrInvalidProfile is returned for an invalid budget, region, language
+	// or limit in a recommendation request
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// Recommendation limits
+const (
+	DefaultRecommendations = 10
+	MaxRecommendations     = 50
+	maxRecommendedPrograms = 5
+)
+
+// DefaultRecommendationWeights returns the weights used when no weights file
+// is configured
+func DefaultRecommendationWeights() models.RecommendationWeights {
+	return models.RecommendationWeights{
+		models.FactorGrade:      3,
+		models.FactorInterests:  3,
+		models.FactorBudget:     2,
+		models.FactorRegion:     1.5,
+		models.FactorLanguage:   1,
+		models.FactorEmployment: 1,
+		models.FactorRating:     1,
+	}
+}
+
+// LoadRecommendationWeights reads factor weights from a JSON or YAML file
+// such as
+//
+//	grade: 4
+//	interests: 2.5
+//
+// Factors the file leaves out keep their default weight.
+func LoadRecommendationWeights(path string) (models.RecommendationWeights, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides models.RecommendationWeights
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	weights, err := MergeWeights(DefaultRecommendationWeights(), overrides)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// MergeWeights returns base with overrides applied. Every factor must be
+// known, weights must not be negative and at least one must be positive.
+func MergeWeights(base, overrides models.RecommendationWeights) (models.RecommendationWeights, error) {
+	merged := make(models.RecommendationWeights, len(base))
+	for factor, w := range base {
+		merged[factor] = w
+	}
+	for factor, w := range overrides {
+		if !models.IsValidFactor(factor) {
+			return nil, fmt.Errorf("%w: unknown factor %q, expected one of %s", ErrInvalidWeights, factor, strings.Join(models.RecommendationFactors, ", "))
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("%w: %s must be a non-negative number", ErrInvalidWeights, factor)
+		}
+		merged[factor] = w
+	}
+
+	total := 0.0
+	for _, w := range merged {
+		total += w
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("%w: at least one weight must be positive", ErrInvalidWeights)
+	}
+	return merged, nil
+}
+
+// interestAliases expands abbreviations students commonly type
+var interestAliases = map[string]string{
+	"ai": "artificial intelligence",
+	"cs": "computer science",
+	"it": "information technology",
+	"is": "information systems",
+}
+
+// interest is a student interest prepared for matching
+type interest struct {
+	text       string
+	terms      []string
+	discipline string // catalogue ID when the interest names a faculty
+}
+
+// Recommend ranks the universities matching the student's profile. A
+// university is left out when it does not accept the certificate, its
+// cut-off is more than reachMargin points above the student's grade, or its
+// lowest fees exceed the budget. The rest are scored on each factor the
+// profile covers, weighted by weights with req.Weights applied on top.
+// Without a certificate, a percentage is taken as Thanawiya.
+func Recommend(unis []models.University, faculties *FacultyCatalog, weights models.RecommendationWeights, req models.RecommendationRequest) (models.RecommendationResponse, error) {
+	var resp models.RecommendationResponse
+
+	weights, err := MergeWeights(weights, req.Weights)
+	if err != nil {
+		return resp, err
+	}
+	if err := validateProfile(&req); err != nil {
+		return resp, err
+	}
+	resp.Weights = weights
+
+	interests := make([]interest, 0, len(req.Interests))
+	for _, text := range req.Interests {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		in := interest{text: text, terms: search.Tokenize(text)}
+		if expanded, found := interestAliases[strings.ToLower(text)]; found {
+			in.terms = search.Tokenize(expanded)
+		}
+		if d, found := faculties.Resolve(text, text); found {
+			in.discipline = d.ID
+		}
+		interests = append(interests, in)
+	}
+
+	for _, uni := range unis {
+		rec, ok := recommend(uni, faculties, weights, req, interests)
+		if ok {
+			resp.Recommendations = append(resp.Recommendations, rec)
+		}
+	}
+
+	sort.SliceStable(resp.Recommendations, func(i, j int) bool {
+		a, b := resp.Recommendations[i], resp.Recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.University.Rating != b.University.Rating {
+			return a.University.Rating > b.University.Rating
+		}
+		return lessID(a.University.ID, b.University.ID)
+	})
+
+	resp.Total = len(resp.Recommendations)
+	if len(resp.Recommendations) > req.Limit {
+		resp.Recommendations = resp.Recommendations[:req.Limit]
+	}
+	if resp.Recommendations == nil {
+		resp.Recommendations = []models.Recommendation{}
+	}
+	return resp, nil
+}
+
+// validateProfile checks the request and fills in its defaults
+func validateProfile(req *models.RecommendationRequest) error {
+	if req.Percentage != nil && (*req.Percentage < 0 || *req.Percentage > 100) {
+		return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidPercentage)
+	}
+	if req.Certificate == "" && req.Percentage != nil {
+		req.Certificate = models.CertificateThanawiya
+	}
+	if req.Certificate != "" && !models.IsValidCertificate(req.Certificate) {
+		return fmt.Errorf("%w: unknown certificate %q", ErrInvalidBackground, req.Certificate)
+	}
+	if req.Budget != nil && *req.Budget < 0 {
+		return fmt.Errorf("%w: budget must not be negative", ErrInvalidProfile)
+	}
+	for _, region := range req.Regions {
+		if !models.IsValidRegion(region) {
+			return fmt.Errorf("%w: unknown region %q", ErrInvalidProfile, region)
+		}
+	}
+	if req.Language != "" && !models.IsValidLanguage(req.Language) {
+		return fmt.Errorf("%w: language must be one of %s", ErrInvalidProfile, strings.Join(models.Languages, ", "))
+	}
+	if req.Limit < 0 || req.Limit > MaxRecommendations {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidProfile, MaxRecommendations)
+	}
+	if req.Limit == 0 {
+		req.Limit = DefaultRecommendations
+	}
+	return nil
+}
+
+// recommend scores one university, reporting false when it is left out
+func recommend(uni models.University, faculties *FacultyCatalog, weights models.RecommendationWeights, req models.RecommendationRequest, interests []interest) (models.Recommendation, bool) {
+	var factors []models.FactorScore
+	add := func(factor string, score float64, reason string) {
+		factors = append(factors, models.FactorScore{Factor: factor, Weight: weights[factor], Score: round2(score), Reason: reason})
+	}
+
+	if req.Certificate != "" {
+		score, reason, ok := gradeScore(uni, req)
+		if !ok {
+			return models.Recommendation{}, false
+		}
+		if reason != "" {
+			add(models.FactorGrade, score, reason)
+		}
+	}
+
+	if req.Budget != nil {
+		budget := *req.Budget
+		switch {
+		case uni.Fees.Min > budget:
+			return models.Recommendation{}, false
+		case uni.Fees.Max <= budget:
+			add(models.FactorBudget, 1, fmt.Sprintf("fees of %d–%d are within the budget", uni.Fees.Min, uni.Fees.Max))
+		default:
+			share := float64(budget-uni.Fees.Min) / float64(uni.Fees.Max-uni.Fees.Min)
+			add(models.FactorBudget, share, fmt.Sprintf("only fees up to %d of the %d–%d range are within the budget", budget, uni.Fees.Min, uni.Fees.Max))
+		}
+	}
+
+	if len(req.Regions) > 0 {
+		if containsString(req.Regions, uni.Region) {
+			add(models.FactorRegion, 1, "in the preferred region "+uni.Region)
+		} else {
+			add(models.FactorRegion, 0, "outside the preferred regions, in "+uni.Region)
+		}
+	}
+
+	var matched []interest
+	if len(interests) > 0 {
+		texts, disciplines := universityTexts(uni, faculties)
+		var names []string
+		for _, in := range interests {
+			if disciplines[in.discipline] || matchesText(texts, in.terms) {
+				matched = append(matched, in)
+				names = append(names, in.text)
+			}
+		}
+		reason := "teaches none of the interests"
+		if len(names) > 0 {
+			reason = "teaches " + strings.Join(names, ", ")
+		}
+		add(models.FactorInterests, float64(len(matched))/float64(len(interests)), reason)
+	}
+
+	if req.Language != "" {
+		switch {
+		case len(uni.Languages) == 0:
+			add(models.FactorLanguage, 0.5, "language of instruction not published")
+		case containsString(uni.Languages, req.Language):
+			add(models.FactorLanguage, 1, "teaches in "+req.Language)
+		default:
+			add(models.FactorLanguage, 0, "teaches in "+strings.Join(uni.Languages, " and "))
+		}
+	}
+
+	if uni.EmploymentRate > 0 {
+		add(models.FactorEmployment, float64(uni.EmploymentRate)/100, fmt.Sprintf("%d%% of graduates employed", uni.EmploymentRate))
+	} else {
+		add(models.FactorEmployment, 0.5, "employment rate not published")
+	}
+	add(models.FactorRating, uni.Rating/5, fmt.Sprintf("rated %.1f of 5", uni.Rating))
+
+	total := 0.0
+	for _, f := range factors {
+		total += f.Weight
+	}
+	rec := models.Recommendation{University: uni, Factors: factors}
+	if total > 0 {
+		for i, f := range factors {
+			rec.Factors[i].Points = round2(f.Weight * f.Score / total * 100)
+			rec.Score += f.Weight * f.Score
+		}
+		rec.Score = round2(rec.Score / total * 100)
+	}
+	if len(matched) > 0 {
+		rec.Programs = matchingPrograms(uni, faculties, matched, req.Budget)
+	}
+	return rec, true
+}
+
+// gradeScore scores the student's margin over the university's cut-off for
+// their certificate: 1 with safeMargin to spare, 0.6 at the cut-off, falling
+// to 0 at reachMargin below it. It reports false when the university does not
+// accept the certificate or the student is further below, and no reason when
+// the profile holds no grade to score.
+func gradeScore(uni models.University, req models.RecommendationRequest) (float64, string, bool) {
+	rule, found := findRule(AdmissionRules(uni), req.Certificate)
+	if !found {
+		return 0, "", false
+	}
+	cut := ruleCutoff(rule, "")
+	if req.Percentage == nil && req.CertificateScore == nil {
+		return 0, "", true
+	}
+	if cut.value <= 0 {
+		return 1, "no minimum grade is published for " + req.Certificate, true
+	}
+
+	var percentage float64
+	switch {
+	case req.CertificateScore != nil && rule.ScoreScale > 0:
+		percentage = *req.CertificateScore / rule.ScoreScale * 100
+	case req.Percentage != nil:
+		percentage = *req.Percentage
+	default:
+		return 0, "", true
+	}
+
+	margin := percentage - cut.value
+	switch {
+	case margin >= safeMargin:
+		return 1, fmt.Sprintf("%.1f points above the %.1f%% minimum", margin, cut.value), true
+	case margin >= 0:
+		return 0.6 + 0.4*margin/safeMargin, fmt.Sprintf("%.1f points above the %.1f%% minimum", margin, cut.value), true
+	case margin >= -reachMargin:
+		return 0.3 * (1 + margin/reachMargin), fmt.Sprintf("%.1f points below the %.1f%% minimum", -margin, cut.value), true
+	default:
+		return 0, "", false
+	}
+}
+
+// universityTexts returns the names a university's interests are matched
+// against, tokenized, and the catalogue IDs of its faculties
+func universityTexts(uni models.University, faculties *FacultyCatalog) ([][]string, map[string]bool) {
+	names := append([]string{}, uni.Specialties...)
+	disciplines := make(map[string]bool)
+	faculties.eachFaculty([]models.University{uni}, func(id string, f universityFaculty) {
+		disciplines[id] = true
+		names = append(names, f.name, f.nameEn)
+		if d, found := faculties.Discipline(id); found {
+			names = append(names, disciplineNames(d)...)
+		}
+	})
+	for _, faculty := range uni.DetailedFaculties {
+		for _, dept := range faculty.Departments {
+			names = append(names, dept.Name, dept.NameEn)
+		}
+		for _, spec := range faculty.Specializations {
+			names = append(names, spec.Name, spec.NameEn)
+		}
+	}
+
+	texts := make([][]string, 0, len(names))
+	for _, name := range names {
+		if terms := search.Tokenize(name); len(terms) > 0 {
+			texts = append(texts, terms)
+		}
+	}
+	return texts, disciplines
+}
+
+// matchesText reports whether one text holds every term
+func matchesText(texts [][]string, terms []string) bool {
+	if len(terms) == 0 {
+		return false
+	}
+	for _, text := range texts {
+		all := true
+		for _, term := range terms {
+			if !containsString(text, term) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingPrograms returns the university's programs matching an interest
+// and within budget, cheapest first
+func matchingPrograms(uni models.University, faculties *FacultyCatalog, interests []interest, budget *int) []models.Program {
+	var result []models.Program
+	for _, p := range flattenPrograms([]models.University{uni}) {
+		if budget != nil && p.AnnualFees > *budget {
+			continue
+		}
+		id := faculties.FacultyID(p.Faculty, p.FacultyEn)
+		texts := [][]string{
+			search.Tokenize(p.Department), search.Tokenize(p.DepartmentEn),
+			search.Tokenize(p.Faculty), search.Tokenize(p.FacultyEn),
+		}
+		for _, in := range interests {
+			if in.discipline == id || matchesText(texts, in.terms) {
+				result = append(result, p.Program)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].AnnualFees < result[j].AnnualFees })
+	if len(result) > maxRecommendedPrograms {
+		result = result[:maxRecommendedPrograms]
+	}
+	return result
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
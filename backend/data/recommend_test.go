@@ -0,0 +1,252 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestDefaultSeedHasNoLanguages(t *testing.T) {
+	unis, _ := seedCatalogue(t)
+	for _, uni := range unis {
+		if len(uni.Languages) > 0 {
+			t.Errorf("default seed gives %s languages %v, want none", uni.ID, uni.Languages)
+		}
+	}
+}
+
+func TestMergeWeights(t *testing.T) {
+	base := models.RecommendationWeights{models.FactorGrade: 3, models.FactorRating: 1}
+	tests := []struct {
+		name      string
+		overrides models.RecommendationWeights
+		want      models.RecommendationWeights
+		err       error
+	}{
+		{"no overrides", nil, base, nil},
+		{"override and add", models.RecommendationWeights{models.FactorGrade: 4, models.FactorRegion: 0.5},
+			models.RecommendationWeights{models.FactorGrade: 4, models.FactorRating: 1, models.FactorRegion: 0.5}, nil},
+		{"zero leaves a factor out", models.RecommendationWeights{models.FactorRating: 0},
+			models.RecommendationWeights{models.FactorGrade: 3, models.FactorRating: 0}, nil},
+		{"unknown factor", models.RecommendationWeights{"prestige": 1}, nil, ErrInvalidWeights},
+		{"negative", models.RecommendationWeights{models.FactorGrade: -1}, nil, ErrInvalidWeights},
+		{"not a number", models.RecommendationWeights{models.FactorGrade: math.NaN()}, nil, ErrInvalidWeights},
+		{"infinite", models.RecommendationWeights{models.FactorGrade: math.Inf(1)}, nil, ErrInvalidWeights},
+		{"all zero", models.RecommendationWeights{models.FactorGrade: 0, models.FactorRating: 0}, nil, ErrInvalidWeights},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeWeights(base, tt.overrides)
+			if !errors.Is(err, tt.err) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeWeights = %v, %v; want %v, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+	if base[models.FactorGrade] != 3 || len(base) != 2 {
+		t.Errorf("MergeWeights changed the base weights to %v", base)
+	}
+}
+
+func TestLoadRecommendationWeights(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	got, err := LoadRecommendationWeights(write("weights.yaml", "grade: 4\nregion: 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultRecommendationWeights()
+	want[models.FactorGrade], want[models.FactorRegion] = 4, 0
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML weights = %v, want %v", got, want)
+	}
+
+	got, err = LoadRecommendationWeights(write("weights.json", `{"interests": 2.5}`))
+	want = DefaultRecommendationWeights()
+	want[models.FactorInterests] = 2.5
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON weights = %v, %v; want %v", got, err, want)
+	}
+
+	if _, err := LoadRecommendationWeights(write("unknown.yaml", "prestige: 1\n")); !errors.Is(err, ErrInvalidWeights) {
+		t.Errorf("unknown factor: %v, want %v", err, ErrInvalidWeights)
+	}
+	if _, err := LoadRecommendationWeights(write("malformed.yaml", "grade: [\n")); err == nil {
+		t.Error("malformed file loaded")
+	}
+	if _, err := LoadRecommendationWeights(filepath.Join(dir, "missing.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+// recommendCatalogue holds two universities a student with 87% and a budget
+// of 150000 can reach, and three they cannot
+func recommendCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo", MinGrade: 85,
+			Fees: models.FeesRange{Min: 1000, Max: 3000}, Languages: []string{"arabic"}, EmploymentRate: 80, Rating: 4,
+			Specialties: []string{"Artificial Intelligence"},
+			Faculties:   []string{"الطب"}, FacultiesEn: []string{"Medicine"}},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "private", Region: "alexandria", MinGrade: 70,
+			Fees: models.FeesRange{Min: 100000, Max: 200000}, Languages: []string{"english"}, Rating: 3,
+			Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {NameEn: "Faculty of Engineering", Departments: []models.Department{
+					{Name: "الميكاترونكس", NameEn: "Mechatronics", Fees: 180000},
+					{Name: "المدني", NameEn: "Civil", Fees: 140000},
+					{Name: "العمارة", NameEn: "Architecture", Fees: 120000},
+				}},
+			}},
+		// Azhar universities admit Azhari certificates only
+		{ID: "3", Name: "جامعة ج", NameEn: "University C", Type: "azhar", Region: "cairo", MinGrade: 60},
+		{ID: "4", Name: "جامعة د", NameEn: "University D", Type: "private", Region: "cairo", MinGrade: 60,
+			Fees: models.FeesRange{Min: 160000, Max: 250000}},
+		{ID: "5", Name: "جامعة هـ", NameEn: "University E", Type: "public", Region: "cairo", MinGrade: 91},
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	percentage, budget := 87.0, 150000
+	req := models.RecommendationRequest{Percentage: &percentage, Budget: &budget, Regions: []string{"cairo"}, Language: "english"}
+	resp, err := Recommend(recommendCatalogue(), testFacultyCatalog(), DefaultRecommendationWeights(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Weights, DefaultRecommendationWeights()) {
+		t.Errorf("weights = %v, want the defaults", resp.Weights)
+	}
+	if len(resp.Recommendations) != 2 || resp.Total != 2 {
+		t.Fatalf("%d recommended of %d, want universities 1 and 2", len(resp.Recommendations), resp.Total)
+	}
+
+	// Weights 3, 2, 1.5, 1, 1 and 1 add up to 9.5
+	a, b := resp.Recommendations[0], resp.Recommendations[1]
+	if a.University.ID != "1" || a.Score != 81.16 || b.University.ID != "2" || b.Score != 64.21 {
+		t.Errorf("recommended %s at %g and %s at %g, want 1 at 81.16 and 2 at 64.21",
+			a.University.ID, a.Score, b.University.ID, b.Score)
+	}
+	want := []models.FactorScore{
+		{Factor: models.FactorGrade, Weight: 3, Score: 0.87, Points: 27.47, Reason: "2.0 points above the 85.0% minimum"},
+		{Factor: models.FactorBudget, Weight: 2, Score: 1, Points: 21.05, Reason: "fees of 1000–3000 are within the budget"},
+		{Factor: models.FactorRegion, Weight: 1.5, Score: 1, Points: 15.79, Reason: "in the preferred region cairo"},
+		{Factor: models.FactorLanguage, Weight: 1, Score: 0, Points: 0, Reason: "teaches in arabic"},
+		{Factor: models.FactorEmployment, Weight: 1, Score: 0.8, Points: 8.42, Reason: "80% of graduates employed"},
+		{Factor: models.FactorRating, Weight: 1, Score: 0.8, Points: 8.42, Reason: "rated 4.0 of 5"},
+	}
+	if !reflect.DeepEqual(a.Factors, want) {
+		t.Errorf("factors = %+v\nwant %+v", a.Factors, want)
+	}
+	wantReasons := []string{
+		"17.0 points above the 70.0% minimum",
+		"only fees up to 150000 of the 100000–200000 range are within the budget",
+		"outside the preferred regions, in alexandria",
+		"teaches in english",
+		"employment rate not published",
+		"rated 3.0 of 5",
+	}
+	var reasons []string
+	for _, f := range b.Factors {
+		reasons = append(reasons, f.Reason)
+	}
+	if !reflect.DeepEqual(reasons, wantReasons) {
+		t.Errorf("reasons = %q, want %q", reasons, wantReasons)
+	}
+
+	// The request's weights apply over the configured ones
+	req.Weights = models.RecommendationWeights{models.FactorRegion: 0, models.FactorLanguage: 5}
+	resp, err = Recommend(recommendCatalogue(), testFacultyCatalog(), DefaultRecommendationWeights(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Weights[models.FactorRegion] != 0 || resp.Weights[models.FactorLanguage] != 5 || resp.Weights[models.FactorGrade] != 3 {
+		t.Errorf("weights = %v, want region 0 and language 5 over the defaults", resp.Weights)
+	}
+	a, b = resp.Recommendations[0], resp.Recommendations[1]
+	if a.University.ID != "2" || a.Score != 84.17 || b.University.ID != "1" || b.Score != 51.75 {
+		t.Errorf("with request weights recommended %s at %g and %s at %g, want 2 at 84.17 and 1 at 51.75",
+			a.University.ID, a.Score, b.University.ID, b.Score)
+	}
+}
+
+func TestRecommendInterests(t *testing.T) {
+	budget := 150000
+	req := models.RecommendationRequest{Budget: &budget, Interests: []string{"engineering", "AI", " "}, Limit: 1}
+	resp, err := Recommend(recommendCatalogue(), testFacultyCatalog(), DefaultRecommendationWeights(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 4 || len(resp.Recommendations) != 1 {
+		t.Fatalf("%d recommended of %d, want 1 of the 4 within budget", len(resp.Recommendations), resp.Total)
+	}
+
+	resp, err = Recommend(recommendCatalogue()[:2], testFacultyCatalog(), DefaultRecommendationWeights(),
+		models.RecommendationRequest{Budget: &budget, Interests: []string{"engineering", "AI"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]models.Recommendation)
+	for _, rec := range resp.Recommendations {
+		byID[rec.University.ID] = rec
+	}
+	for id, want := range map[string]string{"1": "teaches AI", "2": "teaches engineering"} {
+		var got *models.FactorScore
+		for i, f := range byID[id].Factors {
+			if f.Factor == models.FactorInterests {
+				got = &byID[id].Factors[i]
+			}
+		}
+		if got == nil || got.Score != 0.5 || got.Reason != want {
+			t.Errorf("university %s interests = %+v, want 0.5: %q", id, got, want)
+		}
+	}
+
+	// Programs over the budget are left out, the rest listed cheapest first
+	var programs []string
+	for _, p := range byID["2"].Programs {
+		programs = append(programs, p.DepartmentEn)
+	}
+	if want := []string{"Architecture", "Civil"}; !reflect.DeepEqual(programs, want) {
+		t.Errorf("programs = %v, want %v", programs, want)
+	}
+	if len(byID["1"].Programs) != 0 {
+		t.Errorf("university 1 programs = %+v, want none", byID["1"].Programs)
+	}
+}
+
+func TestRecommendErrors(t *testing.T) {
+	num := func(v int) *int { return &v }
+	pct := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name string
+		req  models.RecommendationRequest
+		want error
+	}{
+		{"unknown factor", models.RecommendationRequest{Weights: models.RecommendationWeights{"prestige": 1}}, ErrInvalidWeights},
+		{"negative weight", models.RecommendationRequest{Weights: models.RecommendationWeights{models.FactorRating: -1}}, ErrInvalidWeights},
+		{"percentage over 100", models.RecommendationRequest{Percentage: pct(101)}, ErrInvalidPercentage},
+		{"unknown certificate", models.RecommendationRequest{Certificate: "gcse"}, ErrInvalidBackground},
+		{"negative budget", models.RecommendationRequest{Budget: num(-1)}, ErrInvalidProfile},
+		{"unknown region", models.RecommendationRequest{Regions: []string{"atlantis"}}, ErrInvalidProfile},
+		{"unknown language", models.RecommendationRequest{Language: "latin"}, ErrInvalidProfile},
+		{"limit too high", models.RecommendationRequest{Limit: MaxRecommendations + 1}, ErrInvalidProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Recommend(recommendCatalogue(), testFacultyCatalog(), DefaultRecommendationWeights(), tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Recommend = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
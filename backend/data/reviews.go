@@ -0,0 +1,193 @@
+package data
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"roadtouniversities/models"
+)
+
+// Limits on reviews
+const (
+	MaxReviewTitle = 120
+	MaxReviewBody  = 5000
+	MinReviewBody  = 20
+)
+
+// ReviewPriorWeight is how many reviews' worth of weight the prior carries
+// in a university's rating, so that a handful of reviews cannot make it the
+// best or worst rated
+const ReviewPriorWeight = 5
+
+// Review orders accepted by SortReviews
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortOldest  = "oldest"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
+// ErrInvalidReviewSort is returned by SortReviews for an unknown order
+var ErrInvalidReviewSort = errors.New("sort must be newest, oldest, highest or lowest")
+
+// ReviewFilter narrows the reviews a store lists; empty fields match any
+// review
+type ReviewFilter struct {
+	UniversityID string
+	UserID       string
+	Status       string
+}
+
+// Matches reports whether r passes the filter
+func (f ReviewFilter) Matches(r models.Review) bool {
+	return (f.UniversityID == "" || r.UniversityID == f.UniversityID) &&
+		(f.UserID == "" || r.UserID == f.UserID) &&
+		(f.Status == "" || r.Status == f.Status)
+}
+
+// SortReviews orders reviews in place, newest first by default. Ties on the
+// rating go to the newer review.
+func SortReviews(reviews []models.Review, order string) error {
+	newer := func(a, b models.Review) bool { return a.CreatedAt.After(b.CreatedAt) }
+	var less func(a, b models.Review) bool
+	switch order {
+	case "", ReviewSortNewest:
+		less = newer
+	case ReviewSortOldest:
+		less = func(a, b models.Review) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case ReviewSortHighest:
+		less = func(a, b models.Review) bool {
+			if a.Overall != b.Overall {
+				return a.Overall > b.Overall
+			}
+			return newer(a, b)
+		}
+	case ReviewSortLowest:
+		less = func(a, b models.Review) bool {
+			if a.Overall != b.Overall {
+				return a.Overall < b.Overall
+			}
+			return newer(a, b)
+		}
+	default:
+		return ErrInvalidReviewSort
+	}
+	sort.SliceStable(reviews, func(i, j int) bool { return less(reviews[i], reviews[j]) })
+	return nil
+}
+
+// ReviewPrior is the prior of a university's rating: its editorial rating,
+// or for a university without one the mean overall rating of approved
+// reviews across the catalogue, or 0 when there are none
+func ReviewPrior(editorial float64, approved []models.Review) float64 {
+	if editorial > 0 || len(approved) == 0 {
+		return editorial
+	}
+	sum := 0
+	for _, r := range approved {
+		sum += r.Overall
+	}
+	return float64(sum) / float64(len(approved))
+}
+
+// BayesianRating blends the mean of count ratings summing to sum with prior,
+// weighted as ReviewPriorWeight ratings, rounded to one decimal
+func BayesianRating(sum, count int, prior float64) float64 {
+	return round1((ReviewPriorWeight*prior + float64(sum)) / float64(ReviewPriorWeight+count))
+}
+
+// ReviewRatings computes the rating of every university of unis, keyed by
+// ID, given the approved reviews of the whole catalogue: the Bayesian
+// average of its reviews against ReviewPrior, or its editorial rating when
+// it has none
+func ReviewRatings(unis []models.University, approved []models.Review) map[string]float64 {
+	catalogueMean := ReviewPrior(0, approved)
+	sums := make(map[string]int)
+	counts := make(map[string]int)
+	for _, r := range approved {
+		sums[r.UniversityID] += r.Overall
+		counts[r.UniversityID]++
+	}
+
+	ratings := make(map[string]float64, len(unis))
+	for _, uni := range unis {
+		n := counts[uni.ID]
+		if n == 0 {
+			ratings[uni.ID] = uni.EditorialRating
+			continue
+		}
+		prior := uni.EditorialRating
+		if prior == 0 {
+			prior = catalogueMean
+		}
+		ratings[uni.ID] = BayesianRating(sums[uni.ID], n, prior)
+	}
+	return ratings
+}
+
+// SummarizeReviews aggregates approved reviews, rating them against prior
+func SummarizeReviews(reviews []models.Review, prior float64) models.ReviewSummary {
+	summary := models.ReviewSummary{Count: len(reviews), Stars: make(map[string]int, 5)}
+	for stars := 1; stars <= 5; stars++ {
+		summary.Stars[strconv.Itoa(stars)] = 0
+	}
+
+	var teaching, facilities, housing, career mean
+	sum := 0
+	for _, r := range reviews {
+		sum += r.Overall
+		summary.Stars[strconv.Itoa(r.Overall)]++
+		teaching.add(r.Ratings.Teaching)
+		facilities.add(r.Ratings.Facilities)
+		housing.add(r.Ratings.Housing)
+		career.add(r.Ratings.CareerSupport)
+	}
+	if len(reviews) > 0 {
+		summary.Average = round1(float64(sum) / float64(len(reviews)))
+		summary.Rating = BayesianRating(sum, len(reviews), prior)
+	}
+	summary.Ratings = models.SubRatingAverages{
+		Teaching:      teaching.value(),
+		Facilities:    facilities.value(),
+		Housing:       housing.value(),
+		CareerSupport: career.value(),
+	}
+	return summary
+}
+
+// mean averages the sub-ratings given, skipping unrated (zero) ones
+type mean struct {
+	sum, count int
+}
+
+func (m *mean) add(rating int) {
+	if rating > 0 {
+		m.sum += rating
+		m.count++
+	}
+}
+
+func (m mean) value() float64 {
+	if m.count == 0 {
+		return 0
+	}
+	return round1(float64(m.sum) / float64(m.count))
+}
+
+// ResolveReviewFaculty checks that uni has the faculty a review names, by
+// catalogue ID or name, returning its catalogue ID, or a ValidationError
+func ResolveReviewFaculty(faculty string, uni models.University, faculties *FacultyCatalog) (string, error) {
+	if faculty == "" {
+		return "", nil
+	}
+	if d, found := faculties.Resolve(faculty, faculty); found {
+		faculty = d.ID
+	}
+	if _, found := faculties.universityFaculty(uni, faculty); !found {
+		var v validator
+		v.add("faculty", "%s has no faculty %q", uni.NameEn, faculty)
+		return "", v.err()
+	}
+	return faculty, nil
+}
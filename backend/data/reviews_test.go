@@ -0,0 +1,85 @@
+package data
+
+import (
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestReviewRatings(t *testing.T) {
+	unis := []models.University{
+		{ID: "minia", Rating: 3.9, EditorialRating: 3.9},
+		{ID: "unrated"},
+		{ID: "quiet", Rating: 4.2, EditorialRating: 4.2},
+	}
+	review := func(uni string, overall int) models.Review {
+		return models.Review{UniversityID: uni, Overall: overall, Status: models.ReviewApproved}
+	}
+
+	tests := []struct {
+		name     string
+		approved []models.Review
+		want     map[string]float64
+	}{
+		{
+			name: "no reviews",
+			want: map[string]float64{"minia": 3.9, "unrated": 0, "quiet": 4.2},
+		},
+		{
+			// One 5-star review moves the editorial 3.9 weighted as 5 reviews
+			name:     "one review",
+			approved: []models.Review{review("minia", 5)},
+			want:     map[string]float64{"minia": 4.1, "unrated": 0, "quiet": 4.2},
+		},
+		{
+			name:     "catalogue mean without an editorial rating",
+			approved: []models.Review{review("minia", 5), review("minia", 3), review("unrated", 2)},
+			want:     map[string]float64{"minia": 3.9, "unrated": 3.1, "quiet": 4.2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReviewRatings(unis, tt.approved)
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("rating of %s = %g, want %g", id, got[id], want)
+				}
+			}
+		})
+	}
+
+	// Deleting the only review restores the editorial rating, not the last
+	// computed one
+	unis[0].Rating = ReviewRatings(unis, []models.Review{review("minia", 5)})["minia"]
+	if got := ReviewRatings(unis, nil)["minia"]; got != 3.9 {
+		t.Errorf("rating after the review is gone = %g, want 3.9", got)
+	}
+}
+
+func TestReviewPrior(t *testing.T) {
+	approved := []models.Review{{Overall: 5}, {Overall: 2}}
+	tests := []struct {
+		editorial float64
+		approved  []models.Review
+		want      float64
+	}{
+		{3.9, approved, 3.9},
+		{0, approved, 3.5},
+		{0, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := ReviewPrior(tt.editorial, tt.approved); got != tt.want {
+			t.Errorf("ReviewPrior(%g, %d reviews) = %g, want %g", tt.editorial, len(tt.approved), got, tt.want)
+		}
+	}
+}
+
+func TestLoadUniversitiesEditorialRating(t *testing.T) {
+	unis, _ := seedCatalogue(t)
+	for _, uni := range unis {
+		if uni.EditorialRating != uni.Rating {
+			t.Errorf("%s: editorial rating %g, rating %g: the seed rating should be the editorial one",
+				uni.ID, uni.EditorialRating, uni.Rating)
+		}
+	}
+}
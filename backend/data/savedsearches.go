@@ -0,0 +1,57 @@
+package data
+
+import "roadtouniversities/models"
+
+// Limits on the saved searches of one user
+const (
+	MaxSavedSearches   = 20
+	MaxSavedSearchName = 100
+)
+
+// SearchMatches records the results of a search, in result order
+func SearchMatches(unis []models.University) []models.SearchMatch {
+	result := make([]models.SearchMatch, len(unis))
+	for i, uni := range unis {
+		result[i] = models.SearchMatch{UniversityID: uni.ID, Name: uni.Name, NameEn: uni.NameEn, Fees: uni.Fees}
+	}
+	return result
+}
+
+// DiffMatches compares two sets of search results: universities found only
+// in after, in its order, those found only in before, in its order, and
+// those in both whose fees changed
+func DiffMatches(before, after []models.SearchMatch) models.SearchDiff {
+	diff := models.SearchDiff{
+		Added:      []models.SearchMatch{},
+		Removed:    []models.SearchMatch{},
+		FeeChanges: []models.FeeChange{},
+	}
+
+	previous := make(map[string]models.SearchMatch, len(before))
+	for _, m := range before {
+		previous[m.UniversityID] = m
+	}
+	current := make(map[string]bool, len(after))
+	for _, m := range after {
+		current[m.UniversityID] = true
+		old, found := previous[m.UniversityID]
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, m)
+		case old.Fees != m.Fees:
+			diff.FeeChanges = append(diff.FeeChanges, models.FeeChange{
+				UniversityID: m.UniversityID,
+				Name:         m.Name,
+				NameEn:       m.NameEn,
+				Before:       old.Fees,
+				After:        m.Fees,
+			})
+		}
+	}
+	for _, m := range before {
+		if !current[m.UniversityID] {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+	return diff
+}
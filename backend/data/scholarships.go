@@ -0,0 +1,289 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// scholarshipsDir is the seed directory holding the scholarship catalogue
+const scholarshipsDir = "scholarships"
+
+// LoadScholarships reads the scholarship catalogue from the .json, .yaml and
+// .yml files in the scholarships directory of fsys, in file order. Records
+// must name universities of unis and faculties of the faculty catalogue;
+// every problem found is returned as SeedErrors. Without the directory the
+// catalogue is empty.
+func LoadScholarships(fsys fs.FS, unis []models.University, faculties *FacultyCatalog) ([]models.Scholarship, error) {
+	names, err := seedFiles(fsys, scholarshipsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(unis))
+	for _, uni := range unis {
+		known[uni.ID] = true
+	}
+
+	var result []models.Scholarship
+	var errs SeedErrors
+	seen := make(map[string]string)
+
+	for _, name := range names {
+		records, fileErr := readSeedFile(fsys, name, "a scholarship")
+		if fileErr != nil {
+			errs = append(errs, *fileErr)
+			continue
+		}
+
+		for i, raw := range records {
+			var s models.Scholarship
+			if recErr := decodeRecord(raw, &s); recErr != nil {
+				recErr.File, recErr.Record, recErr.ID = name, i, recordID(raw)
+				errs = append(errs, *recErr)
+				continue
+			}
+
+			invalid := false
+			if err := ValidateScholarship(s); err != nil {
+				var verr ValidationError
+				if !errors.As(err, &verr) {
+					errs = append(errs, SeedError{File: name, Record: i, ID: s.ID, Msg: err.Error()})
+					continue
+				}
+				for _, fe := range verr {
+					errs = append(errs, SeedError{File: name, Record: i, ID: s.ID, Field: fe.Field, Msg: fe.Message})
+				}
+				invalid = true
+			}
+			for j, id := range s.UniversityIDs {
+				if !known[id] {
+					errs = append(errs, SeedError{File: name, Record: i, ID: s.ID, Field: fmt.Sprintf("universityIds[%d]", j), Msg: fmt.Sprintf("unknown university %q", id)})
+					invalid = true
+				}
+			}
+			for j, id := range s.Faculties {
+				if _, found := faculties.Discipline(id); !found {
+					errs = append(errs, SeedError{File: name, Record: i, ID: s.ID, Field: fmt.Sprintf("faculties[%d]", j), Msg: fmt.Sprintf("unknown faculty %q", id)})
+					invalid = true
+				}
+			}
+			if invalid {
+				continue
+			}
+
+			if first, dup := seen[s.ID]; dup {
+				errs = append(errs, SeedError{File: name, Record: i, ID: s.ID, Field: "id", Msg: "duplicates a record in " + first})
+				continue
+			}
+			seen[s.ID] = name
+			result = append(result, s)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ScholarshipQuery selects scholarships; empty fields match everything
+type ScholarshipQuery struct {
+	UniversityID string
+	FacultyID    string
+	Type         string
+	Grade        *float64 // keeps those whose grade threshold it meets
+}
+
+// FilterScholarships returns the scholarships query selects, in order. A
+// scholarship open to every faculty matches any FacultyID.
+func FilterScholarships(scholarships []models.Scholarship, query ScholarshipQuery) []models.Scholarship {
+	result := []models.Scholarship{}
+	for _, s := range scholarships {
+		if (query.UniversityID != "" && !containsString(s.UniversityIDs, query.UniversityID)) ||
+			(query.FacultyID != "" && !coversFaculty(s, query.FacultyID)) ||
+			(query.Type != "" && s.Type != query.Type) ||
+			(query.Grade != nil && *query.Grade < s.MinGrade) {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// coversFaculty reports whether s applies to the faculty with the given ID
+func coversFaculty(s models.Scholarship, facultyID string) bool {
+	return len(s.Faculties) == 0 || containsString(s.Faculties, facultyID)
+}
+
+// eligibleScholarships returns the scholarships at the university with the
+// given ID that a student with grade can still apply for on the given day.
+// Without a grade only scholarships without a threshold are eligible.
+func eligibleScholarships(scholarships []models.Scholarship, universityID string, grade *float64, on time.Time) []models.Scholarship {
+	today := on.Format(dateLayout)
+	var result []models.Scholarship
+	for _, s := range scholarships {
+		if !containsString(s.UniversityIDs, universityID) || (s.Deadline != "" && s.Deadline < today) {
+			continue
+		}
+		if s.MinGrade > 0 && (grade == nil || *grade < s.MinGrade) {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// scholarshipDiscount returns what s takes off an annual fee in currency,
+// never more than the fee itself
+func (er *ExchangeRates) scholarshipDiscount(s models.Scholarship, fee int, currency string, on time.Time) (int, error) {
+	if s.DiscountPercent > 0 {
+		return int(math.Round(float64(fee) * s.DiscountPercent / 100)), nil
+	}
+	amount, err := er.Convert(float64(s.DiscountAmount), firstNonEmpty(s.Currency, models.CurrencyEGP), currency, on)
+	if err != nil {
+		return 0, fmt.Errorf("scholarship %s: %w", s.ID, err)
+	}
+	return min(int(math.Round(amount)), fee), nil
+}
+
+// bestScholarship picks the eligible scholarship covering the faculty with
+// the given ID, or every faculty when facultyID is empty, that takes the
+// most off fee. It reports false when none applies.
+func (er *ExchangeRates) bestScholarship(eligible []models.Scholarship, facultyID string, fee int, currency string, on time.Time) (models.Scholarship, int, bool, error) {
+	var best models.Scholarship
+	bestDiscount, found := 0, false
+	for _, s := range eligible {
+		if (facultyID == "" && len(s.Faculties) > 0) || (facultyID != "" && !coversFaculty(s, facultyID)) {
+			continue
+		}
+		discount, err := er.scholarshipDiscount(s, fee, currency, on)
+		if err != nil {
+			return best, 0, false, err
+		}
+		if !found || discount > bestDiscount {
+			best, bestDiscount, found = s, discount, true
+		}
+	}
+	return best, bestDiscount, found, nil
+}
+
+// ApplyScholarships returns uni, whose fees must already be converted to
+// annual amounts in one currency by ConvertUniversity, with the best
+// scholarship open to a student with grade taken off its fees. The
+// university's range takes scholarships open to every faculty; each
+// detailed faculty, with its departments and specializations, takes the best
+// covering it. Scholarships do not stack. The scholarships applied are
+// returned with their discount in the fees' currency, those of faculties
+// only where they differ from the university's.
+func (er *ExchangeRates) ApplyScholarships(uni models.University, scholarships []models.Scholarship, faculties *FacultyCatalog, grade *float64, on time.Time) (models.University, []models.AppliedScholarship, error) {
+	eligible := eligibleScholarships(scholarships, uni.ID, grade, on)
+	if len(eligible) == 0 {
+		return uni, nil, nil
+	}
+
+	currency := firstNonEmpty(uni.Fees.Currency, models.CurrencyEGP)
+	discounted := cloneUniversity(uni)
+	var applied []models.AppliedScholarship
+
+	wide, _, hasWide, err := er.bestScholarship(eligible, "", uni.Fees.Max, currency, on)
+	if err != nil {
+		return uni, nil, err
+	}
+	hasWide = hasWide && uni.Fees.Max > 0
+	if hasWide {
+		entry, err := er.applyToRange(&discounted.Fees, wide, currency, on)
+		if err != nil {
+			return uni, nil, err
+		}
+		applied = append(applied, entry)
+	}
+
+	for i, name := range uni.Faculties {
+		faculty, detailed := discounted.DetailedFaculties[name]
+		if !detailed || faculty.AnnualFees.Max == 0 {
+			continue
+		}
+		nameEn := ""
+		if i < len(uni.FacultiesEn) {
+			nameEn = uni.FacultiesEn[i]
+		}
+		id := faculties.FacultyID(name, nameEn)
+		s, _, found, err := er.bestScholarship(eligible, id, faculty.AnnualFees.Max, currency, on)
+		if err != nil {
+			return uni, nil, err
+		}
+		if !found {
+			continue
+		}
+
+		entry, err := er.applyToRange(&faculty.AnnualFees, s, currency, on)
+		if err != nil {
+			return uni, nil, err
+		}
+		if faculty.AnnualFeesEn != "" {
+			faculty.AnnualFeesEn = formatFeesRange(faculty.AnnualFees)
+		}
+		for j, dept := range faculty.Departments {
+			discount, err := er.scholarshipDiscount(s, dept.Fees, currency, on)
+			if err != nil {
+				return uni, nil, err
+			}
+			dept.Fees -= discount
+			if dept.FeesEn != "" {
+				dept.FeesEn = formatAnnualFee(dept.Fees, currency)
+			}
+			faculty.Departments[j] = dept
+		}
+		for j, spec := range faculty.Specializations {
+			discount, err := er.scholarshipDiscount(s, spec.Fees, currency, on)
+			if err != nil {
+				return uni, nil, err
+			}
+			spec.Fees -= discount
+			if spec.FeesEn != "" {
+				spec.FeesEn = formatAnnualFee(spec.Fees, currency)
+			}
+			faculty.Specializations[j] = spec
+		}
+		discounted.DetailedFaculties[name] = faculty
+
+		if !hasWide || s.ID != wide.ID {
+			entry.Faculty = id
+			applied = append(applied, entry)
+		}
+	}
+	return discounted, applied, nil
+}
+
+// applyToRange takes s off both bounds of fees, returning it as applied
+func (er *ExchangeRates) applyToRange(fees *models.FeesRange, s models.Scholarship, currency string, on time.Time) (models.AppliedScholarship, error) {
+	lower, err := er.scholarshipDiscount(s, fees.Min, currency, on)
+	if err != nil {
+		return models.AppliedScholarship{}, err
+	}
+	upper, err := er.scholarshipDiscount(s, fees.Max, currency, on)
+	if err != nil {
+		return models.AppliedScholarship{}, err
+	}
+	fees.Min, fees.Max = fees.Min-lower, fees.Max-upper
+	return appliedScholarship(s, upper), nil
+}
+
+// appliedScholarship describes s taking discount off a fee
+func appliedScholarship(s models.Scholarship, discount int) models.AppliedScholarship {
+	applied := models.AppliedScholarship{ID: s.ID, Name: s.Name, NameEn: s.NameEn, Type: s.Type}
+	if s.DiscountPercent > 0 {
+		applied.DiscountPercent = s.DiscountPercent
+	} else {
+		applied.DiscountAmount = discount
+	}
+	return applied
+}
@@ -0,0 +1,28 @@
+package data
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDefaultSeedHasNoScholarships(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	scholarships, err := LoadScholarships(DefaultSeed(), unis, faculties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scholarships) != 0 {
+		t.Errorf("default seed holds %d scholarships, want none", len(scholarships))
+	}
+}
+
+func TestLoadScholarshipsSample(t *testing.T) {
+	unis, faculties := seedCatalogue(t)
+	scholarships, err := LoadScholarships(os.DirFS("testdata"), unis, faculties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scholarships) == 0 {
+		t.Fatal("sample fixture yields no scholarships")
+	}
+}
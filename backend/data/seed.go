@@ -0,0 +1,251 @@
+package data
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"roadtouniversities/models"
+)
+
+// Default catalogue, faculty disciplines and exchange rates compiled into
+// the binary, used when no data directory is configured
+//
+//go:embed seed/*.json seed/*.csv seed/faculties/*.yaml seed/calendar/*.yaml seed/moderation/*.txt
+var defaultSeed embed.FS
+
+// DefaultSeed returns the built-in seed files
+func DefaultSeed() fs.FS {
+	sub, err := fs.Sub(defaultSeed, "seed")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// SeedError reports a problem with one seed file or one record inside it
+type SeedError struct {
+	File   string `json:"file"`
+	Record int    `json:"record"` // position in the file (line for CSV), -1 for file-level errors
+	ID     string `json:"id,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Msg    string `json:"message"`
+}
+
+func (e SeedError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Record >= 0 {
+		fmt.Fprintf(&b, ": record %d", e.Record)
+		if e.ID != "" {
+			fmt.Fprintf(&b, " (id %q)", e.ID)
+		}
+	}
+	if e.Field != "" {
+		b.WriteString(": " + e.Field)
+	}
+	b.WriteString(": " + e.Msg)
+	return b.String()
+}
+
+// SeedErrors lists every problem found while loading a seed directory
+type SeedErrors []SeedError
+
+func (e SeedErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, se := range e {
+		lines[i] = se.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadUniversities reads every .json, .yaml and .yml file at the root of fsys.
+// A file holds either a single university or a list of them. All records are
+// validated and IDs must be unique across files; every problem found is
+// returned as SeedErrors.
+func LoadUniversities(fsys fs.FS) ([]models.University, error) {
+	names, err := seedFiles(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no .json, .yaml or .yml seed files found")
+	}
+
+	var result []models.University
+	var errs SeedErrors
+	seen := make(map[string]string)
+
+	for _, name := range names {
+		records, fileErr := readSeedFile(fsys, name, "a university")
+		if fileErr != nil {
+			errs = append(errs, *fileErr)
+			continue
+		}
+
+		for i, raw := range records {
+			uni, recErr := decodeUniversity(raw)
+			if recErr != nil {
+				recErr.File, recErr.Record, recErr.ID = name, i, uni.ID
+				errs = append(errs, *recErr)
+				continue
+			}
+			// A seed rating is editorial; without reviews the rating is
+			// the editorial one
+			if uni.EditorialRating == 0 {
+				uni.EditorialRating = uni.Rating
+			}
+			uni.Rating = uni.EditorialRating
+
+			if err := ValidateUniversity(uni); err != nil {
+				var verr ValidationError
+				if errors.As(err, &verr) {
+					for _, fe := range verr {
+						errs = append(errs, SeedError{File: name, Record: i, ID: uni.ID, Field: fe.Field, Msg: fe.Message})
+					}
+					continue
+				}
+				errs = append(errs, SeedError{File: name, Record: i, ID: uni.ID, Msg: err.Error()})
+				continue
+			}
+
+			if first, dup := seen[uni.ID]; dup {
+				errs = append(errs, SeedError{File: name, Record: i, ID: uni.ID, Field: "id", Msg: "duplicates a record in " + first})
+				continue
+			}
+			seen[uni.ID] = name
+			result = append(result, uni)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// seedFiles lists the seed files in directory dir of fsys in name order,
+// as paths within fsys
+func seedFiles(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(path.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			names = append(names, path.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readSeedFile splits a seed file of what records into raw JSON records. YAML
+// files are converted to JSON so both formats share the same field names and
+// decoding.
+func readSeedFile(fsys fs.FS, name, what string) ([]json.RawMessage, *SeedError) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, &SeedError{File: name, Record: -1, Msg: err.Error()}
+	}
+
+	if ext := strings.ToLower(path.Ext(name)); ext == ".yaml" || ext == ".yml" {
+		var doc any
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, &SeedError{File: name, Record: -1, Msg: err.Error()}
+		}
+		content, err = json.Marshal(doc)
+		if err != nil {
+			return nil, &SeedError{File: name, Record: -1, Msg: "unsupported YAML structure: " + err.Error()}
+		}
+	}
+
+	// A lone object is wrapped into a one-element list
+	doc, wrapped := content, false
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] != '[' {
+		doc, wrapped = append(append([]byte{'['}, content...), ']'), true
+	}
+
+	var records []json.RawMessage
+	if err := json.Unmarshal(doc, &records); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset := syntaxErr.Offset
+			if wrapped {
+				offset--
+			}
+			line, col := position(content, offset)
+			return nil, &SeedError{File: name, Record: -1, Msg: fmt.Sprintf("line %d, column %d: %v", line, col, err)}
+		}
+		return nil, &SeedError{File: name, Record: -1, Msg: "expected " + what + " object or a list of them"}
+	}
+	return records, nil
+}
+
+// decodeUniversity strictly decodes one record, rejecting unknown fields. The
+// returned university carries whatever ID could be read for error reporting.
+func decodeUniversity(raw json.RawMessage) (models.University, *SeedError) {
+	var uni models.University
+	if err := decodeRecord(raw, &uni); err != nil {
+		uni.ID = recordID(raw)
+		return uni, err
+	}
+	return uni, nil
+}
+
+// decodeRecord strictly decodes one record into v, rejecting unknown fields
+func decodeRecord(raw json.RawMessage, v any) *SeedError {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &SeedError{Field: typeErr.Field, Msg: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value)}
+	}
+	if field, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+		return &SeedError{Field: strings.Trim(field, `"`), Msg: "unknown field"}
+	}
+	return &SeedError{Msg: err.Error()}
+}
+
+// recordID reads whatever ID a record has, for error reporting
+func recordID(raw json.RawMessage) string {
+	var idOnly struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(raw, &idOnly)
+	return idOnly.ID
+}
+
+// position converts a byte offset into a 1-based line and column
+func position(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
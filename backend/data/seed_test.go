@@ -0,0 +1,139 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"roadtouniversities/models"
+)
+
+func TestLoadUniversities(t *testing.T) {
+	unis, err := LoadUniversities(fstest.MapFS{
+		"public.json": {Data: []byte(`[
+  {"id": "1", "name": "جامعة أ", "nameEn": "University A", "type": "public", "region": "cairo", "rating": 4},
+  {"id": "2", "name": "جامعة ب", "nameEn": "University B", "type": "public", "region": "delta", "rating": 4, "editorialRating": 3}
+]`)},
+		"private.yaml":        {Data: []byte("id: \"3\"\nname: جامعة ج\nnameEn: University C\ntype: private\nregion: alexandria\n")},
+		"README.txt":          {Data: []byte("not a seed file")},
+		"cutoffs/sample.json": {Data: []byte(`{"not": "a university"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]float64)
+	for _, uni := range unis {
+		got[uni.ID] = uni.Rating
+		if uni.EditorialRating != uni.Rating {
+			t.Errorf("university %s rated %g with editorial rating %g, want them equal", uni.ID, uni.Rating, uni.EditorialRating)
+		}
+	}
+	// The rating is taken as editorial unless an editorial rating is given
+	if want := map[string]float64{"1": 4, "2": 3, "3": 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings = %v, want %v", got, want)
+	}
+}
+
+func TestLoadUniversitiesErrors(t *testing.T) {
+	_, err := LoadUniversities(fstest.MapFS{
+		"a-broken.json": {Data: []byte("[\n  {\"id\": \"9\",}\n]")},
+		"b-public.json": {Data: []byte(`[
+  {"id": "1", "name": "جامعة أ", "nameEn": "University A", "type": "public", "region": "cairo"},
+  {"id": "2", "name": "جامعة ب", "nameEn": "University B", "type": "public", "region": "atlantis", "minGrade": 101}
+]`)},
+		"c-private.yaml": {Data: []byte(`- {id: "1", name: جامعة, nameEn: Duplicate, type: private, region: cairo}
+- {id: "3", name: جامعة ج, nameEn: University C, type: private, region: cairo, motto: Excellence}
+- {id: "4", name: جامعة د, nameEn: University D, type: private, region: cairo, minGrade: high}
+`)},
+	})
+
+	var errs SeedErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("err = %v, want SeedErrors", err)
+	}
+	want := []struct {
+		file      string
+		record    int
+		id, field string
+		msg       string
+	}{
+		{"a-broken.json", -1, "", "", "line 2, column 15: "},
+		{"b-public.json", 1, "2", "region", "must be one of "},
+		{"b-public.json", 1, "2", "minGrade", "must be between 0 and 100, got 101"},
+		{"c-private.yaml", 0, "1", "id", "duplicates a record in b-public.json"},
+		{"c-private.yaml", 1, "3", "motto", "unknown field"},
+		{"c-private.yaml", 2, "4", "minGrade", "expected int, got string"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("%d errors, want %d:\n%v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		e := errs[i]
+		if e.File != w.file || e.Record != w.record || e.ID != w.id || e.Field != w.field || !strings.HasPrefix(e.Msg, w.msg) {
+			t.Errorf("error %d = %+v, want %s record %d (id %q) %s: %s…", i, e, w.file, w.record, w.id, w.field, w.msg)
+		}
+	}
+	if got := errs[0].Error(); !strings.HasPrefix(got, "a-broken.json: line 2") {
+		t.Errorf("file-level error reads %q", got)
+	}
+	if got := errs[1].Error(); !strings.HasPrefix(got, `b-public.json: record 1 (id "2"): region: `) {
+		t.Errorf("field error reads %q", got)
+	}
+}
+
+func TestLoadUniversitiesNoFiles(t *testing.T) {
+	if _, err := LoadUniversities(fstest.MapFS{"README.txt": {Data: []byte("empty")}}); err == nil {
+		t.Error("loaded a directory without seed files")
+	}
+	if _, err := LoadUniversities(fstest.MapFS{"list.json": {Data: []byte(`"a university"`)}}); err == nil {
+		t.Error("loaded a file holding neither an object nor a list")
+	}
+}
+
+func TestValidateUniversity(t *testing.T) {
+	valid := func(edit func(*models.University)) models.University {
+		uni := models.University{ID: "1", Name: "جامعة", NameEn: "University", Type: "public", Region: "cairo",
+			Fees: models.FeesRange{Min: 1000, Max: 2000}, MinGrade: 80,
+			Faculties: []string{"الطب"}, FacultiesEn: []string{"Medicine"}, Languages: []string{"arabic"}}
+		edit(&uni)
+		return uni
+	}
+	tests := []struct {
+		name   string
+		uni    models.University
+		fields []string
+	}{
+		{"valid", valid(func(u *models.University) {}), nil},
+		{"missing names", valid(func(u *models.University) { u.ID, u.Name, u.NameEn = "", " ", "" }), []string{"id", "name", "nameEn"}},
+		{"unknown type and region", valid(func(u *models.University) { u.Type, u.Region = "online", "atlantis" }), []string{"type", "region"}},
+		{"founded too early", valid(func(u *models.University) { u.Established = 500 }), []string{"established"}},
+		{"rating over 5", valid(func(u *models.University) { u.Rating, u.EditorialRating = 6, -1 }), []string{"rating", "editorialRating"}},
+		{"inverted fees", valid(func(u *models.University) { u.Fees = models.FeesRange{Min: 3000, Max: 2000} }), []string{"fees"}},
+		{"negative fees", valid(func(u *models.University) { u.Fees.Min = -1 }), []string{"fees.min"}},
+		{"fee currency", valid(func(u *models.University) { u.Fees.Currency = "dollars" }), []string{"fees.currency"}},
+		{"English names misaligned", valid(func(u *models.University) { u.FacultiesEn = []string{"a", "b"} }), []string{"facultiesEn"}},
+		{"unknown language", valid(func(u *models.University) { u.Languages = []string{"arabic", "latin"} }), []string{"languages[1]"}},
+		{"grades", valid(func(u *models.University) { u.MaxGrade = 70 }), []string{"maxGrade"}},
+		{"rates", valid(func(u *models.University) { u.AcceptanceRate, u.EmploymentRate, u.Students = 101, -1, -1 }),
+			[]string{"students", "acceptanceRate", "employmentRate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUniversity(tt.uni)
+			var verr ValidationError
+			if err != nil && !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+			var fields []string
+			for _, fe := range verr {
+				fields = append(fields, fe.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Fatalf("invalid fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"roadtouniversities/models"
+)
+
+// Limits on the favourites and shortlists of one user
+const (
+	MaxFavorites      = 100
+	MaxShortlists     = 20
+	MaxShortlistItems = 50
+	MaxShortlistName  = 100
+	MaxShortlistNote  = 1000
+)
+
+// ResolveShortlistItems checks that the universities, faculties and
+// departments items name exist, returning the items with faculties as
+// catalogue IDs and departments by their Arabic name, or a ValidationError
+// listing every item that does not resolve or repeats an earlier one.
+// Faculties may be given by ID or by name, departments by either name.
+func ResolveShortlistItems(items []models.ShortlistItem, unis []models.University, faculties *FacultyCatalog) ([]models.ShortlistItem, error) {
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	var v validator
+	result := make([]models.ShortlistItem, len(items))
+	seen := make(map[models.ShortlistItem]int)
+
+	for i, item := range items {
+		prefix := fmt.Sprintf("items[%d]", i)
+		item.UniversityID = strings.TrimSpace(item.UniversityID)
+		item.Faculty = strings.TrimSpace(item.Faculty)
+		item.Department = strings.TrimSpace(item.Department)
+
+		uni, found := byID[item.UniversityID]
+		if !found {
+			v.add(join(prefix, "universityId"), "unknown university %q", item.UniversityID)
+			continue
+		}
+		if item.Faculty != "" {
+			if d, found := faculties.Resolve(item.Faculty, item.Faculty); found {
+				item.Faculty = d.ID
+			}
+			f, found := faculties.universityFaculty(uni, item.Faculty)
+			if !found {
+				v.add(join(prefix, "faculty"), "%s has no faculty %q", uni.NameEn, item.Faculty)
+				continue
+			}
+			if item.Department != "" {
+				dept, found := findDepartment(f, item.Department)
+				if !found {
+					v.add(join(prefix, "department"), "%s has no department %q", firstNonEmpty(f.nameEn, f.name), item.Department)
+					continue
+				}
+				item.Department = dept.Name
+			}
+		}
+
+		key := item
+		key.Note = ""
+		if first, dup := seen[key]; dup {
+			v.add(prefix, "duplicates items[%d]", first)
+			continue
+		}
+		seen[key] = i
+		result[i] = item
+	}
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FavoriteEntries pairs favorites with their universities, skipping any
+// whose university no longer exists
+func FavoriteEntries(favorites []models.Favorite, unis []models.University) []models.FavoriteEntry {
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	result := []models.FavoriteEntry{}
+	for _, fav := range favorites {
+		if uni, found := byID[fav.UniversityID]; found {
+			result = append(result, models.FavoriteEntry{Favorite: fav, University: uni})
+		}
+	}
+	return result
+}
+
+// ViewShortlist resolves the items of a shortlist against the current
+// catalogue, skipping any whose university no longer exists
+func ViewShortlist(list models.Shortlist, unis []models.University, faculties *FacultyCatalog) models.ShortlistView {
+	byID := make(map[string]models.University, len(unis))
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+	}
+
+	view := models.ShortlistView{
+		ID:        list.ID,
+		Name:      list.Name,
+		Items:     []models.ShortlistEntry{},
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+	for _, item := range list.Items {
+		uni, found := byID[item.UniversityID]
+		if !found {
+			continue
+		}
+		entry := models.ShortlistEntry{ShortlistItem: item, University: uni}
+		if f, found := faculties.universityFaculty(uni, item.Faculty); found && item.Faculty != "" {
+			entry.FacultyName, entry.FacultyNameEn = f.name, f.nameEn
+			if dept, found := findDepartment(f, item.Department); found && item.Department != "" {
+				entry.DepartmentInfo = &dept
+			}
+		}
+		view.Items = append(view.Items, entry)
+	}
+	return view
+}
+
+// universityFaculty finds the faculty of uni with the given ID
+func (fc *FacultyCatalog) universityFaculty(uni models.University, id string) (universityFaculty, bool) {
+	var result universityFaculty
+	found := false
+	fc.eachFaculty([]models.University{uni}, func(fid string, f universityFaculty) {
+		if !found && fid == id {
+			result, found = f, true
+		}
+	})
+	return result, found
+}
+
+// findDepartment finds a department of a university faculty by its Arabic or
+// English name
+func findDepartment(f universityFaculty, name string) (models.Department, bool) {
+	for _, dept := range f.uni.DetailedFaculties[f.name].Departments {
+		if dept.Name == name || strings.EqualFold(dept.NameEn, name) {
+			return dept, true
+		}
+	}
+	return models.Department{}, false
+}
@@ -0,0 +1,136 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"roadtouniversities/models"
+)
+
+// ErrInvalidSort is returned for an unknown sort key or order
+var ErrInvalidSort = errors.New("invalid sort")
+
+// sortFields maps sortBy keys to the numeric university field they order by
+var sortFields = map[string]func(uni models.University) float64{
+	"rating":         func(uni models.University) float64 { return uni.Rating },
+	"established":    func(uni models.University) float64 { return float64(uni.Established) },
+	"students":       func(uni models.University) float64 { return float64(uni.Students) },
+	"studentsCount":  func(uni models.University) float64 { return float64(uni.Students) },
+	"fees":           func(uni models.University) float64 { return float64(uni.Fees.Min) },
+	"feesMin":        func(uni models.University) float64 { return float64(uni.Fees.Min) },
+	"feesMax":        func(uni models.University) float64 { return float64(uni.Fees.Max) },
+	"minGrade":       func(uni models.University) float64 { return float64(uni.MinGrade) },
+	"maxGrade":       func(uni models.University) float64 { return float64(uni.MaxGrade) },
+	"acceptanceRate": func(uni models.University) float64 { return float64(uni.AcceptanceRate) },
+	"employmentRate": func(uni models.University) float64 { return float64(uni.EmploymentRate) },
+}
+
+// SortRelevance orders by full-text score. It sorts descending unless
+// ":asc" is given explicitly.
+const SortRelevance = "relevance"
+
+// SortKey is one field of a multi-key sort
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a comma-separated sortBy such as "rating,fees:asc". Keys
+// without an explicit ":asc" or ":desc" use sortOrder, which defaults to asc.
+func ParseSort(sortBy, sortOrder string) ([]SortKey, error) {
+	return parseSortKeys(sortBy, sortOrder, func(field string) bool {
+		_, known := sortFields[field]
+		return known || field == SortRelevance
+	})
+}
+
+// parseSortKeys parses sortBy, accepting the fields known reports true for
+func parseSortKeys(sortBy, sortOrder string, known func(field string) bool) ([]SortKey, error) {
+	defaultDesc, err := parseOrder(sortOrder)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(sortBy) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	for _, part := range strings.Split(sortBy, ",") {
+		field, order, hasOrder := strings.Cut(strings.TrimSpace(part), ":")
+		if !known(field) {
+			return nil, fmt.Errorf("%w: unknown sort key %q", ErrInvalidSort, field)
+		}
+
+		key := SortKey{Field: field, Desc: defaultDesc || field == SortRelevance}
+		if hasOrder {
+			if key.Desc, err = parseOrder(order); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseOrder(order string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: unknown sort order %q", ErrInvalidSort, order)
+	}
+}
+
+// HasRelevance reports whether keys sort by full-text score
+func HasRelevance(keys []SortKey) bool {
+	for _, key := range keys {
+		if key.Field == SortRelevance {
+			return true
+		}
+	}
+	return false
+}
+
+// SortUniversities stably orders unis by keys, breaking ties by ID. scores
+// holds full-text scores by university ID for the relevance key.
+func SortUniversities(unis []models.University, keys []SortKey, scores map[string]float64) {
+	if len(keys) == 0 {
+		return
+	}
+
+	value := func(key SortKey, uni models.University) float64 {
+		if key.Field == SortRelevance {
+			return scores[uni.ID]
+		}
+		return sortFields[key.Field](uni)
+	}
+
+	sort.SliceStable(unis, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := value(key, unis[i]), value(key, unis[j])
+			if a == b {
+				continue
+			}
+			if key.Desc {
+				return a > b
+			}
+			return a < b
+		}
+		return lessID(unis[i].ID, unis[j].ID)
+	})
+}
+
+// lessID compares IDs numerically when both are numbers
+func lessID(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a < b
+}
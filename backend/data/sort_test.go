@@ -0,0 +1,114 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		want      []SortKey
+		wantErr   bool
+	}{
+		{name: "empty", want: nil},
+		{name: "default order is asc", sortBy: "rating", want: []SortKey{{Field: "rating"}}},
+		{name: "sortOrder applies to every key", sortBy: "rating,fees", sortOrder: "DESC",
+			want: []SortKey{{Field: "rating", Desc: true}, {Field: "fees", Desc: true}}},
+		{name: "explicit order overrides sortOrder", sortBy: "rating:desc, fees:asc", sortOrder: "asc",
+			want: []SortKey{{Field: "rating", Desc: true}, {Field: "fees"}}},
+		{name: "relevance defaults to desc", sortBy: "relevance", want: []SortKey{{Field: "relevance", Desc: true}}},
+		{name: "relevance can be ascending", sortBy: "relevance:asc", want: []SortKey{{Field: "relevance"}}},
+		{name: "aliases", sortBy: "studentsCount,feesMax",
+			want: []SortKey{{Field: "studentsCount"}, {Field: "feesMax"}}},
+		{name: "order without key is checked", sortOrder: "sideways", wantErr: true},
+		{name: "unknown key", sortBy: "name", wantErr: true},
+		{name: "unknown order", sortBy: "rating:up", wantErr: true},
+		{name: "empty key", sortBy: "rating,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.sortBy, tt.sortOrder)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSort) {
+					t.Fatalf("err = %v, want ErrInvalidSort", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("keys = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortUniversities(t *testing.T) {
+	unis := []models.University{
+		{ID: "10", Rating: 4.5, Fees: models.FeesRange{Min: 100}},
+		{ID: "2", Rating: 4.0, Fees: models.FeesRange{Min: 300}},
+		{ID: "9", Rating: 4.5, Fees: models.FeesRange{Min: 200}},
+		{ID: "1", Rating: 4.0, Fees: models.FeesRange{Min: 300}},
+	}
+	scores := map[string]float64{"2": 3, "9": 1, "10": 2}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		want   []string
+	}{
+		{name: "no keys keeps order", want: []string{"10", "2", "9", "1"}},
+		{name: "ties broken by numeric ID", sortBy: "rating:desc", want: []string{"9", "10", "1", "2"}},
+		{name: "second key", sortBy: "rating:desc,fees:desc", want: []string{"9", "10", "1", "2"}},
+		{name: "ascending fees", sortBy: "fees", want: []string{"10", "9", "1", "2"}},
+		{name: "relevance with unscored last", sortBy: "relevance", want: []string{"2", "10", "9", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseSort(tt.sortBy, "")
+			if err != nil {
+				t.Fatalf("ParseSort: %v", err)
+			}
+			sorted := append([]models.University(nil), unis...)
+			SortUniversities(sorted, keys, scores)
+			if got := universityIDs(sorted); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLessID(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"2", "10", true},
+		{"10", "2", false},
+		{"a", "b", true},
+		{"10", "a", true},
+		{"3", "3", false},
+	}
+	for _, tt := range tests {
+		if got := lessID(tt.a, tt.b); got != tt.want {
+			t.Errorf("lessID(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func universityIDs(unis []models.University) []string {
+	ids := make([]string, len(unis))
+	for i, uni := range unis {
+		ids[i] = uni.ID
+	}
+	return ids
+}
@@ -0,0 +1,1258 @@
+package data
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"roadtouniversities/models"
+)
+
+// SQLiteStore persists the catalogue in a SQLite database file
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+const universityColumns = `id, name, name_en, type, location, location_en, region, established,
+	rating, fees_min, fees_max, faculties, faculties_en, specialties, description,
+	description_en, image, min_grade, max_grade, students, acceptance_rate,
+	employment_rate, detailed_faculties, admission, languages, costs, fees_currency,
+	fees_period, editorial_rating`
+
+// NewSQLiteStore opens the database at path and applies pending migrations.
+// Use ":memory:" for a throwaway database.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, errors.New("sqlite: database path is required")
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: open %s: %w", path, err)
+	}
+	// A single connection serialises writers and keeps ":memory:" databases
+	// from being split across connections
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// ListUniversities returns every university in insertion order
+func (s *SQLiteStore) ListUniversities() ([]models.University, error) {
+	rows, err := s.db.Query(`SELECT ` + universityColumns + ` FROM universities ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.University
+	for rows.Next() {
+		uni, err := scanUniversity(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, uni)
+	}
+	return result, rows.Err()
+}
+
+// GetUniversity returns a university by ID
+func (s *SQLiteStore) GetUniversity(id string) (models.University, bool, error) {
+	row := s.db.QueryRow(`SELECT `+universityColumns+` FROM universities WHERE id = ?`, id)
+	uni, err := scanUniversity(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.University{}, false, nil
+	}
+	if err != nil {
+		return models.University{}, false, err
+	}
+	return uni, true, nil
+}
+
+// PutUniversity creates or replaces a university by ID
+func (s *SQLiteStore) PutUniversity(uni models.University) error {
+	args, err := universityArgs(uni)
+	if err != nil {
+		return err
+	}
+	// An upsert keeps the original rowid so listing order stays stable
+	_, err = s.db.Exec(`INSERT INTO universities (`+universityColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			name_en = excluded.name_en,
+			type = excluded.type,
+			location = excluded.location,
+			location_en = excluded.location_en,
+			region = excluded.region,
+			established = excluded.established,
+			rating = excluded.rating,
+			fees_min = excluded.fees_min,
+			fees_max = excluded.fees_max,
+			faculties = excluded.faculties,
+			faculties_en = excluded.faculties_en,
+			specialties = excluded.specialties,
+			description = excluded.description,
+			description_en = excluded.description_en,
+			image = excluded.image,
+			min_grade = excluded.min_grade,
+			max_grade = excluded.max_grade,
+			students = excluded.students,
+			acceptance_rate = excluded.acceptance_rate,
+			employment_rate = excluded.employment_rate,
+			detailed_faculties = excluded.detailed_faculties,
+			admission = excluded.admission,
+			languages = excluded.languages,
+			costs = excluded.costs,
+			fees_currency = excluded.fees_currency,
+			fees_period = excluded.fees_period,
+			editorial_rating = excluded.editorial_rating`, args...)
+	return err
+}
+
+// CreateUniversity adds a university, returning ErrUniversityExists when its
+// ID is in use
+func (s *SQLiteStore) CreateUniversity(uni models.University) error {
+	args, err := universityArgs(uni)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var taken bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM universities WHERE id = ?)`, uni.ID).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return ErrUniversityExists
+	}
+	if _, err := tx.Exec(`INSERT INTO universities (`+universityColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...); err != nil {
+		return fmt.Errorf("university %s: %w", uni.ID, err)
+	}
+	return tx.Commit()
+}
+
+// universityArgs returns the values of universityColumns for uni
+func universityArgs(uni models.University) ([]any, error) {
+	faculties, err := marshalJSON(uni.Faculties, "[]")
+	if err != nil {
+		return nil, err
+	}
+	facultiesEn, err := marshalJSON(uni.FacultiesEn, "[]")
+	if err != nil {
+		return nil, err
+	}
+	specialties, err := marshalJSON(uni.Specialties, "[]")
+	if err != nil {
+		return nil, err
+	}
+	detailed, err := marshalJSON(uni.DetailedFaculties, "{}")
+	if err != nil {
+		return nil, err
+	}
+	admission, err := marshalJSON(uni.Admission, "[]")
+	if err != nil {
+		return nil, err
+	}
+	languages, err := marshalJSON(uni.Languages, "[]")
+	if err != nil {
+		return nil, err
+	}
+	costs, err := marshalJSON(uni.Costs, "null")
+	if err != nil {
+		return nil, err
+	}
+	return []any{
+		uni.ID, uni.Name, uni.NameEn, uni.Type, uni.Location, uni.LocationEn, uni.Region,
+		uni.Established, uni.Rating, uni.Fees.Min, uni.Fees.Max, faculties, facultiesEn,
+		specialties, uni.Description, uni.DescriptionEn, uni.Image, uni.MinGrade, uni.MaxGrade,
+		uni.Students, uni.AcceptanceRate, uni.EmploymentRate, detailed, admission, languages, costs,
+		uni.Fees.Currency, uni.Fees.Period, uni.EditorialRating,
+	}, nil
+}
+
+// SetRatings sets the rating of the universities keyed by ID in one
+// transaction
+func (s *SQLiteStore) SetRatings(ratings map[string]float64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`UPDATE universities SET rating = ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for id, rating := range ratings {
+		if _, err := stmt.Exec(rating, id); err != nil {
+			return fmt.Errorf("university %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteUniversity removes a university, reporting whether it existed
+func (s *SQLiteStore) DeleteUniversity(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM universities WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const cutoffColumns = `university_id, faculty_id, faculty, faculty_en, track, gender, year, percentage, score, max_score`
+
+// ListCutoffs returns every cut-off ordered by university, faculty ID, track,
+// gender and year
+func (s *SQLiteStore) ListCutoffs() ([]models.Cutoff, error) {
+	rows, err := s.db.Query(`SELECT c.university_id, c.faculty_id, c.faculty, c.faculty_en, c.track,
+			c.gender, c.year, c.percentage, c.score, c.max_score
+		FROM cutoffs c JOIN universities u ON u.id = c.university_id
+		ORDER BY u.rowid, c.faculty_id, c.track, c.gender, c.year`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Cutoff
+	for rows.Next() {
+		var c models.Cutoff
+		if err := rows.Scan(&c.UniversityID, &c.FacultyID, &c.Faculty, &c.FacultyEn, &c.Track,
+			&c.Gender, &c.Year, &c.Percentage, &c.Score, &c.MaxScore); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+// PutCutoffs creates or replaces cut-offs keyed by university, faculty ID,
+// track, gender and year, in a single transaction
+func (s *SQLiteStore) PutCutoffs(cutoffs []models.Cutoff) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO cutoffs (` + cutoffColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (university_id, faculty_id, track, gender, year) DO UPDATE SET
+			faculty = excluded.faculty,
+			faculty_en = excluded.faculty_en,
+			percentage = excluded.percentage,
+			score = excluded.score,
+			max_score = excluded.max_score`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range cutoffs {
+		if _, err := stmt.Exec(c.UniversityID, c.FacultyID, c.Faculty, c.FacultyEn, c.Track,
+			c.Gender, c.Year, c.Percentage, c.Score, c.MaxScore); err != nil {
+			return fmt.Errorf("cut-off %s/%s/%s/%d: %w", c.UniversityID, c.FacultyID, c.Track, c.Year, err)
+		}
+	}
+	return tx.Commit()
+}
+
+const disciplineColumns = `id, name, name_en, description, description_en, field, aliases`
+
+// ListDisciplines returns every discipline in insertion order
+func (s *SQLiteStore) ListDisciplines() ([]models.Discipline, error) {
+	rows, err := s.db.Query(`SELECT ` + disciplineColumns + ` FROM disciplines ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Discipline
+	for rows.Next() {
+		var d models.Discipline
+		var aliases string
+		if err := rows.Scan(&d.ID, &d.Name, &d.NameEn, &d.Description, &d.DescriptionEn, &d.Field, &aliases); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSON(aliases, &d.Aliases); err != nil {
+			return nil, fmt.Errorf("discipline %s: aliases: %w", d.ID, err)
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+// PutDisciplines creates or replaces disciplines by ID in a single
+// transaction
+func (s *SQLiteStore) PutDisciplines(disciplines []models.Discipline) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// An upsert keeps the original rowid so listing order stays stable
+	stmt, err := tx.Prepare(`INSERT INTO disciplines (` + disciplineColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			name_en = excluded.name_en,
+			description = excluded.description,
+			description_en = excluded.description_en,
+			field = excluded.field,
+			aliases = excluded.aliases`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, d := range disciplines {
+		aliases, err := marshalJSON(d.Aliases, "[]")
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(d.ID, d.Name, d.NameEn, d.Description, d.DescriptionEn, d.Field, aliases); err != nil {
+			return fmt.Errorf("discipline %s: %w", d.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+const scholarshipColumns = `id, name, name_en, provider, provider_en, type, university_ids, faculties,
+	min_grade, discount_percent, discount_amount, currency, deadline, documents, documents_en`
+
+// ListScholarships returns every scholarship in insertion order
+func (s *SQLiteStore) ListScholarships() ([]models.Scholarship, error) {
+	rows, err := s.db.Query(`SELECT ` + scholarshipColumns + ` FROM scholarships ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Scholarship
+	for rows.Next() {
+		var sch models.Scholarship
+		var universityIDs, faculties, documents, documentsEn string
+		if err := rows.Scan(&sch.ID, &sch.Name, &sch.NameEn, &sch.Provider, &sch.ProviderEn, &sch.Type,
+			&universityIDs, &faculties, &sch.MinGrade, &sch.DiscountPercent, &sch.DiscountAmount,
+			&sch.Currency, &sch.Deadline, &documents, &documentsEn); err != nil {
+			return nil, err
+		}
+		for _, col := range []struct {
+			name  string
+			value string
+			dest  *[]string
+		}{
+			{"university_ids", universityIDs, &sch.UniversityIDs},
+			{"faculties", faculties, &sch.Faculties},
+			{"documents", documents, &sch.Documents},
+			{"documents_en", documentsEn, &sch.DocumentsEn},
+		} {
+			if err := unmarshalJSON(col.value, col.dest); err != nil {
+				return nil, fmt.Errorf("scholarship %s: %s: %w", sch.ID, col.name, err)
+			}
+		}
+		result = append(result, sch)
+	}
+	return result, rows.Err()
+}
+
+// PutScholarships creates or replaces scholarships by ID in a single
+// transaction
+func (s *SQLiteStore) PutScholarships(scholarships []models.Scholarship) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// An upsert keeps the original rowid so listing order stays stable
+	stmt, err := tx.Prepare(`INSERT INTO scholarships (` + scholarshipColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			name_en = excluded.name_en,
+			provider = excluded.provider,
+			provider_en = excluded.provider_en,
+			type = excluded.type,
+			university_ids = excluded.university_ids,
+			faculties = excluded.faculties,
+			min_grade = excluded.min_grade,
+			discount_percent = excluded.discount_percent,
+			discount_amount = excluded.discount_amount,
+			currency = excluded.currency,
+			deadline = excluded.deadline,
+			documents = excluded.documents,
+			documents_en = excluded.documents_en`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, sch := range scholarships {
+		lists := make([]string, 4)
+		for i, values := range [][]string{sch.UniversityIDs, sch.Faculties, sch.Documents, sch.DocumentsEn} {
+			if lists[i], err = marshalJSON(values, "[]"); err != nil {
+				return err
+			}
+		}
+		if _, err := stmt.Exec(sch.ID, sch.Name, sch.NameEn, sch.Provider, sch.ProviderEn, sch.Type,
+			lists[0], lists[1], sch.MinGrade, sch.DiscountPercent, sch.DiscountAmount,
+			sch.Currency, sch.Deadline, lists[2], lists[3]); err != nil {
+			return fmt.Errorf("scholarship %s: %w", sch.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+const eventColumns = `id, university_id, faculty, kind, title, title_en, description, description_en,
+	start_date, end_date, location, location_en, url`
+
+// ListEvents returns every admission event in insertion order
+func (s *SQLiteStore) ListEvents() ([]models.AdmissionEvent, error) {
+	rows, err := s.db.Query(`SELECT ` + eventColumns + ` FROM events ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.AdmissionEvent
+	for rows.Next() {
+		var e models.AdmissionEvent
+		var universityID sql.NullString
+		if err := rows.Scan(&e.ID, &universityID, &e.Faculty, &e.Kind, &e.Title, &e.TitleEn, &e.Description,
+			&e.DescriptionEn, &e.Start, &e.End, &e.Location, &e.LocationEn, &e.URL); err != nil {
+			return nil, err
+		}
+		e.UniversityID = universityID.String
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// PutEvents creates or replaces admission events by ID in a single
+// transaction
+func (s *SQLiteStore) PutEvents(events []models.AdmissionEvent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Nationwide events are stored without a university to satisfy the
+	// foreign key; an upsert keeps the original rowid so listing order
+	// stays stable
+	stmt, err := tx.Prepare(`INSERT INTO events (` + eventColumns + `)
+		VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			university_id = excluded.university_id,
+			faculty = excluded.faculty,
+			kind = excluded.kind,
+			title = excluded.title,
+			title_en = excluded.title_en,
+			description = excluded.description,
+			description_en = excluded.description_en,
+			start_date = excluded.start_date,
+			end_date = excluded.end_date,
+			location = excluded.location,
+			location_en = excluded.location_en,
+			url = excluded.url`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.ID, e.UniversityID, e.Faculty, e.Kind, e.Title, e.TitleEn, e.Description,
+			e.DescriptionEn, e.Start, e.End, e.Location, e.LocationEn, e.URL); err != nil {
+			return fmt.Errorf("event %s: %w", e.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteEvent removes an admission event, reporting whether it existed
+func (s *SQLiteStore) DeleteEvent(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM events WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const userColumns = `id, email, name, role, password_hash, created_at`
+
+// ListUsers returns every account in creation order
+func (s *SQLiteStore) ListUsers() ([]models.User, error) {
+	rows, err := s.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+	return result, rows.Err()
+}
+
+// GetUser returns an account by ID
+func (s *SQLiteStore) GetUser(id string) (models.User, bool, error) {
+	return s.getUser(`id = ?`, id)
+}
+
+// GetUserByEmail returns an account by email
+func (s *SQLiteStore) GetUserByEmail(email string) (models.User, bool, error) {
+	return s.getUser(`email = ?`, email)
+}
+
+func (s *SQLiteStore) getUser(where string, arg string) (models.User, bool, error) {
+	u, err := scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE `+where, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, false, nil
+	}
+	if err != nil {
+		return models.User{}, false, err
+	}
+	return u, true, nil
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var u models.User
+	var created string
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.PasswordHash, &created); err != nil {
+		return u, err
+	}
+	var err error
+	if u.CreatedAt, err = time.Parse(time.RFC3339, created); err != nil {
+		return u, fmt.Errorf("user %s: created_at: %w", u.ID, err)
+	}
+	return u, nil
+}
+
+// CreateUser adds an account, returning ErrEmailTaken when its email is in
+// use
+func (s *SQLiteStore) CreateUser(user models.User) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var taken bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE email = ?)`, user.Email).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	if _, err := tx.Exec(`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Email, user.Name, user.Role, user.PasswordHash, user.CreatedAt.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("user %s: %w", user.ID, err)
+	}
+	return tx.Commit()
+}
+
+// SetUserRole changes the role of an account, reporting whether it exists
+func (s *SQLiteStore) SetUserRole(id, role string) (bool, error) {
+	res, err := s.db.Exec(`UPDATE users SET role = ? WHERE id = ?`, role, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// PutRefreshToken stores a refresh token
+func (s *SQLiteStore) PutRefreshToken(token models.RefreshToken) error {
+	_, err := s.db.Exec(`INSERT INTO refresh_tokens (hash, user_id, expires_at) VALUES (?, ?, ?)`,
+		token.Hash, token.UserID, token.ExpiresAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+// TakeRefreshToken removes and returns a refresh token by hash
+func (s *SQLiteStore) TakeRefreshToken(hash string) (models.RefreshToken, bool, error) {
+	token := models.RefreshToken{Hash: hash}
+	var expires string
+	err := s.db.QueryRow(`DELETE FROM refresh_tokens WHERE hash = ? RETURNING user_id, expires_at`, hash).
+		Scan(&token.UserID, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.RefreshToken{}, false, nil
+	}
+	if err != nil {
+		return models.RefreshToken{}, false, err
+	}
+	if token.ExpiresAt, err = time.Parse(time.RFC3339, expires); err != nil {
+		return models.RefreshToken{}, false, fmt.Errorf("refresh token: expires_at: %w", err)
+	}
+	return token, true, nil
+}
+
+// ListFavorites returns the favorites of a user in the order added
+func (s *SQLiteStore) ListFavorites(userID string) ([]models.Favorite, error) {
+	rows, err := s.db.Query(`SELECT university_id, created_at FROM favorites WHERE user_id = ? ORDER BY rowid`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Favorite
+	for rows.Next() {
+		fav := models.Favorite{UserID: userID}
+		var created string
+		if err := rows.Scan(&fav.UniversityID, &created); err != nil {
+			return nil, err
+		}
+		if fav.CreatedAt, err = time.Parse(time.RFC3339, created); err != nil {
+			return nil, fmt.Errorf("favorite %s: created_at: %w", fav.UniversityID, err)
+		}
+		result = append(result, fav)
+	}
+	return result, rows.Err()
+}
+
+// AddFavorite stores a favorite, reporting false when the user already has
+// the university
+func (s *SQLiteStore) AddFavorite(fav models.Favorite) (bool, error) {
+	res, err := s.db.Exec(`INSERT INTO favorites (user_id, university_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		fav.UserID, fav.UniversityID, fav.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RemoveFavorite removes a favorite, reporting whether it existed
+func (s *SQLiteStore) RemoveFavorite(userID, universityID string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM favorites WHERE user_id = ? AND university_id = ?`, userID, universityID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const shortlistColumns = `id, user_id, name, created_at, updated_at`
+
+// ListShortlists returns the shortlists of a user in creation order
+func (s *SQLiteStore) ListShortlists(userID string) ([]models.Shortlist, error) {
+	rows, err := s.db.Query(`SELECT `+shortlistColumns+` FROM shortlists WHERE user_id = ? ORDER BY rowid`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Shortlist
+	for rows.Next() {
+		list, err := scanShortlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := s.loadShortlistItems(result, `l.user_id = ?`, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetShortlist returns a shortlist of a user by ID
+func (s *SQLiteStore) GetShortlist(userID, id string) (models.Shortlist, bool, error) {
+	list, err := scanShortlist(s.db.QueryRow(`SELECT `+shortlistColumns+` FROM shortlists WHERE id = ? AND user_id = ?`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Shortlist{}, false, nil
+	}
+	if err != nil {
+		return models.Shortlist{}, false, err
+	}
+
+	lists := []models.Shortlist{list}
+	if err := s.loadShortlistItems(lists, `l.id = ?`, id); err != nil {
+		return models.Shortlist{}, false, err
+	}
+	return lists[0], true, nil
+}
+
+func scanShortlist(row rowScanner) (models.Shortlist, error) {
+	list := models.Shortlist{Items: []models.ShortlistItem{}}
+	var created, updated string
+	if err := row.Scan(&list.ID, &list.UserID, &list.Name, &created, &updated); err != nil {
+		return list, err
+	}
+	var err error
+	if list.CreatedAt, err = time.Parse(time.RFC3339, created); err != nil {
+		return list, fmt.Errorf("shortlist %s: created_at: %w", list.ID, err)
+	}
+	if list.UpdatedAt, err = time.Parse(time.RFC3339, updated); err != nil {
+		return list, fmt.Errorf("shortlist %s: updated_at: %w", list.ID, err)
+	}
+	return list, nil
+}
+
+// loadShortlistItems fills in the items of lists, selecting them from the
+// shortlists matching where
+func (s *SQLiteStore) loadShortlistItems(lists []models.Shortlist, where string, arg string) error {
+	index := make(map[string]int, len(lists))
+	for i, list := range lists {
+		index[list.ID] = i
+	}
+
+	rows, err := s.db.Query(`SELECT i.shortlist_id, i.university_id, i.faculty, i.department, i.note
+		FROM shortlist_items i JOIN shortlists l ON l.id = i.shortlist_id
+		WHERE `+where+` ORDER BY i.shortlist_id, i.position`, arg)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var item models.ShortlistItem
+		if err := rows.Scan(&id, &item.UniversityID, &item.Faculty, &item.Department, &item.Note); err != nil {
+			return err
+		}
+		if i, found := index[id]; found {
+			lists[i].Items = append(lists[i].Items, item)
+		}
+	}
+	return rows.Err()
+}
+
+// PutShortlist creates or replaces a shortlist by ID and user in a single
+// transaction
+func (s *SQLiteStore) PutShortlist(list models.Shortlist) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The upsert keeps the original rowid, and with it the listing order,
+	// and leaves shortlists of other users alone
+	res, err := tx.Exec(`INSERT INTO shortlists (`+shortlistColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			updated_at = excluded.updated_at
+		WHERE shortlists.user_id = excluded.user_id`,
+		list.ID, list.UserID, list.Name, list.CreatedAt.UTC().Format(time.RFC3339), list.UpdatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("shortlist %s: %w", list.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("shortlist %s belongs to another user", list.ID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM shortlist_items WHERE shortlist_id = ?`, list.ID); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO shortlist_items (shortlist_id, position, university_id, faculty, department, note)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, item := range list.Items {
+		if _, err := stmt.Exec(list.ID, i, item.UniversityID, item.Faculty, item.Department, item.Note); err != nil {
+			return fmt.Errorf("shortlist %s: item %d: %w", list.ID, i, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteShortlist removes a shortlist of a user, reporting whether it
+// existed
+func (s *SQLiteStore) DeleteShortlist(userID, id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM shortlists WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const savedSearchColumns = `id, user_id, name, params, currency, notify, matches, created_at, updated_at, checked_at`
+
+// ListSavedSearches returns the saved searches of a user in creation order
+func (s *SQLiteStore) ListSavedSearches(userID string) ([]models.SavedSearch, error) {
+	return s.listSavedSearches(`user_id = ?`, userID)
+}
+
+// ListWatchedSearches returns every saved search with notifications on, in
+// creation order
+func (s *SQLiteStore) ListWatchedSearches() ([]models.SavedSearch, error) {
+	return s.listSavedSearches(`notify = ?`, true)
+}
+
+func (s *SQLiteStore) listSavedSearches(where string, arg any) ([]models.SavedSearch, error) {
+	rows, err := s.db.Query(`SELECT `+savedSearchColumns+` FROM saved_searches WHERE `+where+` ORDER BY rowid`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, search)
+	}
+	return result, rows.Err()
+}
+
+// GetSavedSearch returns a saved search of a user by ID
+func (s *SQLiteStore) GetSavedSearch(userID, id string) (models.SavedSearch, bool, error) {
+	search, err := scanSavedSearch(s.db.QueryRow(`SELECT `+savedSearchColumns+` FROM saved_searches
+		WHERE id = ? AND user_id = ?`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SavedSearch{}, false, nil
+	}
+	if err != nil {
+		return models.SavedSearch{}, false, err
+	}
+	return search, true, nil
+}
+
+func scanSavedSearch(row rowScanner) (models.SavedSearch, error) {
+	var search models.SavedSearch
+	var params, matches, created, updated, checked string
+	if err := row.Scan(&search.ID, &search.UserID, &search.Name, &params, &search.Currency, &search.Notify,
+		&matches, &created, &updated, &checked); err != nil {
+		return search, err
+	}
+
+	if err := unmarshalJSON(params, &search.Params); err != nil {
+		return search, fmt.Errorf("saved search %s: params: %w", search.ID, err)
+	}
+	if err := unmarshalJSON(matches, &search.Matches); err != nil {
+		return search, fmt.Errorf("saved search %s: matches: %w", search.ID, err)
+	}
+	if search.Matches == nil {
+		search.Matches = []models.SearchMatch{}
+	}
+	for _, t := range []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{{"created_at", created, &search.CreatedAt}, {"updated_at", updated, &search.UpdatedAt}, {"checked_at", checked, &search.CheckedAt}} {
+		var err error
+		if *t.dest, err = time.Parse(time.RFC3339, t.value); err != nil {
+			return search, fmt.Errorf("saved search %s: %s: %w", search.ID, t.name, err)
+		}
+	}
+	return search, nil
+}
+
+// PutSavedSearch creates or replaces a saved search by ID and user
+func (s *SQLiteStore) PutSavedSearch(search models.SavedSearch) error {
+	params, err := marshalJSON(search.Params, "{}")
+	if err != nil {
+		return fmt.Errorf("saved search %s: params: %w", search.ID, err)
+	}
+	matches, err := marshalJSON(search.Matches, "[]")
+	if err != nil {
+		return fmt.Errorf("saved search %s: matches: %w", search.ID, err)
+	}
+
+	// The upsert keeps the original rowid, and with it the listing order,
+	// and leaves searches of other users alone
+	res, err := s.db.Exec(`INSERT INTO saved_searches (`+savedSearchColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			params = excluded.params,
+			currency = excluded.currency,
+			notify = excluded.notify,
+			matches = excluded.matches,
+			updated_at = excluded.updated_at,
+			checked_at = excluded.checked_at
+		WHERE saved_searches.user_id = excluded.user_id`,
+		search.ID, search.UserID, search.Name, params, search.Currency, search.Notify, matches,
+		search.CreatedAt.UTC().Format(time.RFC3339), search.UpdatedAt.UTC().Format(time.RFC3339),
+		search.CheckedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("saved search %s: %w", search.ID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("saved search %s belongs to another user", search.ID)
+	}
+	return nil
+}
+
+// SetSearchMatches records the results of a saved search as of checkedAt,
+// reporting whether it exists
+func (s *SQLiteStore) SetSearchMatches(id string, matches []models.SearchMatch, checkedAt time.Time) (bool, error) {
+	encoded, err := marshalJSON(matches, "[]")
+	if err != nil {
+		return false, fmt.Errorf("saved search %s: matches: %w", id, err)
+	}
+	res, err := s.db.Exec(`UPDATE saved_searches SET matches = ?, checked_at = ? WHERE id = ?`,
+		encoded, checkedAt.UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteSavedSearch removes a saved search of a user, reporting whether it
+// existed
+func (s *SQLiteStore) DeleteSavedSearch(userID, id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM saved_searches WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const reviewColumns = `id, user_id, university_id, faculty, overall, teaching, facilities, housing,
+	career_support, title, body, status, status_reason, flags, created_at, updated_at`
+
+// ListReviews returns the reviews passing filter in submission order
+func (s *SQLiteStore) ListReviews(filter ReviewFilter) ([]models.Review, error) {
+	var where []string
+	var args []any
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{{"university_id", filter.UniversityID}, {"user_id", filter.UserID}, {"status", filter.Status}} {
+		if cond.value != "" {
+			where = append(where, cond.column+" = ?")
+			args = append(args, cond.value)
+		}
+	}
+	query := `SELECT ` + reviewColumns + ` FROM reviews`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+
+	rows, err := s.db.Query(query+` ORDER BY rowid`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.Review
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// GetReview returns a review by ID
+func (s *SQLiteStore) GetReview(id string) (models.Review, bool, error) {
+	r, err := scanReview(s.db.QueryRow(`SELECT `+reviewColumns+` FROM reviews WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Review{}, false, nil
+	}
+	if err != nil {
+		return models.Review{}, false, err
+	}
+	return r, true, nil
+}
+
+func scanReview(row rowScanner) (models.Review, error) {
+	var r models.Review
+	var flags, created, updated string
+	if err := row.Scan(&r.ID, &r.UserID, &r.UniversityID, &r.Faculty, &r.Overall, &r.Ratings.Teaching,
+		&r.Ratings.Facilities, &r.Ratings.Housing, &r.Ratings.CareerSupport, &r.Title, &r.Body, &r.Status,
+		&r.StatusReason, &flags, &created, &updated); err != nil {
+		return r, err
+	}
+
+	if err := unmarshalJSON(flags, &r.Flags); err != nil {
+		return r, fmt.Errorf("review %s: flags: %w", r.ID, err)
+	}
+	var err error
+	if r.CreatedAt, err = time.Parse(time.RFC3339, created); err != nil {
+		return r, fmt.Errorf("review %s: created_at: %w", r.ID, err)
+	}
+	if r.UpdatedAt, err = time.Parse(time.RFC3339, updated); err != nil {
+		return r, fmt.Errorf("review %s: updated_at: %w", r.ID, err)
+	}
+	return r, nil
+}
+
+// PutReview creates or replaces a review by ID
+func (s *SQLiteStore) PutReview(r models.Review) error {
+	flags, err := marshalJSON(r.Flags, "[]")
+	if err != nil {
+		return fmt.Errorf("review %s: flags: %w", r.ID, err)
+	}
+
+	// The upsert keeps the original rowid, and with it the listing order
+	_, err = s.db.Exec(`INSERT INTO reviews (`+reviewColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = excluded.user_id,
+			university_id = excluded.university_id,
+			faculty = excluded.faculty,
+			overall = excluded.overall,
+			teaching = excluded.teaching,
+			facilities = excluded.facilities,
+			housing = excluded.housing,
+			career_support = excluded.career_support,
+			title = excluded.title,
+			body = excluded.body,
+			status = excluded.status,
+			status_reason = excluded.status_reason,
+			flags = excluded.flags,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at`,
+		r.ID, r.UserID, r.UniversityID, r.Faculty, r.Overall, r.Ratings.Teaching, r.Ratings.Facilities,
+		r.Ratings.Housing, r.Ratings.CareerSupport, r.Title, r.Body, r.Status, r.StatusReason, flags,
+		r.CreatedAt.UTC().Format(time.RFC3339), r.UpdatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("review %s: %w", r.ID, err)
+	}
+	return nil
+}
+
+// DeleteReview removes a review, reporting whether it existed
+func (s *SQLiteStore) DeleteReview(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM reviews WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const moderationColumns = `id, review_id, university_id, moderator_id, action, previous_status, status,
+	reason, flags, created_at`
+
+// ListModerationActions returns the audit entries passing filter in the order
+// recorded
+func (s *SQLiteStore) ListModerationActions(filter ModerationFilter) ([]models.ModerationAction, error) {
+	var where []string
+	var args []any
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{{"review_id", filter.ReviewID}, {"moderator_id", filter.ModeratorID}} {
+		if cond.value != "" {
+			where = append(where, cond.column+" = ?")
+			args = append(args, cond.value)
+		}
+	}
+	query := `SELECT ` + moderationColumns + ` FROM moderation_actions`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+
+	rows, err := s.db.Query(query+` ORDER BY rowid`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.ModerationAction
+	for rows.Next() {
+		var a models.ModerationAction
+		var flags, created string
+		if err := rows.Scan(&a.ID, &a.ReviewID, &a.UniversityID, &a.ModeratorID, &a.Action, &a.PreviousStatus,
+			&a.Status, &a.Reason, &flags, &created); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSON(flags, &a.Flags); err != nil {
+			return nil, fmt.Errorf("moderation action %s: flags: %w", a.ID, err)
+		}
+		if a.CreatedAt, err = time.Parse(time.RFC3339, created); err != nil {
+			return nil, fmt.Errorf("moderation action %s: created_at: %w", a.ID, err)
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// AddModerationAction records an audit entry
+func (s *SQLiteStore) AddModerationAction(a models.ModerationAction) error {
+	return addModerationAction(s.db, a)
+}
+
+// ModerateReview sets the status of review r and records the audit entry
+// of the decision in one transaction, provided the review is unchanged
+func (s *SQLiteStore) ModerateReview(r models.Review, a models.ModerationAction) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE reviews SET status = ?, status_reason = ?
+		WHERE id = ? AND updated_at = ? AND status = ?`,
+		r.Status, r.StatusReason, r.ID, r.UpdatedAt.UTC().Format(time.RFC3339), a.PreviousStatus)
+	if err != nil {
+		return fmt.Errorf("review %s: %w", r.ID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrReviewChanged
+	}
+	if err := addModerationAction(tx, a); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func addModerationAction(db execer, a models.ModerationAction) error {
+	flags, err := marshalJSON(a.Flags, "[]")
+	if err != nil {
+		return fmt.Errorf("moderation action %s: flags: %w", a.ID, err)
+	}
+	_, err = db.Exec(`INSERT INTO moderation_actions (`+moderationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, a.ReviewID, a.UniversityID, a.ModeratorID, a.Action, a.PreviousStatus, a.Status, a.Reason, flags,
+		a.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("moderation action %s: %w", a.ID, err)
+	}
+	return nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func scanUniversity(row rowScanner) (models.University, error) {
+	var uni models.University
+	var faculties, facultiesEn, specialties, detailed, admission, languages, costs string
+
+	err := row.Scan(
+		&uni.ID, &uni.Name, &uni.NameEn, &uni.Type, &uni.Location, &uni.LocationEn, &uni.Region,
+		&uni.Established, &uni.Rating, &uni.Fees.Min, &uni.Fees.Max, &faculties, &facultiesEn,
+		&specialties, &uni.Description, &uni.DescriptionEn, &uni.Image, &uni.MinGrade, &uni.MaxGrade,
+		&uni.Students, &uni.AcceptanceRate, &uni.EmploymentRate, &detailed, &admission, &languages, &costs,
+		&uni.Fees.Currency, &uni.Fees.Period, &uni.EditorialRating,
+	)
+	if err != nil {
+		return models.University{}, err
+	}
+
+	if err := unmarshalJSON(faculties, &uni.Faculties); err != nil {
+		return models.University{}, fmt.Errorf("university %s faculties: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(facultiesEn, &uni.FacultiesEn); err != nil {
+		return models.University{}, fmt.Errorf("university %s facultiesEn: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(specialties, &uni.Specialties); err != nil {
+		return models.University{}, fmt.Errorf("university %s specialties: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(detailed, &uni.DetailedFaculties); err != nil {
+		return models.University{}, fmt.Errorf("university %s detailedFaculties: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(admission, &uni.Admission); err != nil {
+		return models.University{}, fmt.Errorf("university %s admission: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(languages, &uni.Languages); err != nil {
+		return models.University{}, fmt.Errorf("university %s languages: %w", uni.ID, err)
+	}
+	if err := unmarshalJSON(costs, &uni.Costs); err != nil {
+		return models.University{}, fmt.Errorf("university %s costs: %w", uni.ID, err)
+	}
+	return uni, nil
+}
+
+// marshalJSON encodes a column value, storing empty values as fallback
+func marshalJSON(v any, fallback string) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if string(b) == "null" {
+		return fallback, nil
+	}
+	return string(b), nil
+}
+
+// unmarshalJSON decodes a column value, leaving empty collections nil
+func unmarshalJSON(s string, v any) error {
+	if s == "" || s == "[]" || s == "{}" || s == "null" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), v)
+}
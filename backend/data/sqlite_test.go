@@ -0,0 +1,64 @@
+package data_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"roadtouniversities/data"
+	"roadtouniversities/data/storetest"
+	"roadtouniversities/models"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) data.Store {
+		s, err := data.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteStore: %v", err)
+		}
+		return s
+	})
+}
+
+func TestSQLiteModerateReviewRollsBack(t *testing.T) {
+	s, err := data.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.PutUniversity(models.University{ID: "1", Name: "جامعة", NameEn: "University"}); err != nil {
+		t.Fatalf("PutUniversity: %v", err)
+	}
+	for _, id := range []string{"a1", "m1"} {
+		if err := s.CreateUser(models.User{ID: id, Email: id + "@example.com", Role: models.RoleStudent}); err != nil {
+			t.Fatalf("CreateUser %s: %v", id, err)
+		}
+	}
+	at := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	review := models.Review{ID: "r1", UserID: "a1", UniversityID: "1", Overall: 4, Status: models.ReviewPending,
+		CreatedAt: at, UpdatedAt: at}
+	if err := s.PutReview(review); err != nil {
+		t.Fatalf("PutReview: %v", err)
+	}
+	action := models.ModerationAction{ID: "x1", ReviewID: "r1", UniversityID: "1", ModeratorID: "m1",
+		Action: models.ModerationReject, PreviousStatus: models.ReviewPending, Status: models.ReviewRejected, CreatedAt: at}
+	if err := s.AddModerationAction(action); err != nil {
+		t.Fatalf("AddModerationAction: %v", err)
+	}
+
+	// Reusing the audit entry's ID fails the insert, which must undo the
+	// review update
+	approved := review
+	approved.Status = models.ReviewApproved
+	if err := s.ModerateReview(approved, action); err == nil {
+		t.Fatal("ModerateReview with a duplicate action ID succeeded")
+	}
+	got, _, err := s.GetReview("r1")
+	if err != nil {
+		t.Fatalf("GetReview: %v", err)
+	}
+	if got.Status != models.ReviewPending {
+		t.Errorf("review status = %s after a failed ModerateReview, want %s", got.Status, models.ReviewPending)
+	}
+}
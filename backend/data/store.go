@@ -0,0 +1,282 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// Store backends selectable through configuration
+const (
+	DriverMemory = "memory"
+	DriverSQLite = "sqlite"
+)
+
+// ErrUnknownDriver is returned by Open for an unsupported backend name
+var ErrUnknownDriver = errors.New("unknown store driver")
+
+// ErrUniversityExists is returned when creating a university with an ID
+// already in use
+var ErrUniversityExists = errors.New("university ID already exists")
+
+// ErrReviewChanged is returned when moderating a review that was edited,
+// moderated or deleted since it was read
+var ErrReviewChanged = errors.New("review changed since it was read")
+
+// Store is the persistence layer the handlers depend on
+type Store interface {
+	CutoffStore
+	DisciplineStore
+	ScholarshipStore
+	CalendarStore
+	UserStore
+	ShortlistStore
+	SavedSearchStore
+	ReviewStore
+	ModerationStore
+
+	// ListUniversities returns every university in insertion order
+	ListUniversities() ([]models.University, error)
+	// GetUniversity returns a university by ID
+	GetUniversity(id string) (models.University, bool, error)
+	// PutUniversity creates or replaces a university by ID
+	PutUniversity(uni models.University) error
+	// CreateUniversity adds a university, returning ErrUniversityExists
+	// when its ID is in use
+	CreateUniversity(uni models.University) error
+	// SetRatings sets the rating of the universities keyed by ID, leaving
+	// the rest of their records alone; unknown IDs are skipped
+	SetRatings(ratings map[string]float64) error
+	// DeleteUniversity removes a university with its cut-offs, admission
+	// events, favorites, shortlist items and reviews, reporting whether it
+	// existed
+	DeleteUniversity(id string) (bool, error)
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// CutoffStore persists historical admission cut-offs
+type CutoffStore interface {
+	// ListCutoffs returns every cut-off ordered by university (in insertion
+	// order), faculty ID, track, gender and year
+	ListCutoffs() ([]models.Cutoff, error)
+	// PutCutoffs creates or replaces cut-offs keyed by university, faculty
+	// ID, track, gender and year
+	PutCutoffs(cutoffs []models.Cutoff) error
+}
+
+// DisciplineStore persists the faculty catalogue
+type DisciplineStore interface {
+	// ListDisciplines returns every discipline in insertion order
+	ListDisciplines() ([]models.Discipline, error)
+	// PutDisciplines creates or replaces disciplines by ID
+	PutDisciplines(disciplines []models.Discipline) error
+}
+
+// ScholarshipStore persists the scholarship catalogue
+type ScholarshipStore interface {
+	// ListScholarships returns every scholarship in insertion order
+	ListScholarships() ([]models.Scholarship, error)
+	// PutScholarships creates or replaces scholarships by ID
+	PutScholarships(scholarships []models.Scholarship) error
+}
+
+// CalendarStore persists the admissions calendar
+type CalendarStore interface {
+	// ListEvents returns every admission event in insertion order
+	ListEvents() ([]models.AdmissionEvent, error)
+	// PutEvents creates or replaces admission events by ID
+	PutEvents(events []models.AdmissionEvent) error
+	// DeleteEvent removes an admission event, reporting whether it existed
+	DeleteEvent(id string) (bool, error)
+}
+
+// UserStore persists accounts and their refresh tokens. Emails are
+// compared as given; callers normalise them with NormalizeEmail.
+type UserStore interface {
+	// ListUsers returns every account in creation order
+	ListUsers() ([]models.User, error)
+	// GetUser returns an account by ID
+	GetUser(id string) (models.User, bool, error)
+	// GetUserByEmail returns an account by email
+	GetUserByEmail(email string) (models.User, bool, error)
+	// CreateUser adds an account, returning ErrEmailTaken when its email
+	// is in use
+	CreateUser(user models.User) error
+	// SetUserRole changes the role of an account, reporting whether it
+	// exists
+	SetUserRole(id, role string) (bool, error)
+	// PutRefreshToken stores a refresh token
+	PutRefreshToken(token models.RefreshToken) error
+	// TakeRefreshToken removes and returns a refresh token by hash, so each
+	// token is used once
+	TakeRefreshToken(hash string) (models.RefreshToken, bool, error)
+}
+
+// ShortlistStore persists the favorites and shortlists of accounts. Every
+// method is scoped to one user.
+type ShortlistStore interface {
+	// ListFavorites returns the favorites of a user in the order added
+	ListFavorites(userID string) ([]models.Favorite, error)
+	// AddFavorite stores a favorite, reporting false when the user already
+	// has the university
+	AddFavorite(fav models.Favorite) (bool, error)
+	// RemoveFavorite removes a favorite, reporting whether it existed
+	RemoveFavorite(userID, universityID string) (bool, error)
+	// ListShortlists returns the shortlists of a user in creation order
+	ListShortlists(userID string) ([]models.Shortlist, error)
+	// GetShortlist returns a shortlist of a user by ID
+	GetShortlist(userID, id string) (models.Shortlist, bool, error)
+	// PutShortlist creates or replaces a shortlist by ID and user
+	PutShortlist(list models.Shortlist) error
+	// DeleteShortlist removes a shortlist of a user, reporting whether it
+	// existed
+	DeleteShortlist(userID, id string) (bool, error)
+}
+
+// SavedSearchStore persists the saved searches of accounts
+type SavedSearchStore interface {
+	// ListSavedSearches returns the saved searches of a user in creation
+	// order
+	ListSavedSearches(userID string) ([]models.SavedSearch, error)
+	// ListWatchedSearches returns every saved search with notifications on,
+	// across users, in creation order
+	ListWatchedSearches() ([]models.SavedSearch, error)
+	// GetSavedSearch returns a saved search of a user by ID
+	GetSavedSearch(userID, id string) (models.SavedSearch, bool, error)
+	// PutSavedSearch creates or replaces a saved search by ID and user
+	PutSavedSearch(search models.SavedSearch) error
+	// SetSearchMatches records the results of a saved search as of
+	// checkedAt, reporting whether it exists
+	SetSearchMatches(id string, matches []models.SearchMatch, checkedAt time.Time) (bool, error)
+	// DeleteSavedSearch removes a saved search of a user, reporting whether
+	// it existed
+	DeleteSavedSearch(userID, id string) (bool, error)
+}
+
+// ReviewStore persists student reviews
+type ReviewStore interface {
+	// ListReviews returns the reviews passing filter in submission order
+	ListReviews(filter ReviewFilter) ([]models.Review, error)
+	// GetReview returns a review by ID
+	GetReview(id string) (models.Review, bool, error)
+	// PutReview creates or replaces a review by ID
+	PutReview(r models.Review) error
+	// DeleteReview removes a review, reporting whether it existed
+	DeleteReview(id string) (bool, error)
+}
+
+// ModerationStore persists the moderation audit trail. Entries are never
+// changed or removed, and outlive the reviews they are about.
+type ModerationStore interface {
+	// ListModerationActions returns the audit entries passing filter in the
+	// order recorded
+	ListModerationActions(filter ModerationFilter) ([]models.ModerationAction, error)
+	// AddModerationAction records an audit entry
+	AddModerationAction(action models.ModerationAction) error
+	// ModerateReview sets the status and status reason of review r and
+	// records the audit entry of the decision together: either both are
+	// saved or neither is. The stored review must still have r's UpdatedAt
+	// and the action's PreviousStatus, or ErrReviewChanged is returned.
+	ModerateReview(r models.Review, action models.ModerationAction) error
+}
+
+// Config selects and configures a Store backend
+type Config struct {
+	Driver     string
+	SQLitePath string
+}
+
+// Open creates the store described by cfg
+func Open(cfg Config) (Store, error) {
+	switch cfg.Driver {
+	case "", DriverMemory:
+		return NewMemoryStore(), nil
+	case DriverSQLite:
+		return NewSQLiteStore(cfg.SQLitePath)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// Seed fills an empty store with the given universities
+func Seed(store Store, unis []models.University) error {
+	existing, err := store.ListUniversities()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	for _, uni := range unis {
+		if err := store.PutUniversity(uni); err != nil {
+			return fmt.Errorf("seed university %s: %w", uni.ID, err)
+		}
+	}
+	return nil
+}
+
+// SeedCutoffs fills a store without cut-offs with the given records
+func SeedCutoffs(store Store, cutoffs []models.Cutoff) error {
+	existing, err := store.ListCutoffs()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(cutoffs) == 0 {
+		return nil
+	}
+	if err := store.PutCutoffs(cutoffs); err != nil {
+		return fmt.Errorf("seed cut-offs: %w", err)
+	}
+	return nil
+}
+
+// SeedDisciplines fills a store without a faculty catalogue with the given
+// disciplines
+func SeedDisciplines(store Store, disciplines []models.Discipline) error {
+	existing, err := store.ListDisciplines()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(disciplines) == 0 {
+		return nil
+	}
+	if err := store.PutDisciplines(disciplines); err != nil {
+		return fmt.Errorf("seed disciplines: %w", err)
+	}
+	return nil
+}
+
+// SeedScholarships fills a store without scholarships with the given records
+func SeedScholarships(store Store, scholarships []models.Scholarship) error {
+	existing, err := store.ListScholarships()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(scholarships) == 0 {
+		return nil
+	}
+	if err := store.PutScholarships(scholarships); err != nil {
+		return fmt.Errorf("seed scholarships: %w", err)
+	}
+	return nil
+}
+
+// SeedEvents fills a store without an admissions calendar with the given
+// events
+func SeedEvents(store Store, events []models.AdmissionEvent) error {
+	existing, err := store.ListEvents()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(events) == 0 {
+		return nil
+	}
+	if err := store.PutEvents(events); err != nil {
+		return fmt.Errorf("seed admission events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,1148 @@
+// Package storetest provides a conformance suite that every data.Store
+// implementation must pass. Backends call Run from their own tests:
+//
+//	func TestMemoryStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) data.Store { return data.NewMemoryStore() })
+//	}
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// Factory returns a new, empty store. The suite closes it when done.
+type Factory func(t *testing.T) data.Store
+
+// Run exercises a Store implementation against the shared contract
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s data.Store)
+	}{
+		{"EmptyStore", testEmptyStore},
+		{"PutAndGet", testPutAndGet},
+		{"ListKeepsInsertionOrder", testListOrder},
+		{"PutReplacesByID", testPutReplaces},
+		{"CreateDoesNotReplace", testCreate},
+		{"SetRatings", testSetRatings},
+		{"Delete", testDelete},
+		{"ReturnsCopies", testReturnsCopies},
+		{"Seed", testSeed},
+		{"Cutoffs", testCutoffs},
+		{"DeleteRemovesCutoffs", testDeleteRemovesCutoffs},
+		{"SeedCutoffs", testSeedCutoffs},
+		{"Disciplines", testDisciplines},
+		{"SeedDisciplines", testSeedDisciplines},
+		{"Scholarships", testScholarships},
+		{"SeedScholarships", testSeedScholarships},
+		{"Events", testEvents},
+		{"DeleteEvent", testDeleteEvent},
+		{"DeleteRemovesEvents", testDeleteRemovesEvents},
+		{"SeedEvents", testSeedEvents},
+		{"Users", testUsers},
+		{"RefreshTokens", testRefreshTokens},
+		{"Favorites", testFavorites},
+		{"Shortlists", testShortlists},
+		{"DeleteRemovesShortlistItems", testDeleteRemovesShortlistItems},
+		{"SavedSearches", testSavedSearches},
+		{"Reviews", testReviews},
+		{"DeleteRemovesReviews", testDeleteRemovesReviews},
+		{"ModerationActions", testModerationActions},
+		{"ModerateReview", testModerateReview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore(t)
+			defer s.Close()
+			tt.fn(t, s)
+		})
+	}
+}
+
+func sampleUniversity(id string) models.University {
+	return models.University{
+		ID:              id,
+		Name:            "جامعة الاختبار " + id,
+		NameEn:          "Test University " + id,
+		Type:            "public",
+		Location:        "القاهرة، مصر",
+		LocationEn:      "Cairo, Egypt",
+		Region:          "cairo",
+		Established:     1950,
+		Rating:          4.2,
+		EditorialRating: 4.1,
+		Fees:            models.FeesRange{Min: 1000, Max: 5000, Currency: "EGP", Period: "annual"},
+		Faculties:       []string{"الطب", "الهندسة"},
+		FacultiesEn:     []string{"Medicine", "Engineering"},
+		Specialties:     []string{"طب الأسنان"},
+		Languages:       []string{"arabic", "english"},
+		Costs:           &models.CostModel{AnnualIncrease: 10, RegistrationFee: 500, Housing: 6000},
+		Description:     "وصف",
+		DescriptionEn:   "Description",
+		MinGrade:        80,
+		MaxGrade:        99,
+		Students:        1000,
+		Admission: []models.AdmissionRule{
+			{Certificate: models.CertificateThanawiya, MinPercentage: 80},
+			{Certificate: models.CertificateIB, MinScore: 28, ScoreScale: 45},
+		},
+		DetailedFaculties: map[string]models.Faculty{
+			"الطب": {
+				NameEn:     "Faculty of Medicine",
+				AnnualFees: models.FeesRange{Min: 3000, Max: 5000},
+				Departments: []models.Department{
+					{Name: "طب الأسنان", NameEn: "Dentistry", Fees: 4500, Degrees: []string{"بكالوريوس"}},
+				},
+				Specializations: []models.Specialization{
+					{Name: "الجراحة العامة", NameEn: "General Surgery", Fees: 8000, Currency: "USD", Period: "semester"},
+				},
+				Admission: []models.AdmissionRule{
+					{Certificate: models.CertificateThanawiya, MinPercentage: 95},
+				},
+			},
+		},
+	}
+}
+
+func mustPut(t *testing.T, s data.Store, uni models.University) {
+	t.Helper()
+	if err := s.PutUniversity(uni); err != nil {
+		t.Fatalf("PutUniversity(%s): %v", uni.ID, err)
+	}
+}
+
+func mustList(t *testing.T, s data.Store) []models.University {
+	t.Helper()
+	unis, err := s.ListUniversities()
+	if err != nil {
+		t.Fatalf("ListUniversities: %v", err)
+	}
+	return unis
+}
+
+func mustGet(t *testing.T, s data.Store, id string) (models.University, bool) {
+	t.Helper()
+	uni, found, err := s.GetUniversity(id)
+	if err != nil {
+		t.Fatalf("GetUniversity(%s): %v", id, err)
+	}
+	return uni, found
+}
+
+func ids(unis []models.University) []string {
+	result := make([]string, len(unis))
+	for i, uni := range unis {
+		result[i] = uni.ID
+	}
+	return result
+}
+
+func testEmptyStore(t *testing.T, s data.Store) {
+	if unis := mustList(t, s); len(unis) != 0 {
+		t.Fatalf("new store has %d universities, want 0", len(unis))
+	}
+	if _, found := mustGet(t, s, "missing"); found {
+		t.Fatal("GetUniversity found a university in an empty store")
+	}
+}
+
+func testPutAndGet(t *testing.T, s data.Store) {
+	want := sampleUniversity("1")
+	mustPut(t, s, want)
+
+	got, found := mustGet(t, s, "1")
+	if !found {
+		t.Fatal("GetUniversity did not find the stored university")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func testListOrder(t *testing.T, s data.Store) {
+	for _, id := range []string{"3", "1", "2"} {
+		mustPut(t, s, sampleUniversity(id))
+	}
+
+	got := ids(mustList(t, s))
+	if want := []string{"3", "1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("list order = %v, want %v", got, want)
+	}
+}
+
+func testPutReplaces(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+
+	updated := sampleUniversity("1")
+	updated.NameEn = "Renamed University"
+	updated.Faculties = nil
+	updated.DetailedFaculties = nil
+	mustPut(t, s, updated)
+
+	unis := mustList(t, s)
+	if got, want := ids(unis), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after replace list = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(unis[0], updated) {
+		t.Fatalf("replaced university mismatch:\n got  %+v\n want %+v", unis[0], updated)
+	}
+}
+
+func testCreate(t *testing.T, s data.Store) {
+	if err := s.CreateUniversity(sampleUniversity("1")); err != nil {
+		t.Fatalf("CreateUniversity: %v", err)
+	}
+	mustPut(t, s, sampleUniversity("2"))
+
+	duplicate := sampleUniversity("1")
+	duplicate.NameEn = "Duplicate University"
+	if err := s.CreateUniversity(duplicate); !errors.Is(err, data.ErrUniversityExists) {
+		t.Fatalf("CreateUniversity with a taken ID: err = %v, want ErrUniversityExists", err)
+	}
+	if err := s.CreateUniversity(sampleUniversity("2")); !errors.Is(err, data.ErrUniversityExists) {
+		t.Fatalf("CreateUniversity over a put university: err = %v, want ErrUniversityExists", err)
+	}
+
+	got, _ := mustGet(t, s, "1")
+	if !reflect.DeepEqual(got, sampleUniversity("1")) {
+		t.Fatalf("failed create changed the stored university:\n got  %+v\n want %+v", got, sampleUniversity("1"))
+	}
+	if got, want := ids(mustList(t, s)), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after create list = %v, want %v", got, want)
+	}
+}
+
+func testSetRatings(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	// An editor's change made after the ratings were computed
+	edited := sampleUniversity("1")
+	edited.NameEn = "Renamed University"
+	mustPut(t, s, edited)
+
+	if err := s.SetRatings(map[string]float64{"1": 3.1, "missing": 2}); err != nil {
+		t.Fatalf("SetRatings: %v", err)
+	}
+	want := edited
+	want.Rating = 3.1
+	if got, _ := mustGet(t, s, "1"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after SetRatings:\n got  %+v\n want %+v", got, want)
+	}
+	if got, _ := mustGet(t, s, "2"); !reflect.DeepEqual(got, sampleUniversity("2")) {
+		t.Fatalf("SetRatings changed a university it was not given:\n got  %+v", got)
+	}
+	if got, want := ids(mustList(t, s)), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after SetRatings list = %v, want %v", got, want)
+	}
+}
+
+func testDelete(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+
+	deleted, err := s.DeleteUniversity("1")
+	if err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	if !deleted {
+		t.Fatal("DeleteUniversity reported a missing university")
+	}
+	if _, found := mustGet(t, s, "1"); found {
+		t.Fatal("deleted university is still returned")
+	}
+
+	deleted, err = s.DeleteUniversity("1")
+	if err != nil {
+		t.Fatalf("second DeleteUniversity: %v", err)
+	}
+	if deleted {
+		t.Fatal("deleting twice reported success")
+	}
+	if got, want := ids(mustList(t, s)), []string{"2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete list = %v, want %v", got, want)
+	}
+}
+
+func testReturnsCopies(t *testing.T, s data.Store) {
+	original := sampleUniversity("1")
+	mustPut(t, s, original)
+
+	got, _ := mustGet(t, s, "1")
+	got.Faculties[0] = "mutated"
+	got.DetailedFaculties["الطب"].Departments[0].Degrees[0] = "mutated"
+
+	again, _ := mustGet(t, s, "1")
+	if !reflect.DeepEqual(again, sampleUniversity("1")) {
+		t.Fatal("mutating a returned university changed the stored copy")
+	}
+}
+
+func testSeed(t *testing.T, s data.Store) {
+	seed := []models.University{sampleUniversity("1"), sampleUniversity("2")}
+	if err := data.Seed(s, seed); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	if got, want := ids(mustList(t, s)), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after seed list = %v, want %v", got, want)
+	}
+
+	// Seeding a populated store leaves it untouched
+	if err := data.Seed(s, []models.University{sampleUniversity("3")}); err != nil {
+		t.Fatalf("second Seed: %v", err)
+	}
+	if got := ids(mustList(t, s)); len(got) != 2 {
+		t.Fatalf("seeding a populated store changed it: %v", got)
+	}
+}
+
+func sampleCutoff(universityID, facultyID, track string, year int, percentage float64) models.Cutoff {
+	return models.Cutoff{
+		UniversityID: universityID,
+		FacultyID:    facultyID,
+		Faculty:      "كلية " + facultyID,
+		FacultyEn:    facultyID,
+		Track:        track,
+		Year:         year,
+		Percentage:   percentage,
+	}
+}
+
+func mustListCutoffs(t *testing.T, s data.Store) []models.Cutoff {
+	t.Helper()
+	cutoffs, err := s.ListCutoffs()
+	if err != nil {
+		t.Fatalf("ListCutoffs: %v", err)
+	}
+	return cutoffs
+}
+
+func testCutoffs(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("2"))
+	mustPut(t, s, sampleUniversity("1"))
+
+	if cutoffs := mustListCutoffs(t, s); len(cutoffs) != 0 {
+		t.Fatalf("new store has %d cut-offs, want 0", len(cutoffs))
+	}
+
+	female := sampleCutoff("1", "medicine", "science", 2024, 96)
+	female.Gender = models.GenderFemale
+	err := s.PutCutoffs([]models.Cutoff{
+		sampleCutoff("1", "medicine", "science", 2024, 95),
+		female,
+		sampleCutoff("1", "medicine", "science", 2023, 94.5),
+		sampleCutoff("2", "engineering", "math", 2024, 88),
+		sampleCutoff("1", "commerce", "literary", 2024, 70),
+	})
+	if err != nil {
+		t.Fatalf("PutCutoffs: %v", err)
+	}
+
+	// Replacing a cut-off keeps one record per key; gender is part of the key
+	updated := sampleCutoff("1", "medicine", "science", 2024, 95.5)
+	updated.Score, updated.MaxScore = 391.55, 410
+	if err := s.PutCutoffs([]models.Cutoff{updated}); err != nil {
+		t.Fatalf("PutCutoffs replace: %v", err)
+	}
+
+	want := []models.Cutoff{
+		sampleCutoff("2", "engineering", "math", 2024, 88),
+		sampleCutoff("1", "commerce", "literary", 2024, 70),
+		sampleCutoff("1", "medicine", "science", 2023, 94.5),
+		updated,
+		female,
+	}
+	if got := mustListCutoffs(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("cut-offs mismatch:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func testDeleteRemovesCutoffs(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	err := s.PutCutoffs([]models.Cutoff{
+		sampleCutoff("1", "medicine", "science", 2024, 95),
+		sampleCutoff("2", "medicine", "science", 2024, 93),
+	})
+	if err != nil {
+		t.Fatalf("PutCutoffs: %v", err)
+	}
+
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	want := []models.Cutoff{sampleCutoff("2", "medicine", "science", 2024, 93)}
+	if got := mustListCutoffs(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete cut-offs = %+v, want %+v", got, want)
+	}
+}
+
+func testSeedCutoffs(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+
+	seed := []models.Cutoff{sampleCutoff("1", "medicine", "science", 2024, 95)}
+	if err := data.SeedCutoffs(s, seed); err != nil {
+		t.Fatalf("SeedCutoffs: %v", err)
+	}
+	if got := mustListCutoffs(t, s); !reflect.DeepEqual(got, seed) {
+		t.Fatalf("after seed cut-offs = %+v, want %+v", got, seed)
+	}
+
+	// Seeding a store that has cut-offs leaves it untouched
+	if err := data.SeedCutoffs(s, []models.Cutoff{sampleCutoff("1", "medicine", "science", 2023, 94)}); err != nil {
+		t.Fatalf("second SeedCutoffs: %v", err)
+	}
+	if got := mustListCutoffs(t, s); len(got) != 1 {
+		t.Fatalf("seeding a store with cut-offs changed it: %+v", got)
+	}
+}
+
+func sampleDiscipline(id string, aliases ...string) models.Discipline {
+	return models.Discipline{
+		ID:            id,
+		Name:          "كلية " + id,
+		NameEn:        "Faculty " + id,
+		Description:   "وصف " + id,
+		DescriptionEn: "About " + id,
+		Field:         "sciences",
+		Aliases:       aliases,
+	}
+}
+
+func mustListDisciplines(t *testing.T, s data.Store) []models.Discipline {
+	t.Helper()
+	disciplines, err := s.ListDisciplines()
+	if err != nil {
+		t.Fatalf("ListDisciplines: %v", err)
+	}
+	return disciplines
+}
+
+func testDisciplines(t *testing.T, s data.Store) {
+	if disciplines := mustListDisciplines(t, s); len(disciplines) != 0 {
+		t.Fatalf("new store has %d disciplines, want 0", len(disciplines))
+	}
+
+	err := s.PutDisciplines([]models.Discipline{
+		sampleDiscipline("medicine", "Human Medicine", "الطب البشري"),
+		sampleDiscipline("law"),
+	})
+	if err != nil {
+		t.Fatalf("PutDisciplines: %v", err)
+	}
+
+	// Replacing a discipline keeps its position
+	updated := sampleDiscipline("medicine", "Medicine and Surgery")
+	updated.Field = "medical"
+	if err := s.PutDisciplines([]models.Discipline{updated, sampleDiscipline("arts")}); err != nil {
+		t.Fatalf("PutDisciplines replace: %v", err)
+	}
+
+	want := []models.Discipline{updated, sampleDiscipline("law"), sampleDiscipline("arts")}
+	got := mustListDisciplines(t, s)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("disciplines mismatch:\n got  %+v\n want %+v", got, want)
+	}
+
+	// Mutating a returned discipline must not change the store
+	got[0].Aliases[0] = "changed"
+	if again := mustListDisciplines(t, s); again[0].Aliases[0] != "Medicine and Surgery" {
+		t.Fatal("store shares alias slices with callers")
+	}
+}
+
+func testSeedDisciplines(t *testing.T, s data.Store) {
+	seed := []models.Discipline{sampleDiscipline("medicine")}
+	if err := data.SeedDisciplines(s, seed); err != nil {
+		t.Fatalf("SeedDisciplines: %v", err)
+	}
+	if got := mustListDisciplines(t, s); !reflect.DeepEqual(got, seed) {
+		t.Fatalf("after seed disciplines = %+v, want %+v", got, seed)
+	}
+
+	// Seeding a store with a catalogue leaves it untouched
+	if err := data.SeedDisciplines(s, []models.Discipline{sampleDiscipline("law")}); err != nil {
+		t.Fatalf("second SeedDisciplines: %v", err)
+	}
+	if got := mustListDisciplines(t, s); len(got) != 1 {
+		t.Fatalf("seeding a store with disciplines changed it: %+v", got)
+	}
+}
+
+func sampleScholarship(id string, universityIDs ...string) models.Scholarship {
+	return models.Scholarship{
+		ID:              id,
+		Name:            "منحة " + id,
+		NameEn:          "Scholarship " + id,
+		Provider:        "جهة " + id,
+		ProviderEn:      "Provider " + id,
+		Type:            models.ScholarshipMerit,
+		UniversityIDs:   universityIDs,
+		MinGrade:        90,
+		DiscountPercent: 25,
+		Deadline:        "2030-08-31",
+		Documents:       []string{"شهادة الثانوية العامة"},
+		DocumentsEn:     []string{"Secondary school certificate"},
+	}
+}
+
+func mustListScholarships(t *testing.T, s data.Store) []models.Scholarship {
+	t.Helper()
+	scholarships, err := s.ListScholarships()
+	if err != nil {
+		t.Fatalf("ListScholarships: %v", err)
+	}
+	return scholarships
+}
+
+func testScholarships(t *testing.T, s data.Store) {
+	if scholarships := mustListScholarships(t, s); len(scholarships) != 0 {
+		t.Fatalf("new store has %d scholarships, want 0", len(scholarships))
+	}
+
+	amount := sampleScholarship("need-aid", "1")
+	amount.Type, amount.MinGrade = models.ScholarshipNeed, 0
+	amount.DiscountPercent, amount.DiscountAmount, amount.Currency = 0, 500, models.CurrencyUSD
+	amount.Documents, amount.DocumentsEn = nil, nil
+	if err := s.PutScholarships([]models.Scholarship{sampleScholarship("merit", "1", "2"), amount}); err != nil {
+		t.Fatalf("PutScholarships: %v", err)
+	}
+
+	// Replacing a scholarship keeps its position
+	updated := sampleScholarship("merit", "1")
+	updated.Faculties = []string{"medicine", "engineering"}
+	if err := s.PutScholarships([]models.Scholarship{updated, sampleScholarship("sports", "3")}); err != nil {
+		t.Fatalf("PutScholarships replace: %v", err)
+	}
+
+	want := []models.Scholarship{updated, amount, sampleScholarship("sports", "3")}
+	got := mustListScholarships(t, s)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("scholarships mismatch:\n got  %+v\n want %+v", got, want)
+	}
+
+	// Mutating a returned scholarship must not change the store
+	got[0].Faculties[0] = "changed"
+	if again := mustListScholarships(t, s); again[0].Faculties[0] != "medicine" {
+		t.Fatal("store shares faculty slices with callers")
+	}
+}
+
+func testSeedScholarships(t *testing.T, s data.Store) {
+	seed := []models.Scholarship{sampleScholarship("merit", "1")}
+	if err := data.SeedScholarships(s, seed); err != nil {
+		t.Fatalf("SeedScholarships: %v", err)
+	}
+	if got := mustListScholarships(t, s); !reflect.DeepEqual(got, seed) {
+		t.Fatalf("after seed scholarships = %+v, want %+v", got, seed)
+	}
+
+	// Seeding a store with scholarships leaves it untouched
+	if err := data.SeedScholarships(s, []models.Scholarship{sampleScholarship("sports", "2")}); err != nil {
+		t.Fatalf("second SeedScholarships: %v", err)
+	}
+	if got := mustListScholarships(t, s); len(got) != 1 {
+		t.Fatalf("seeding a store with scholarships changed it: %+v", got)
+	}
+}
+
+func sampleEvent(id, universityID, start string) models.AdmissionEvent {
+	return models.AdmissionEvent{
+		ID:           id,
+		UniversityID: universityID,
+		Kind:         models.EventApplicationOpen,
+		Title:        "موعد " + id,
+		TitleEn:      "Event " + id,
+		Start:        start,
+	}
+}
+
+func mustListEvents(t *testing.T, s data.Store) []models.AdmissionEvent {
+	t.Helper()
+	events, err := s.ListEvents()
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	return events
+}
+
+func testEvents(t *testing.T, s data.Store) {
+	if events := mustListEvents(t, s); len(events) != 0 {
+		t.Fatalf("new store has %d events, want 0", len(events))
+	}
+	mustPut(t, s, sampleUniversity("1"))
+
+	// Nationwide events have no university
+	tansik := sampleEvent("tansik-phase-1", "", "2027-07-20")
+	tansik.Kind, tansik.End = models.EventTansikPhase, "2027-07-27"
+	if err := s.PutEvents([]models.AdmissionEvent{sampleEvent("open", "1", "2027-02-01"), tansik}); err != nil {
+		t.Fatalf("PutEvents: %v", err)
+	}
+
+	// Replacing an event keeps its position
+	updated := sampleEvent("open", "1", "2027-02-15")
+	updated.Faculty, updated.URL = "medicine", "https://example.edu/apply"
+	updated.Description, updated.DescriptionEn = "التقديم إلكترونيا", "Apply online"
+	updated.Location, updated.LocationEn = "الحرم الرئيسي", "Main campus"
+	if err := s.PutEvents([]models.AdmissionEvent{updated, sampleEvent("test", "1", "2027-03-01")}); err != nil {
+		t.Fatalf("PutEvents replace: %v", err)
+	}
+
+	want := []models.AdmissionEvent{updated, tansik, sampleEvent("test", "1", "2027-03-01")}
+	if got := mustListEvents(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events mismatch:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func testDeleteEvent(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	err := s.PutEvents([]models.AdmissionEvent{sampleEvent("one", "1", "2027-02-01"), sampleEvent("two", "1", "2027-03-01")})
+	if err != nil {
+		t.Fatalf("PutEvents: %v", err)
+	}
+
+	if deleted, err := s.DeleteEvent("one"); err != nil || !deleted {
+		t.Fatalf("DeleteEvent = %v, %v, want true", deleted, err)
+	}
+	if deleted, err := s.DeleteEvent("one"); err != nil || deleted {
+		t.Fatalf("DeleteEvent again = %v, %v, want false", deleted, err)
+	}
+	want := []models.AdmissionEvent{sampleEvent("two", "1", "2027-03-01")}
+	if got := mustListEvents(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete events = %+v, want %+v", got, want)
+	}
+}
+
+func testDeleteRemovesEvents(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	err := s.PutEvents([]models.AdmissionEvent{
+		sampleEvent("one", "1", "2027-02-01"),
+		sampleEvent("two", "2", "2027-02-01"),
+		sampleEvent("nationwide", "", "2027-07-20"),
+	})
+	if err != nil {
+		t.Fatalf("PutEvents: %v", err)
+	}
+
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	want := []models.AdmissionEvent{sampleEvent("two", "2", "2027-02-01"), sampleEvent("nationwide", "", "2027-07-20")}
+	if got := mustListEvents(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete events = %+v, want %+v", got, want)
+	}
+}
+
+func testSeedEvents(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+
+	seed := []models.AdmissionEvent{sampleEvent("open", "1", "2027-02-01")}
+	if err := data.SeedEvents(s, seed); err != nil {
+		t.Fatalf("SeedEvents: %v", err)
+	}
+	if got := mustListEvents(t, s); !reflect.DeepEqual(got, seed) {
+		t.Fatalf("after seed events = %+v, want %+v", got, seed)
+	}
+
+	// Seeding a store with a calendar leaves it untouched
+	if err := data.SeedEvents(s, []models.AdmissionEvent{sampleEvent("close", "1", "2027-03-01")}); err != nil {
+		t.Fatalf("second SeedEvents: %v", err)
+	}
+	if got := mustListEvents(t, s); len(got) != 1 {
+		t.Fatalf("seeding a store with events changed it: %+v", got)
+	}
+}
+
+func sampleUser(id, email string) models.User {
+	return models.User{
+		ID:           id,
+		Email:        email,
+		Name:         "User " + id,
+		Role:         models.RoleStudent,
+		PasswordHash: "$2a$10$hash" + id,
+		CreatedAt:    time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func testUsers(t *testing.T, s data.Store) {
+	if users, err := s.ListUsers(); err != nil || len(users) != 0 {
+		t.Fatalf("new store users = %v, %v; want none", users, err)
+	}
+
+	first, second := sampleUser("a1", "one@example.com"), sampleUser("b2", "two@example.com")
+	for _, u := range []models.User{first, second} {
+		if err := s.CreateUser(u); err != nil {
+			t.Fatalf("CreateUser %s: %v", u.ID, err)
+		}
+	}
+	if err := s.CreateUser(sampleUser("c3", "one@example.com")); !errors.Is(err, data.ErrEmailTaken) {
+		t.Fatalf("CreateUser with a taken email: err = %v, want ErrEmailTaken", err)
+	}
+
+	got, found, err := s.GetUserByEmail("two@example.com")
+	if err != nil || !found || !reflect.DeepEqual(got, second) {
+		t.Fatalf("GetUserByEmail = %+v, %v, %v; want %+v", got, found, err, second)
+	}
+	if _, found, err := s.GetUser("missing"); err != nil || found {
+		t.Fatalf("GetUser(missing) found = %v, err = %v", found, err)
+	}
+
+	if ok, err := s.SetUserRole("a1", models.RoleEditor); err != nil || !ok {
+		t.Fatalf("SetUserRole = %v, %v", ok, err)
+	}
+	if ok, err := s.SetUserRole("missing", models.RoleEditor); err != nil || ok {
+		t.Fatalf("SetUserRole(missing) = %v, %v", ok, err)
+	}
+	first.Role = models.RoleEditor
+	if got, _, _ := s.GetUser("a1"); !reflect.DeepEqual(got, first) {
+		t.Fatalf("after SetUserRole user = %+v, want %+v", got, first)
+	}
+
+	users, err := s.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if want := []models.User{first, second}; !reflect.DeepEqual(users, want) {
+		t.Fatalf("users mismatch:\n got  %+v\n want %+v", users, want)
+	}
+}
+
+func testRefreshTokens(t *testing.T, s data.Store) {
+	if err := s.CreateUser(sampleUser("a1", "one@example.com")); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	token := models.RefreshToken{Hash: "abc", UserID: "a1", ExpiresAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	if err := s.PutRefreshToken(token); err != nil {
+		t.Fatalf("PutRefreshToken: %v", err)
+	}
+
+	got, found, err := s.TakeRefreshToken("abc")
+	if err != nil || !found || !reflect.DeepEqual(got, token) {
+		t.Fatalf("TakeRefreshToken = %+v, %v, %v; want %+v", got, found, err, token)
+	}
+	// A token is used once
+	if _, found, err := s.TakeRefreshToken("abc"); err != nil || found {
+		t.Fatalf("second TakeRefreshToken found = %v, err = %v", found, err)
+	}
+}
+
+func sampleFavorite(userID, universityID string) models.Favorite {
+	return models.Favorite{UserID: userID, UniversityID: universityID, CreatedAt: time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func sampleShortlist(id, userID string, universityIDs ...string) models.Shortlist {
+	list := models.Shortlist{
+		ID:        id,
+		UserID:    userID,
+		Name:      "List " + id,
+		Items:     []models.ShortlistItem{},
+		CreatedAt: time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 9, 2, 12, 0, 0, 0, time.UTC),
+	}
+	for _, uid := range universityIDs {
+		list.Items = append(list.Items, models.ShortlistItem{UniversityID: uid, Faculty: "medicine", Note: "note " + uid})
+	}
+	return list
+}
+
+// mustCreateUsers creates accounts with the given IDs for the personal
+// features to refer to
+func mustCreateUsers(t *testing.T, s data.Store, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := s.CreateUser(sampleUser(id, id+"@example.com")); err != nil {
+			t.Fatalf("CreateUser %s: %v", id, err)
+		}
+	}
+}
+
+func testFavorites(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	mustCreateUsers(t, s, "a1", "b2")
+
+	for _, fav := range []models.Favorite{sampleFavorite("a1", "2"), sampleFavorite("a1", "1"), sampleFavorite("b2", "1")} {
+		if added, err := s.AddFavorite(fav); err != nil || !added {
+			t.Fatalf("AddFavorite %+v = %v, %v", fav, added, err)
+		}
+	}
+	if added, err := s.AddFavorite(sampleFavorite("a1", "2")); err != nil || added {
+		t.Fatalf("AddFavorite of an existing favorite = %v, %v; want false", added, err)
+	}
+
+	got, err := s.ListFavorites("a1")
+	if err != nil {
+		t.Fatalf("ListFavorites: %v", err)
+	}
+	if want := []models.Favorite{sampleFavorite("a1", "2"), sampleFavorite("a1", "1")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("favorites mismatch:\n got  %+v\n want %+v", got, want)
+	}
+
+	if removed, err := s.RemoveFavorite("a1", "2"); err != nil || !removed {
+		t.Fatalf("RemoveFavorite = %v, %v", removed, err)
+	}
+	if removed, err := s.RemoveFavorite("a1", "2"); err != nil || removed {
+		t.Fatalf("second RemoveFavorite = %v, %v", removed, err)
+	}
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	for _, user := range []string{"a1", "b2"} {
+		if got, err := s.ListFavorites(user); err != nil || len(got) != 0 {
+			t.Fatalf("favorites of %s after delete = %+v, %v; want none", user, got, err)
+		}
+	}
+}
+
+func testShortlists(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	mustCreateUsers(t, s, "a1", "b2")
+
+	plan, budget := sampleShortlist("plan", "a1", "2", "1"), sampleShortlist("budget", "a1")
+	for _, list := range []models.Shortlist{plan, budget, sampleShortlist("other", "b2", "1")} {
+		if err := s.PutShortlist(list); err != nil {
+			t.Fatalf("PutShortlist %s: %v", list.ID, err)
+		}
+	}
+
+	got, found, err := s.GetShortlist("a1", "plan")
+	if err != nil || !found || !reflect.DeepEqual(got, plan) {
+		t.Fatalf("GetShortlist = %+v, %v, %v; want %+v", got, found, err, plan)
+	}
+	// Shortlists are private to their user
+	if _, found, err := s.GetShortlist("b2", "plan"); err != nil || found {
+		t.Fatalf("GetShortlist of another user found = %v, err = %v", found, err)
+	}
+	if err := s.PutShortlist(sampleShortlist("plan", "b2")); err == nil {
+		t.Fatal("PutShortlist over another user's shortlist succeeded")
+	}
+
+	plan.Name = "Plan A"
+	plan.Items = plan.Items[1:]
+	plan.UpdatedAt = plan.UpdatedAt.Add(time.Hour)
+	if err := s.PutShortlist(plan); err != nil {
+		t.Fatalf("PutShortlist replacing: %v", err)
+	}
+	lists, err := s.ListShortlists("a1")
+	if err != nil {
+		t.Fatalf("ListShortlists: %v", err)
+	}
+	if want := []models.Shortlist{plan, budget}; !reflect.DeepEqual(lists, want) {
+		t.Fatalf("shortlists mismatch:\n got  %+v\n want %+v", lists, want)
+	}
+
+	if deleted, err := s.DeleteShortlist("b2", "plan"); err != nil || deleted {
+		t.Fatalf("DeleteShortlist of another user = %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteShortlist("a1", "plan"); err != nil || !deleted {
+		t.Fatalf("DeleteShortlist = %v, %v", deleted, err)
+	}
+	if lists, _ := s.ListShortlists("a1"); !reflect.DeepEqual(lists, []models.Shortlist{budget}) {
+		t.Fatalf("after delete shortlists = %+v, want %+v", lists, budget)
+	}
+}
+
+func testDeleteRemovesShortlistItems(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	mustCreateUsers(t, s, "a1")
+	if err := s.PutShortlist(sampleShortlist("plan", "a1", "1", "2", "1")); err != nil {
+		t.Fatalf("PutShortlist: %v", err)
+	}
+
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	want := sampleShortlist("plan", "a1", "2")
+	if got, _, err := s.GetShortlist("a1", "plan"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete shortlist = %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func sampleSavedSearch(id, userID string, notify bool) models.SavedSearch {
+	fees := 50000
+	created := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	return models.SavedSearch{
+		ID:       id,
+		UserID:   userID,
+		Name:     "Search " + id,
+		Params:   models.SearchParams{SearchQuery: "engineering", SelectedRegion: "alexandria", FilterByFees: &fees},
+		Currency: "EGP",
+		Notify:   notify,
+		Matches: []models.SearchMatch{
+			{UniversityID: "1", Name: "جامعة", NameEn: "University 1", Fees: models.FeesRange{Min: 1000, Max: 2000, Currency: "EGP"}},
+		},
+		CreatedAt: created,
+		UpdatedAt: created,
+		CheckedAt: created,
+	}
+}
+
+func testSavedSearches(t *testing.T, s data.Store) {
+	mustCreateUsers(t, s, "a1", "b2")
+
+	first, second, other := sampleSavedSearch("s1", "a1", true), sampleSavedSearch("s2", "a1", false), sampleSavedSearch("s3", "b2", true)
+	for _, search := range []models.SavedSearch{first, second, other} {
+		if err := s.PutSavedSearch(search); err != nil {
+			t.Fatalf("PutSavedSearch %s: %v", search.ID, err)
+		}
+	}
+
+	got, found, err := s.GetSavedSearch("a1", "s1")
+	if err != nil || !found || !reflect.DeepEqual(got, first) {
+		t.Fatalf("GetSavedSearch = %+v, %v, %v; want %+v", got, found, err, first)
+	}
+	if _, found, err := s.GetSavedSearch("b2", "s1"); err != nil || found {
+		t.Fatalf("GetSavedSearch of another user found = %v, err = %v", found, err)
+	}
+	if err := s.PutSavedSearch(sampleSavedSearch("s1", "b2", true)); err == nil {
+		t.Fatal("PutSavedSearch over another user's search succeeded")
+	}
+
+	second.Name, second.Notify = "Renamed", true
+	if err := s.PutSavedSearch(second); err != nil {
+		t.Fatalf("PutSavedSearch replacing: %v", err)
+	}
+	searches, err := s.ListSavedSearches("a1")
+	if err != nil {
+		t.Fatalf("ListSavedSearches: %v", err)
+	}
+	if want := []models.SavedSearch{first, second}; !reflect.DeepEqual(searches, want) {
+		t.Fatalf("saved searches mismatch:\n got  %+v\n want %+v", searches, want)
+	}
+
+	checked := first.CheckedAt.Add(24 * time.Hour)
+	first.Matches, first.CheckedAt = []models.SearchMatch{}, checked
+	if ok, err := s.SetSearchMatches("s1", first.Matches, checked); err != nil || !ok {
+		t.Fatalf("SetSearchMatches = %v, %v", ok, err)
+	}
+	if ok, err := s.SetSearchMatches("missing", nil, checked); err != nil || ok {
+		t.Fatalf("SetSearchMatches(missing) = %v, %v", ok, err)
+	}
+
+	if deleted, err := s.DeleteSavedSearch("b2", "s3"); err != nil || !deleted {
+		t.Fatalf("DeleteSavedSearch = %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteSavedSearch("b2", "s1"); err != nil || deleted {
+		t.Fatalf("DeleteSavedSearch of another user = %v, %v", deleted, err)
+	}
+	watched, err := s.ListWatchedSearches()
+	if err != nil {
+		t.Fatalf("ListWatchedSearches: %v", err)
+	}
+	if want := []models.SavedSearch{first, second}; !reflect.DeepEqual(watched, want) {
+		t.Fatalf("watched searches mismatch:\n got  %+v\n want %+v", watched, want)
+	}
+}
+
+func sampleReview(id, userID, universityID, status string) models.Review {
+	return models.Review{
+		ID:           id,
+		UserID:       userID,
+		UniversityID: universityID,
+		Faculty:      "medicine",
+		Overall:      4,
+		Ratings:      models.SubRatings{Teaching: 5, Facilities: 3, CareerSupport: 4},
+		Title:        "Review " + id,
+		Body:         "Demanding but well taught courses.",
+		Status:       status,
+		Flags:        []models.ReviewFlag{{Kind: models.FlagPersonalData, Detail: "phone number"}},
+		CreatedAt:    time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2026, 9, 2, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func testReviews(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	mustCreateUsers(t, s, "a1", "b2")
+
+	first := sampleReview("r1", "a1", "1", models.ReviewPending)
+	second := sampleReview("r2", "b2", "1", models.ReviewApproved)
+	other := sampleReview("r3", "a1", "2", models.ReviewApproved)
+	for _, r := range []models.Review{first, second, other} {
+		if err := s.PutReview(r); err != nil {
+			t.Fatalf("PutReview %s: %v", r.ID, err)
+		}
+	}
+
+	got, found, err := s.GetReview("r1")
+	if err != nil || !found || !reflect.DeepEqual(got, first) {
+		t.Fatalf("GetReview = %+v, %v, %v; want %+v", got, found, err, first)
+	}
+	if _, found, err := s.GetReview("missing"); err != nil || found {
+		t.Fatalf("GetReview(missing) found = %v, err = %v", found, err)
+	}
+
+	first.Status, first.StatusReason, first.Overall, first.Flags = models.ReviewApproved, "Fair criticism", 2, nil
+	if err := s.PutReview(first); err != nil {
+		t.Fatalf("PutReview replacing: %v", err)
+	}
+	for _, tc := range []struct {
+		filter data.ReviewFilter
+		want   []models.Review
+	}{
+		{data.ReviewFilter{}, []models.Review{first, second, other}},
+		{data.ReviewFilter{UniversityID: "1"}, []models.Review{first, second}},
+		{data.ReviewFilter{UserID: "a1"}, []models.Review{first, other}},
+		{data.ReviewFilter{UniversityID: "2", Status: models.ReviewApproved}, []models.Review{other}},
+		{data.ReviewFilter{Status: models.ReviewRejected}, nil},
+	} {
+		reviews, err := s.ListReviews(tc.filter)
+		if err != nil {
+			t.Fatalf("ListReviews(%+v): %v", tc.filter, err)
+		}
+		if !reflect.DeepEqual(reviews, tc.want) {
+			t.Fatalf("ListReviews(%+v) mismatch:\n got  %+v\n want %+v", tc.filter, reviews, tc.want)
+		}
+	}
+
+	if deleted, err := s.DeleteReview("r2"); err != nil || !deleted {
+		t.Fatalf("DeleteReview = %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteReview("r2"); err != nil || deleted {
+		t.Fatalf("second DeleteReview = %v, %v", deleted, err)
+	}
+}
+
+func testDeleteRemovesReviews(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustPut(t, s, sampleUniversity("2"))
+	mustCreateUsers(t, s, "a1")
+	kept := sampleReview("r2", "a1", "2", models.ReviewApproved)
+	for _, r := range []models.Review{sampleReview("r1", "a1", "1", models.ReviewApproved), kept} {
+		if err := s.PutReview(r); err != nil {
+			t.Fatalf("PutReview %s: %v", r.ID, err)
+		}
+	}
+
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	reviews, err := s.ListReviews(data.ReviewFilter{})
+	if err != nil {
+		t.Fatalf("ListReviews: %v", err)
+	}
+	if want := []models.Review{kept}; !reflect.DeepEqual(reviews, want) {
+		t.Fatalf("reviews after delete = %+v, want %+v", reviews, want)
+	}
+}
+
+func testModerationActions(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustCreateUsers(t, s, "a1", "m1", "m2")
+	if err := s.PutReview(sampleReview("r1", "a1", "1", models.ReviewPending)); err != nil {
+		t.Fatalf("PutReview: %v", err)
+	}
+
+	at := time.Date(2026, 9, 3, 12, 0, 0, 0, time.UTC)
+	first := models.ModerationAction{
+		ID: "x1", ReviewID: "r1", UniversityID: "1", ModeratorID: "m1", Action: models.ModerationReject,
+		PreviousStatus: models.ReviewPending, Status: models.ReviewRejected, Reason: "Contains a phone number",
+		Flags: []models.ReviewFlag{{Kind: models.FlagPersonalData, Detail: "phone number"}}, CreatedAt: at,
+	}
+	second := models.ModerationAction{
+		ID: "x2", ReviewID: "r1", UniversityID: "1", ModeratorID: "m2", Action: models.ModerationApprove,
+		PreviousStatus: models.ReviewRejected, Status: models.ReviewApproved, CreatedAt: at.Add(time.Hour),
+	}
+	other := models.ModerationAction{
+		ID: "x3", ReviewID: "r2", UniversityID: "1", ModeratorID: "m1", Action: models.ModerationApprove,
+		PreviousStatus: models.ReviewPending, Status: models.ReviewApproved, CreatedAt: at.Add(2 * time.Hour),
+	}
+	for _, a := range []models.ModerationAction{first, second, other} {
+		if err := s.AddModerationAction(a); err != nil {
+			t.Fatalf("AddModerationAction %s: %v", a.ID, err)
+		}
+	}
+
+	// The trail outlives the review and its university
+	if _, err := s.DeleteUniversity("1"); err != nil {
+		t.Fatalf("DeleteUniversity: %v", err)
+	}
+	for _, tc := range []struct {
+		filter data.ModerationFilter
+		want   []models.ModerationAction
+	}{
+		{data.ModerationFilter{}, []models.ModerationAction{first, second, other}},
+		{data.ModerationFilter{ReviewID: "r1"}, []models.ModerationAction{first, second}},
+		{data.ModerationFilter{ModeratorID: "m1"}, []models.ModerationAction{first, other}},
+		{data.ModerationFilter{ReviewID: "r2", ModeratorID: "m2"}, nil},
+	} {
+		actions, err := s.ListModerationActions(tc.filter)
+		if err != nil {
+			t.Fatalf("ListModerationActions(%+v): %v", tc.filter, err)
+		}
+		if !reflect.DeepEqual(actions, tc.want) {
+			t.Fatalf("ListModerationActions(%+v) mismatch:\n got  %+v\n want %+v", tc.filter, actions, tc.want)
+		}
+	}
+}
+
+func testModerateReview(t *testing.T, s data.Store) {
+	mustPut(t, s, sampleUniversity("1"))
+	mustCreateUsers(t, s, "a1", "m1")
+	review := sampleReview("r1", "a1", "1", models.ReviewPending)
+	if err := s.PutReview(review); err != nil {
+		t.Fatalf("PutReview: %v", err)
+	}
+
+	review.Status, review.StatusReason = models.ReviewApproved, "Fair"
+	action := models.ModerationAction{
+		ID: "x1", ReviewID: "r1", UniversityID: "1", ModeratorID: "m1", Action: models.ModerationApprove,
+		PreviousStatus: models.ReviewPending, Status: models.ReviewApproved, Reason: "Fair",
+		Flags: review.Flags, CreatedAt: time.Date(2026, 9, 3, 12, 0, 0, 0, time.UTC),
+	}
+
+	// A decision on a version of the review that is no longer stored changes
+	// nothing
+	edited := review
+	edited.UpdatedAt = edited.UpdatedAt.Add(-time.Hour)
+	moderated := action
+	moderated.PreviousStatus = models.ReviewRejected
+	missing := review
+	missing.ID = "r2"
+	for _, tc := range []struct {
+		name   string
+		review models.Review
+		action models.ModerationAction
+	}{
+		{"edited since", edited, action},
+		{"moderated since", review, moderated},
+		{"deleted", missing, action},
+	} {
+		if err := s.ModerateReview(tc.review, tc.action); !errors.Is(err, data.ErrReviewChanged) {
+			t.Fatalf("ModerateReview of a review %s = %v, want %v", tc.name, err, data.ErrReviewChanged)
+		}
+	}
+	if actions, err := s.ListModerationActions(data.ModerationFilter{}); err != nil || len(actions) != 0 {
+		t.Fatalf("actions after stale decisions = %+v, %v; want none", actions, err)
+	}
+	if _, found, _ := s.GetReview("r2"); found {
+		t.Fatal("ModerateReview recreated a deleted review")
+	}
+
+	if err := s.ModerateReview(review, action); err != nil {
+		t.Fatalf("ModerateReview: %v", err)
+	}
+
+	got, found, err := s.GetReview("r1")
+	if err != nil || !found || !reflect.DeepEqual(got, review) {
+		t.Fatalf("GetReview = %+v, %v, %v; want %+v", got, found, err, review)
+	}
+	actions, err := s.ListModerationActions(data.ModerationFilter{ReviewID: "r1"})
+	if err != nil {
+		t.Fatalf("ListModerationActions: %v", err)
+	}
+	if want := []models.ModerationAction{action}; !reflect.DeepEqual(actions, want) {
+		t.Fatalf("ListModerationActions mismatch:\n got  %+v\n want %+v", actions, want)
+	}
+}
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"roadtouniversities/models"
+)
+
+var (
+	// ErrInvalidGender is returned for an unknown applicant gender
+	ErrInvalidGender = errors.New("invalid gender")
+	// ErrInvalidZone is returned for an unknown geographic zone
+	ErrInvalidZone = errors.New("invalid zone")
+	// ErrInvalidPreferences is returned for an empty or oversized preference
+	// list
+	ErrInvalidPreferences = errors.New("invalid preferences")
+)
+
+// maxPreferences bounds the preference list; the Tansik form has 75 slots
+const maxPreferences = 75
+
+// tansikUniversityType is the only university type placing students through
+// Tansik. Private, national and Al-Azhar universities admit directly.
+const tansikUniversityType = "public"
+
+// SimulateTansik places the student in the first preference whose cut-off
+// they meet, the way Tansik allocates an ordered preference list. Cut-offs
+// come from history, for req.Year or the latest year on record, preferring
+// the student's gender over a cut-off for everyone.
+//
+// Geographic distribution keeps students in public universities of their
+// own zone; a faculty is open to every zone when no public university in the
+// student's zone offers it. Preferences that can never change the placement
+// (out of zone, the wrong track or gender, not in Tansik, repeated, unknown,
+// or after the admitted one) are reported as wasted.
+func SimulateTansik(unis []models.University, faculties *FacultyCatalog, history []models.Cutoff, req models.TansikSimulationRequest) (models.TansikSimulationResponse, error) {
+	var resp models.TansikSimulationResponse
+
+	percentage, err := tansikPercentage(req)
+	if err != nil {
+		return resp, err
+	}
+	if !models.IsValidTrack(req.Track) {
+		return resp, fmt.Errorf("%w: %q", ErrInvalidTrack, req.Track)
+	}
+	if !models.IsValidGender(req.Gender) {
+		return resp, fmt.Errorf("%w: %q", ErrInvalidGender, req.Gender)
+	}
+	if !models.IsValidRegion(req.Zone) {
+		return resp, fmt.Errorf("%w: %q", ErrInvalidZone, req.Zone)
+	}
+	if len(req.Preferences) == 0 || len(req.Preferences) > maxPreferences {
+		return resp, fmt.Errorf("%w: between 1 and %d preferences are required", ErrInvalidPreferences, maxPreferences)
+	}
+	resp.Percentage = round1(percentage)
+
+	byID := make(map[string]models.University, len(unis))
+	zoneFaculties := make(map[string]bool)
+	for _, uni := range unis {
+		byID[uni.ID] = uni
+		if uni.Type == tansikUniversityType && uni.Region == req.Zone {
+			for i, name := range uni.Faculties {
+				nameEn := ""
+				if i < len(uni.FacultiesEn) {
+					nameEn = uni.FacultiesEn[i]
+				}
+				zoneFaculties[faculties.FacultyID(name, nameEn)] = true
+			}
+		}
+	}
+
+	seen := make(map[string]int, len(req.Preferences))
+	admitted := -1
+	for i, pref := range req.Preferences {
+		r := models.TansikPreferenceResult{Rank: i + 1, UniversityID: pref.UniversityID, Wasted: true}
+
+		uni, found := byID[pref.UniversityID]
+		f := -1
+		if found {
+			f = resolveFaculty(uni, faculties, pref.Faculty)
+		}
+		if f < 0 {
+			r.Status = models.PreferenceUnknown
+			r.Reason = fmt.Sprintf("no faculty %q at %s", pref.Faculty, uni.NameEn)
+			if !found {
+				r.Reason = fmt.Sprintf("no university with ID %q", pref.UniversityID)
+			}
+			resp.Preferences = append(resp.Preferences, r)
+			continue
+		}
+		r.University, r.UniversityEn = uni.Name, uni.NameEn
+		r.Faculty = uni.Faculties[f]
+		if f < len(uni.FacultiesEn) {
+			r.FacultyEn = uni.FacultiesEn[f]
+		}
+		r.FacultyID = faculties.FacultyID(r.Faculty, r.FacultyEn)
+
+		key := uni.ID + "\x00" + r.FacultyID
+		cut, cutFound := tansikCutoff(history, uni.ID, r.FacultyID, req.Track, req.Gender, req.Year)
+		switch first, dup := seen[key]; {
+		case dup:
+			r.Status = models.PreferenceDuplicate
+			r.Reason = fmt.Sprintf("repeats preference %d", first)
+		case uni.Type != tansikUniversityType:
+			r.Status = models.PreferenceNotInTansik
+			r.Reason = fmt.Sprintf("%s universities admit directly, not through Tansik", uni.Type)
+		case !containsString(FacultyTracks(r.FacultyID), req.Track):
+			r.Status = models.PreferenceWrongTrack
+			r.Reason = fmt.Sprintf("%s admits the %s track only", r.FacultyEn, strings.Join(FacultyTracks(r.FacultyID), " or "))
+		case uni.Region != req.Zone && zoneFaculties[r.FacultyID]:
+			r.Status = models.PreferenceOutOfZone
+			r.Reason = fmt.Sprintf("%s students are placed in %s within their own zone", req.Zone, r.FacultyEn)
+		case !cutFound && otherGenderOnly(history, uni.ID, r.FacultyID, req.Track, req.Gender):
+			r.Status = models.PreferenceWrongGender
+			r.Reason = fmt.Sprintf("%s admits %s students only", r.FacultyEn, otherGender(req.Gender))
+		case admitted >= 0:
+			r.Status = models.PreferenceNotReached
+			r.Reason = fmt.Sprintf("listed after preference %d, which admits", admitted+1)
+		case !cutFound:
+			r.Status = models.PreferenceNoCutoff
+			r.Wasted = false
+			r.Reason = "no cut-off on record for the " + req.Track + " track; assumed not to admit"
+			if req.Year != 0 {
+				r.Reason = fmt.Sprintf("no %d cut-off on record for the %s track; assumed not to admit", req.Year, req.Track)
+			}
+		default:
+			value, margin := cut.Percentage, round1(percentage-cut.Percentage)
+			r.Cutoff, r.CutoffYear, r.Margin = &value, cut.Year, &margin
+			r.Wasted = false
+			if percentage >= cut.Percentage {
+				r.Status = models.PreferenceAdmitted
+				r.Reason = fmt.Sprintf("%.1f points above the %d cut-off of %.2f%%", margin, cut.Year, cut.Percentage)
+				admitted = i
+			} else {
+				r.Status = models.PreferenceBelowCutoff
+				r.Reason = fmt.Sprintf("%.1f points below the %d cut-off of %.2f%%", -margin, cut.Year, cut.Percentage)
+			}
+		}
+		if _, dup := seen[key]; !dup {
+			seen[key] = i + 1
+		}
+		resp.Preferences = append(resp.Preferences, r)
+	}
+
+	resp.Wasted = []int{}
+	for i := range resp.Preferences {
+		if resp.Preferences[i].Wasted {
+			resp.Wasted = append(resp.Wasted, resp.Preferences[i].Rank)
+		}
+	}
+	if admitted >= 0 {
+		resp.Admitted = &resp.Preferences[admitted]
+	}
+	return resp, nil
+}
+
+// tansikPercentage returns the student's grade as a percentage
+func tansikPercentage(req models.TansikSimulationRequest) (float64, error) {
+	switch {
+	case req.Percentage != nil:
+		if *req.Percentage < 0 || *req.Percentage > 100 {
+			return 0, fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidPercentage)
+		}
+		return *req.Percentage, nil
+	case req.Score != nil:
+		if req.MaxScore <= 0 || *req.Score < 0 || *req.Score > req.MaxScore {
+			return 0, fmt.Errorf("%w: score must be between 0 and a positive maxScore", ErrInvalidPercentage)
+		}
+		return *req.Score / req.MaxScore * 100, nil
+	default:
+		return 0, fmt.Errorf("%w: percentage or score is required", ErrInvalidPercentage)
+	}
+}
+
+// resolveFaculty finds a faculty of uni by ID, Arabic or English name, or
+// returns -1
+func resolveFaculty(uni models.University, faculties *FacultyCatalog, faculty string) int {
+	faculty = strings.TrimSpace(faculty)
+	if faculty == "" {
+		return -1
+	}
+	for i, name := range uni.Faculties {
+		nameEn := ""
+		if i < len(uni.FacultiesEn) {
+			nameEn = uni.FacultiesEn[i]
+		}
+		if faculties.FacultyID(name, nameEn) == faculty {
+			return i
+		}
+	}
+	return findFaculty(uni, faculty, faculty)
+}
+
+// tansikCutoff returns the cut-off applying to the student, from year or the
+// latest year on record. A cut-off for the student's gender wins over one
+// for everyone in the same year.
+func tansikCutoff(history []models.Cutoff, universityID, facultyID, track, gender string, year int) (models.Cutoff, bool) {
+	var best models.Cutoff
+	found := false
+	for _, c := range history {
+		if c.UniversityID != universityID || c.FacultyID != facultyID || c.Track != track ||
+			(c.Gender != "" && c.Gender != gender) || (year != 0 && c.Year != year) {
+			continue
+		}
+		if !found || c.Year > best.Year || (c.Year == best.Year && c.Gender != "") {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// otherGenderOnly reports whether a faculty's cut-offs for track are all for
+// the gender other than gender
+func otherGenderOnly(history []models.Cutoff, universityID, facultyID, track, gender string) bool {
+	found := false
+	for _, c := range history {
+		if c.UniversityID != universityID || c.FacultyID != facultyID || c.Track != track {
+			continue
+		}
+		if c.Gender == "" || c.Gender == gender {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func otherGender(gender string) string {
+	if gender == models.GenderMale {
+		return models.GenderFemale
+	}
+	return models.GenderMale
+}
@@ -0,0 +1,197 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func tansikCatalogue() []models.University {
+	return []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo",
+			Faculties: []string{"الطب", "الهندسة", "التجارة"}, FacultiesEn: []string{"Medicine", "Engineering", "Commerce"}},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "public", Region: "alexandria",
+			Faculties: []string{"الطب", "العلوم"}, FacultiesEn: []string{"Medicine", "Science"}},
+		{ID: "3", Name: "جامعة ج", NameEn: "University C", Type: "private", Region: "cairo",
+			Faculties: []string{"الطب"}, FacultiesEn: []string{"Medicine"}},
+		{ID: "4", Name: "جامعة د", NameEn: "University D", Type: "public", Region: "cairo",
+			Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"}},
+	}
+}
+
+func tansikHistory() []models.Cutoff {
+	return []models.Cutoff{
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Year: 2023, Percentage: 95},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Year: 2024, Percentage: 96},
+		{UniversityID: "1", FacultyID: "medicine", Track: models.TrackScience, Gender: models.GenderFemale, Year: 2024, Percentage: 97},
+		{UniversityID: "1", FacultyID: "engineering", Track: models.TrackMath, Year: 2024, Percentage: 90},
+		{UniversityID: "1", FacultyID: "commerce", Track: models.TrackScience, Year: 2024, Percentage: 70},
+		{UniversityID: "2", FacultyID: "medicine", Track: models.TrackScience, Year: 2024, Percentage: 93},
+		{UniversityID: "2", FacultyID: "science", Track: models.TrackScience, Year: 2024, Percentage: 80},
+		{UniversityID: "4", FacultyID: "engineering", Track: models.TrackMath, Gender: models.GenderFemale, Year: 2024, Percentage: 85},
+	}
+}
+
+func TestSimulateTansik(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	prefs := func(pairs ...string) []models.TansikPreference {
+		var out []models.TansikPreference
+		for i := 0; i < len(pairs); i += 2 {
+			out = append(out, models.TansikPreference{UniversityID: pairs[i], Faculty: pairs[i+1]})
+		}
+		return out
+	}
+	tests := []struct {
+		name       string
+		req        models.TansikSimulationRequest
+		statuses   []string
+		wasted     []int
+		admitted   int // rank, 0 when none
+		cutoffYear int // of the admitted preference
+	}{
+		{
+			name: "every status",
+			req: models.TansikSimulationRequest{Percentage: pct(94), Track: models.TrackScience, Gender: models.GenderMale,
+				Zone: "cairo", Preferences: prefs("1", "medicine", "1", "Medicine", "3", "medicine", "1", "engineering",
+					"2", "medicine", "2", "العلوم", "1", "commerce", "9", "medicine", "1", "dentistry")},
+			statuses: []string{
+				models.PreferenceBelowCutoff, models.PreferenceDuplicate, models.PreferenceNotInTansik,
+				models.PreferenceWrongTrack, models.PreferenceOutOfZone, models.PreferenceAdmitted,
+				models.PreferenceNotReached, models.PreferenceUnknown, models.PreferenceUnknown,
+			},
+			// Science is open to Cairo students: no public university there offers it
+			wasted:     []int{2, 3, 4, 5, 7, 8, 9},
+			admitted:   6,
+			cutoffYear: 2024,
+		},
+		{
+			name: "cut-off for everyone",
+			req: models.TansikSimulationRequest{Percentage: pct(96.5), Track: models.TrackScience, Gender: models.GenderMale,
+				Zone: "cairo", Preferences: prefs("1", "medicine")},
+			statuses:   []string{models.PreferenceAdmitted},
+			wasted:     []int{},
+			admitted:   1,
+			cutoffYear: 2024,
+		},
+		{
+			name: "gender cut-off wins in the same year",
+			req: models.TansikSimulationRequest{Percentage: pct(96.5), Track: models.TrackScience, Gender: models.GenderFemale,
+				Zone: "cairo", Preferences: prefs("1", "medicine")},
+			statuses: []string{models.PreferenceBelowCutoff},
+			wasted:   []int{},
+		},
+		{
+			name: "requested year",
+			req: models.TansikSimulationRequest{Percentage: pct(95.5), Track: models.TrackScience, Gender: models.GenderMale,
+				Zone: "cairo", Year: 2023, Preferences: prefs("1", "medicine", "1", "commerce")},
+			statuses:   []string{models.PreferenceAdmitted, models.PreferenceNotReached},
+			wasted:     []int{2},
+			admitted:   1,
+			cutoffYear: 2023,
+		},
+		{
+			name: "no cut-off for the year is not wasted",
+			req: models.TansikSimulationRequest{Percentage: pct(99), Track: models.TrackScience, Gender: models.GenderMale,
+				Zone: "cairo", Year: 2022, Preferences: prefs("1", "commerce")},
+			statuses: []string{models.PreferenceNoCutoff},
+			wasted:   []int{},
+		},
+		{
+			name: "faculty for the other gender",
+			req: models.TansikSimulationRequest{Percentage: pct(95), Track: models.TrackMath, Gender: models.GenderMale,
+				Zone: "cairo", Preferences: prefs("4", "engineering", "1", "engineering")},
+			statuses:   []string{models.PreferenceWrongGender, models.PreferenceAdmitted},
+			wasted:     []int{1},
+			admitted:   2,
+			cutoffYear: 2024,
+		},
+		{
+			name: "score out of a maximum",
+			req: models.TansikSimulationRequest{Score: pct(369), MaxScore: 410, Track: models.TrackMath, Gender: models.GenderFemale,
+				Zone: "cairo", Preferences: prefs("1", "engineering", "4", "engineering")},
+			statuses:   []string{models.PreferenceAdmitted, models.PreferenceNotReached},
+			wasted:     []int{2},
+			admitted:   1,
+			cutoffYear: 2024,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := SimulateTansik(tansikCatalogue(), testFacultyCatalog(), tansikHistory(), tt.req)
+			if err != nil {
+				t.Fatalf("SimulateTansik: %v", err)
+			}
+			var statuses []string
+			for _, p := range resp.Preferences {
+				statuses = append(statuses, p.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.statuses) {
+				t.Fatalf("statuses = %v, want %v", statuses, tt.statuses)
+			}
+			if !reflect.DeepEqual(resp.Wasted, tt.wasted) {
+				t.Errorf("wasted = %v, want %v", resp.Wasted, tt.wasted)
+			}
+			switch {
+			case tt.admitted == 0 && resp.Admitted != nil:
+				t.Errorf("admitted to preference %d, want none", resp.Admitted.Rank)
+			case tt.admitted != 0 && resp.Admitted == nil:
+				t.Errorf("admitted nowhere, want preference %d", tt.admitted)
+			case tt.admitted != 0 && (resp.Admitted.Rank != tt.admitted || resp.Admitted.CutoffYear != tt.cutoffYear):
+				t.Errorf("admitted to preference %d on the %d cut-off, want %d on %d",
+					resp.Admitted.Rank, resp.Admitted.CutoffYear, tt.admitted, tt.cutoffYear)
+			}
+		})
+	}
+}
+
+func TestSimulateTansikMargin(t *testing.T) {
+	pct := 96.54
+	resp, err := SimulateTansik(tansikCatalogue(), testFacultyCatalog(), tansikHistory(), models.TansikSimulationRequest{
+		Percentage: &pct, Track: models.TrackScience, Gender: models.GenderMale, Zone: "cairo",
+		Preferences: []models.TansikPreference{{UniversityID: "1", Faculty: "medicine"}},
+	})
+	if err != nil {
+		t.Fatalf("SimulateTansik: %v", err)
+	}
+	r := resp.Preferences[0]
+	if resp.Percentage != 96.5 || r.Cutoff == nil || *r.Cutoff != 96 || r.Margin == nil || *r.Margin != 0.5 {
+		t.Fatalf("percentage %g, preference %+v: want 96.5 against a cut-off of 96 with a margin of 0.5", resp.Percentage, r)
+	}
+}
+
+func TestSimulateTansikErrors(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	valid := func(edit func(*models.TansikSimulationRequest)) models.TansikSimulationRequest {
+		req := models.TansikSimulationRequest{Percentage: pct(90), Track: models.TrackScience, Gender: models.GenderMale,
+			Zone: "cairo", Preferences: []models.TansikPreference{{UniversityID: "1", Faculty: "medicine"}}}
+		edit(&req)
+		return req
+	}
+	tests := []struct {
+		name string
+		req  models.TansikSimulationRequest
+		want error
+	}{
+		{"missing grade", valid(func(r *models.TansikSimulationRequest) { r.Percentage = nil }), ErrInvalidPercentage},
+		{"percentage above 100", valid(func(r *models.TansikSimulationRequest) { r.Percentage = pct(100.5) }), ErrInvalidPercentage},
+		{"score without maximum", valid(func(r *models.TansikSimulationRequest) { r.Percentage, r.Score = nil, pct(300) }), ErrInvalidPercentage},
+		{"score above maximum", valid(func(r *models.TansikSimulationRequest) { r.Percentage, r.Score, r.MaxScore = nil, pct(411), 410 }), ErrInvalidPercentage},
+		{"unknown track", valid(func(r *models.TansikSimulationRequest) { r.Track = "arts" }), ErrInvalidTrack},
+		{"unknown gender", valid(func(r *models.TansikSimulationRequest) { r.Gender = "" }), ErrInvalidGender},
+		{"unknown zone", valid(func(r *models.TansikSimulationRequest) { r.Zone = "sinai" }), ErrInvalidZone},
+		{"no preferences", valid(func(r *models.TansikSimulationRequest) { r.Preferences = nil }), ErrInvalidPreferences},
+		{"too many preferences", valid(func(r *models.TansikSimulationRequest) {
+			r.Preferences = make([]models.TansikPreference, maxPreferences+1)
+		}), ErrInvalidPreferences},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SimulateTansik(tansikCatalogue(), testFacultyCatalog(), tansikHistory(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
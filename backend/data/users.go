@@ -0,0 +1,110 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"roadtouniversities/auth"
+	"roadtouniversities/models"
+)
+
+// ErrEmailTaken is returned when creating an account with an email already
+// in use
+var ErrEmailTaken = errors.New("email already registered")
+
+// ErrAdminMismatch is returned when the configured admin email belongs to a
+// non-admin account with a different password
+var ErrAdminMismatch = errors.New("admin password does not match the existing account")
+
+// NormalizeEmail returns the form emails are stored and looked up in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidateRegistration checks a registration request, returning a
+// ValidationError listing every invalid field
+func ValidateRegistration(req models.RegisterRequest) error {
+	var v validator
+
+	if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != strings.TrimSpace(req.Email) {
+		v.add("email", "must be an email address, got %q", req.Email)
+	}
+	if n := len(req.Password); n < auth.MinPasswordLength || n > auth.MaxPasswordLength {
+		v.add("password", "must be between %d and %d bytes long", auth.MinPasswordLength, auth.MaxPasswordLength)
+	}
+	if len(req.Name) > 100 {
+		v.add("name", "must be at most 100 characters long")
+	}
+
+	return v.err()
+}
+
+// ValidateLogin checks that a login request holds an email and a password
+func ValidateLogin(req models.LoginRequest) error {
+	var v validator
+	v.required("email", req.Email)
+	if req.Password == "" {
+		v.add("password", "is required")
+	}
+	return v.err()
+}
+
+// ValidateRefresh checks that a refresh or logout request holds a token
+func ValidateRefresh(req models.RefreshRequest) error {
+	var v validator
+	v.required("refreshToken", req.RefreshToken)
+	return v.err()
+}
+
+// NewUser creates an account with a random ID and a hashed password
+func NewUser(email, password, name, role string, now time.Time) (models.User, error) {
+	id, err := auth.RandomString(16)
+	if err != nil {
+		return models.User{}, err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+	return models.User{
+		ID:           id,
+		Email:        NormalizeEmail(email),
+		Name:         strings.TrimSpace(name),
+		Role:         role,
+		PasswordHash: hash,
+		CreatedAt:    now.UTC().Truncate(time.Second),
+	}, nil
+}
+
+// EnsureAdmin creates an admin account with the given credentials unless
+// one with that email exists. An existing account is made an admin only when
+// password is its password; otherwise ErrAdminMismatch is returned, so that
+// whoever registered the email first does not gain admin rights.
+func EnsureAdmin(store Store, email, password string) error {
+	existing, found, err := store.GetUserByEmail(NormalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if found {
+		if existing.Role == models.RoleAdmin {
+			return nil
+		}
+		if !auth.CheckPassword(existing.PasswordHash, password) {
+			return fmt.Errorf("%w: %s is a %s account", ErrAdminMismatch, existing.Email, existing.Role)
+		}
+		_, err := store.SetUserRole(existing.ID, models.RoleAdmin)
+		return err
+	}
+
+	if err := ValidateRegistration(models.RegisterRequest{Email: email, Password: password}); err != nil {
+		return fmt.Errorf("admin account: %w", err)
+	}
+	admin, err := NewUser(email, password, "", models.RoleAdmin, time.Now())
+	if err != nil {
+		return err
+	}
+	return store.CreateUser(admin)
+}
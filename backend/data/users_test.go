@@ -0,0 +1,63 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"roadtouniversities/auth"
+	"roadtouniversities/models"
+)
+
+func TestEnsureAdmin(t *testing.T) {
+	const email, password = "admin@example.com", "admin-password"
+	tests := []struct {
+		name     string
+		existing *models.User // registered before startup, with password
+		password string
+		wantRole string
+		wantErr  error
+	}{
+		{name: "creates the account", wantRole: models.RoleAdmin},
+		{name: "keeps an admin", existing: &models.User{Role: models.RoleAdmin}, password: "another-password",
+			wantRole: models.RoleAdmin},
+		{name: "promotes with the same password", existing: &models.User{Role: models.RoleStudent}, password: password,
+			wantRole: models.RoleAdmin},
+		{name: "refuses another password", existing: &models.User{Role: models.RoleStudent}, password: "student-password",
+			wantRole: models.RoleStudent, wantErr: ErrAdminMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			if tt.existing != nil {
+				user, err := NewUser("Admin@Example.com", tt.password, "", tt.existing.Role, time.Now())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := store.CreateUser(user); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := EnsureAdmin(store, email, password); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnsureAdmin err = %v, want %v", err, tt.wantErr)
+			}
+			user, found, err := store.GetUserByEmail(email)
+			if err != nil || !found {
+				t.Fatalf("GetUserByEmail = %v, %v", found, err)
+			}
+			if user.Role != tt.wantRole {
+				t.Errorf("role = %s, want %s", user.Role, tt.wantRole)
+			}
+			if tt.existing == nil && !auth.CheckPassword(user.PasswordHash, password) {
+				t.Error("created admin does not take the configured password")
+			}
+		})
+	}
+}
+
+func TestEnsureAdminValidates(t *testing.T) {
+	if err := EnsureAdmin(NewMemoryStore(), "admin@example.com", "short"); err == nil {
+		t.Fatal("EnsureAdmin accepted a too short password")
+	}
+}
@@ -0,0 +1,453 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"roadtouniversities/models"
+)
+
+// FieldError describes one invalid field of a record
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every invalid field of a record
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// validator accumulates field errors
+type validator struct {
+	errs ValidationError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) between(field string, value, min, max int) {
+	if value < min || value > max {
+		v.add(field, "must be between %d and %d, got %d", min, max, value)
+	}
+}
+
+func (v *validator) feesRange(field string, fees models.FeesRange) {
+	if fees.Min < 0 {
+		v.add(field+".min", "must not be negative")
+	}
+	if fees.Max < 0 {
+		v.add(field+".max", "must not be negative")
+	}
+	if fees.Min > fees.Max {
+		v.add(field, "min (%d) must not exceed max (%d)", fees.Min, fees.Max)
+	}
+	v.feeTerms(field, fees.Currency, fees.Period)
+}
+
+// feeTerms checks the optional currency and period of a fee
+func (v *validator) feeTerms(field, currency, period string) {
+	if currency != "" && !models.IsCurrencyCode(currency) {
+		v.add(join(field, "currency"), "must be an ISO 4217 code such as EGP or USD, got %q", currency)
+	}
+	if period != "" && !models.IsValidFeePeriod(period) {
+		v.add(join(field, "period"), "must be one of %s, got %q", strings.Join(models.FeePeriods, ", "), period)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// ValidateUniversity checks a university record, returning a ValidationError
+// listing every invalid field
+func ValidateUniversity(uni models.University) error {
+	var v validator
+
+	v.required("id", uni.ID)
+	v.required("name", uni.Name)
+	v.required("nameEn", uni.NameEn)
+
+	if !models.IsValidType(uni.Type) {
+		v.add("type", "must be one of %s, got %q", strings.Join(models.UniversityTypes, ", "), uni.Type)
+	}
+	if !models.IsValidRegion(uni.Region) {
+		v.add("region", "must be one of %s, got %q", strings.Join(models.Regions, ", "), uni.Region)
+	}
+	if uni.Established != 0 {
+		v.between("established", uni.Established, 800, time.Now().Year())
+	}
+	if uni.Rating < 0 || uni.Rating > 5 {
+		v.add("rating", "must be between 0 and 5, got %g", uni.Rating)
+	}
+	if uni.EditorialRating < 0 || uni.EditorialRating > 5 {
+		v.add("editorialRating", "must be between 0 and 5, got %g", uni.EditorialRating)
+	}
+
+	v.feesRange("fees", uni.Fees)
+
+	if len(uni.FacultiesEn) > 0 && len(uni.FacultiesEn) != len(uni.Faculties) {
+		v.add("facultiesEn", "has %d entries but faculties has %d", len(uni.FacultiesEn), len(uni.Faculties))
+	}
+
+	for i, lang := range uni.Languages {
+		if !models.IsValidLanguage(lang) {
+			v.add(fmt.Sprintf("languages[%d]", i), "must be one of %s, got %q", strings.Join(models.Languages, ", "), lang)
+		}
+	}
+
+	v.between("minGrade", uni.MinGrade, 0, 100)
+	if uni.MaxGrade != 0 {
+		v.between("maxGrade", uni.MaxGrade, 0, 100)
+		if uni.MaxGrade < uni.MinGrade {
+			v.add("maxGrade", "must not be below minGrade (%d), got %d", uni.MinGrade, uni.MaxGrade)
+		}
+	}
+	if uni.Students < 0 {
+		v.add("students", "must not be negative")
+	}
+	v.between("acceptanceRate", uni.AcceptanceRate, 0, 100)
+	v.between("employmentRate", uni.EmploymentRate, 0, 100)
+
+	validateAdmission(&v, "admission", uni.Admission)
+	if uni.Costs != nil {
+		validateCosts(&v, "costs", *uni.Costs)
+	}
+
+	keys := make([]string, 0, len(uni.DetailedFaculties))
+	for key := range uni.DetailedFaculties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		validateFaculty(&v, fmt.Sprintf("detailedFaculties[%s]", key), uni.DetailedFaculties[key])
+	}
+
+	return v.err()
+}
+
+func validateCosts(v *validator, field string, c models.CostModel) {
+	if c.AnnualIncrease < 0 || c.AnnualIncrease > 100 {
+		v.add(field+".annualIncrease", "must be between 0 and 100, got %g", c.AnnualIncrease)
+	}
+	amounts := []struct {
+		name  string
+		value int
+	}{
+		{"registrationFee", c.RegistrationFee},
+		{"otherFees", c.OtherFees},
+		{"housing", c.Housing},
+		{"transport", c.Transport},
+	}
+	for _, a := range amounts {
+		if a.value < 0 {
+			v.add(field+"."+a.name, "must not be negative")
+		}
+	}
+}
+
+// ValidateDiscipline checks a faculty discipline record, returning a
+// ValidationError listing every invalid field
+func ValidateDiscipline(d models.Discipline) error {
+	var v validator
+
+	v.required("id", d.ID)
+	if d.ID != "" && FacultySlug(d.ID) != d.ID {
+		v.add("id", "must be lower-case words joined by dashes, got %q", d.ID)
+	}
+	v.required("name", d.Name)
+	v.required("nameEn", d.NameEn)
+	if !models.IsValidFieldOfStudy(d.Field) {
+		v.add("field", "must be one of %s, got %q", strings.Join(models.FieldsOfStudy, ", "), d.Field)
+	}
+	for i, alias := range d.Aliases {
+		v.required(fmt.Sprintf("aliases[%d]", i), alias)
+	}
+
+	return v.err()
+}
+
+// ValidateScholarship checks a scholarship record, returning a
+// ValidationError listing every invalid field. The universities and
+// faculties it names are checked by LoadScholarships.
+func ValidateScholarship(s models.Scholarship) error {
+	var v validator
+
+	v.required("id", s.ID)
+	if s.ID != "" && FacultySlug(s.ID) != s.ID {
+		v.add("id", "must be lower-case words joined by dashes, got %q", s.ID)
+	}
+	v.required("name", s.Name)
+	v.required("nameEn", s.NameEn)
+	v.required("provider", s.Provider)
+	v.required("providerEn", s.ProviderEn)
+	if !models.IsValidScholarshipType(s.Type) {
+		v.add("type", "must be one of %s, got %q", strings.Join(models.ScholarshipTypes, ", "), s.Type)
+	}
+	if len(s.UniversityIDs) == 0 {
+		v.add("universityIds", "must list at least one university")
+	}
+	for i, id := range s.UniversityIDs {
+		v.required(fmt.Sprintf("universityIds[%d]", i), id)
+	}
+	if s.MinGrade < 0 || s.MinGrade > 100 {
+		v.add("minGrade", "must be between 0 and 100, got %g", s.MinGrade)
+	}
+
+	switch {
+	case s.DiscountPercent != 0 && s.DiscountAmount != 0:
+		v.add("discountAmount", "must not be set with discountPercent")
+	case s.DiscountPercent != 0:
+		if s.DiscountPercent < 0 || s.DiscountPercent > 100 {
+			v.add("discountPercent", "must be between 0 and 100, got %g", s.DiscountPercent)
+		}
+		if s.Currency != "" {
+			v.add("currency", "only applies to discountAmount")
+		}
+	case s.DiscountAmount < 0:
+		v.add("discountAmount", "must not be negative")
+	case s.DiscountAmount == 0:
+		v.add("discountPercent", "either discountPercent or discountAmount is required")
+	}
+	if s.Currency != "" && !models.IsCurrencyCode(s.Currency) {
+		v.add("currency", "must be an ISO 4217 code such as EGP or USD, got %q", s.Currency)
+	}
+	if s.Deadline != "" {
+		if _, err := time.Parse(dateLayout, s.Deadline); err != nil {
+			v.add("deadline", "must be a date in YYYY-MM-DD form, got %q", s.Deadline)
+		}
+	}
+	for i, doc := range s.Documents {
+		v.required(fmt.Sprintf("documents[%d]", i), doc)
+	}
+	for i, doc := range s.DocumentsEn {
+		v.required(fmt.Sprintf("documentsEn[%d]", i), doc)
+	}
+
+	return v.err()
+}
+
+// ValidateAdmissionEvent checks an admissions calendar event, returning a
+// ValidationError listing every invalid field. The university and faculty it
+// names are checked by LoadCalendar.
+func ValidateAdmissionEvent(e models.AdmissionEvent) error {
+	var v validator
+
+	v.required("id", e.ID)
+	if e.ID != "" && FacultySlug(e.ID) != e.ID {
+		v.add("id", "must be lower-case words joined by dashes, got %q", e.ID)
+	}
+	if !models.IsValidEventKind(e.Kind) {
+		v.add("kind", "must be one of %s, got %q", strings.Join(models.EventKinds, ", "), e.Kind)
+	}
+	v.required("title", e.Title)
+	v.required("titleEn", e.TitleEn)
+
+	start, err := time.Parse(dateLayout, e.Start)
+	if err != nil {
+		v.add("start", "must be a date in YYYY-MM-DD form, got %q", e.Start)
+	}
+	if e.End != "" {
+		end, endErr := time.Parse(dateLayout, e.End)
+		switch {
+		case endErr != nil:
+			v.add("end", "must be a date in YYYY-MM-DD form, got %q", e.End)
+		case err == nil && end.Before(start):
+			v.add("end", "must not be before start (%s), got %s", e.Start, e.End)
+		}
+	}
+	if e.URL != "" && !strings.HasPrefix(e.URL, "https://") && !strings.HasPrefix(e.URL, "http://") {
+		v.add("url", "must be an http or https URL, got %q", e.URL)
+	}
+
+	return v.err()
+}
+
+// ValidateShortlist checks a shortlist, returning a ValidationError listing
+// every invalid field. The universities, faculties and departments its items
+// name are checked by ResolveShortlistItems.
+func ValidateShortlist(list models.Shortlist) error {
+	var v validator
+
+	v.required("name", list.Name)
+	if utf8.RuneCountInString(list.Name) > MaxShortlistName {
+		v.add("name", "must be at most %d characters long", MaxShortlistName)
+	}
+	if len(list.Items) > MaxShortlistItems {
+		v.add("items", "must have at most %d items, got %d", MaxShortlistItems, len(list.Items))
+	}
+	for i, item := range list.Items {
+		validateShortlistItem(&v, fmt.Sprintf("items[%d]", i), item)
+	}
+
+	return v.err()
+}
+
+// ValidateSavedSearch checks a saved search, returning a ValidationError
+// listing every invalid field. Its parameters are checked by running it.
+func ValidateSavedSearch(s models.SavedSearch) error {
+	var v validator
+
+	v.required("name", s.Name)
+	if utf8.RuneCountInString(s.Name) > MaxSavedSearchName {
+		v.add("name", "must be at most %d characters long", MaxSavedSearchName)
+	}
+	if !models.IsCurrencyCode(s.Currency) {
+		v.add("currency", "must be an ISO 4217 code such as EGP or USD, got %q", s.Currency)
+	}
+	if s.Params.Page < 0 {
+		v.add("params.page", "must not be negative")
+	}
+	if s.Params.PageSize < 0 {
+		v.add("params.pageSize", "must not be negative")
+	}
+
+	return v.err()
+}
+
+// ValidateReview checks a review, returning a ValidationError listing every
+// invalid field. The faculty it names is checked by ResolveReviewFaculty.
+func ValidateReview(r models.Review) error {
+	var v validator
+
+	v.required("universityId", r.UniversityID)
+	v.between("overall", r.Overall, 1, 5)
+	for _, sub := range []struct {
+		field  string
+		rating int
+	}{
+		{"ratings.teaching", r.Ratings.Teaching},
+		{"ratings.facilities", r.Ratings.Facilities},
+		{"ratings.housing", r.Ratings.Housing},
+		{"ratings.careerSupport", r.Ratings.CareerSupport},
+	} {
+		if sub.rating != 0 {
+			v.between(sub.field, sub.rating, 1, 5)
+		}
+	}
+	if utf8.RuneCountInString(r.Title) > MaxReviewTitle {
+		v.add("title", "must be at most %d characters long", MaxReviewTitle)
+	}
+	if n := utf8.RuneCountInString(strings.TrimSpace(r.Body)); n < MinReviewBody || n > MaxReviewBody {
+		v.add("body", "must be between %d and %d characters long, got %d", MinReviewBody, MaxReviewBody, n)
+	}
+	if !models.IsValidReviewStatus(r.Status) {
+		v.add("status", "must be one of %s, got %q", strings.Join(models.ReviewStatuses, ", "), r.Status)
+	}
+
+	return v.err()
+}
+
+// ValidateModerationRequest checks a request to take action on a review,
+// returning a ValidationError listing every invalid field. Every decision
+// names the version of the review it is about; rejections need a reason.
+func ValidateModerationRequest(action string, req models.ModerationRequest) error {
+	var v validator
+
+	if req.UpdatedAt.IsZero() {
+		v.add("updatedAt", "is required")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if action == models.ModerationReject {
+		v.required("reason", reason)
+	}
+	if utf8.RuneCountInString(reason) > MaxModerationReason {
+		v.add("reason", "must be at most %d characters long", MaxModerationReason)
+	}
+
+	return v.err()
+}
+
+func validateShortlistItem(v *validator, prefix string, item models.ShortlistItem) {
+	v.required(join(prefix, "universityId"), item.UniversityID)
+	if strings.TrimSpace(item.Department) != "" && strings.TrimSpace(item.Faculty) == "" {
+		v.add(join(prefix, "department"), "requires a faculty")
+	}
+	if utf8.RuneCountInString(item.Note) > MaxShortlistNote {
+		v.add(join(prefix, "note"), "must be at most %d characters long", MaxShortlistNote)
+	}
+}
+
+func validateFaculty(v *validator, prefix string, faculty models.Faculty) {
+	v.required(join(prefix, "nameEn"), faculty.NameEn)
+	v.feesRange(join(prefix, "annualFees"), faculty.AnnualFees)
+
+	for i, dept := range faculty.Departments {
+		validateDepartment(v, join(prefix, fmt.Sprintf("departments[%d]", i)), dept)
+	}
+	for i, spec := range faculty.Specializations {
+		validateSpecialization(v, join(prefix, fmt.Sprintf("specializations[%d]", i)), spec)
+	}
+	validateAdmission(v, join(prefix, "admission"), faculty.Admission)
+}
+
+func validateAdmission(v *validator, prefix string, rules []models.AdmissionRule) {
+	seen := make(map[string]bool)
+	for i, rule := range rules {
+		field := fmt.Sprintf("%s[%d]", prefix, i)
+		if !models.IsValidCertificate(rule.Certificate) {
+			v.add(field+".certificate", "must be one of %s, got %q", strings.Join(models.Certificates, ", "), rule.Certificate)
+		} else if seen[rule.Certificate] {
+			v.add(field+".certificate", "duplicate rule for %s", rule.Certificate)
+		}
+		seen[rule.Certificate] = true
+
+		if rule.MinPercentage < 0 || rule.MinPercentage > 100 {
+			v.add(field+".minPercentage", "must be between 0 and 100, got %g", rule.MinPercentage)
+		}
+		if rule.MinScore < 0 {
+			v.add(field+".minScore", "must not be negative")
+		}
+		if rule.ScoreScale < 0 {
+			v.add(field+".scoreScale", "must not be negative")
+		}
+		if rule.ScoreScale > 0 && rule.MinScore > rule.ScoreScale {
+			v.add(field+".minScore", "must not exceed scoreScale (%g), got %g", rule.ScoreScale, rule.MinScore)
+		}
+	}
+}
+
+func validateDepartment(v *validator, prefix string, dept models.Department) {
+	v.required(join(prefix, "name"), dept.Name)
+	v.required(join(prefix, "nameEn"), dept.NameEn)
+	if dept.Fees < 0 {
+		v.add(join(prefix, "fees"), "must not be negative")
+	}
+	v.feeTerms(prefix, dept.Currency, dept.Period)
+}
+
+func validateSpecialization(v *validator, prefix string, spec models.Specialization) {
+	v.required(join(prefix, "name"), spec.Name)
+	v.required(join(prefix, "nameEn"), spec.NameEn)
+	if spec.Fees < 0 {
+		v.add(join(prefix, "fees"), "must not be negative")
+	}
+	v.feeTerms(prefix, spec.Currency, spec.Period)
+}
+
+// join builds a dotted field path
+func join(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// dummyHash is compared against when logging in to an unknown email, so
+// the response time does not reveal which emails are registered
+var dummyHash, _ = auth.HashPassword("not a real password")
+
+// Register creates a student account and signs it in
+func (h *Handler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if err := data.ValidateRegistration(req); err != nil {
+		respondValidation(c, err)
+		return
+	}
+
+	user, err := data.NewUser(req.Email, req.Password, req.Name, models.RoleStudent, time.Now())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	err = h.store.CreateUser(user)
+	if errors.Is(err, data.ErrEmailTaken) {
+		c.JSON(http.StatusConflict, models.NewErrorResponse("Email already registered", "EMAIL_TAKEN"))
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	h.issueTokens(c, http.StatusCreated, user)
+}
+
+// Login exchanges an email and password for tokens
+func (h *Handler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if err := data.ValidateLogin(req); err != nil {
+		respondValidation(c, err)
+		return
+	}
+
+	user, found, err := h.store.GetUserByEmail(data.NormalizeEmail(req.Email))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	hash := dummyHash
+	if found {
+		hash = user.PasswordHash
+	}
+	if !auth.CheckPassword(hash, req.Password) || !found {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Invalid email or password", "INVALID_CREDENTIALS"))
+		return
+	}
+	h.issueTokens(c, http.StatusOK, user)
+}
+
+// Refresh exchanges a refresh token for new tokens. Each refresh token is
+// used once; the response carries its replacement.
+func (h *Handler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if err := data.ValidateRefresh(req); err != nil {
+		respondValidation(c, err)
+		return
+	}
+
+	token, found, err := h.store.TakeRefreshToken(auth.HashToken(req.RefreshToken))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found || time.Now().After(token.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Invalid or expired refresh token", "INVALID_TOKEN"))
+		return
+	}
+	// The account is reloaded so role changes take effect
+	user, found, err := h.store.GetUser(token.UserID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Invalid or expired refresh token", "INVALID_TOKEN"))
+		return
+	}
+	h.issueTokens(c, http.StatusOK, user)
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they
+// expire.
+func (h *Handler) Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if err := data.ValidateRefresh(req); err != nil {
+		respondValidation(c, err)
+		return
+	}
+	if _, _, err := h.store.TakeRefreshToken(auth.HashToken(req.RefreshToken)); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{}, "Logged out"))
+}
+
+// Me returns the account of the current user
+func (h *Handler) Me(c *gin.Context) {
+	current, _ := CurrentUser(c)
+	user, found, err := h.store.GetUser(current.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("User not found", "NOT_FOUND"))
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(user, ""))
+}
+
+// ListUsers returns every account
+func (h *Handler) ListUsers(c *gin.Context) {
+	users, err := h.store.ListUsers()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(users, ""))
+}
+
+// SetUserRole changes the role of an account. Admins cannot change their
+// own, so one always remains.
+func (h *Handler) SetUserRole(c *gin.Context) {
+	var req models.RoleRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if !models.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid role", "INVALID_ROLE", gin.H{"roles": models.Roles}))
+		return
+	}
+	id := c.Param("id")
+	if current, _ := CurrentUser(c); current.ID == id {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Admins cannot change their own role", "INVALID_ROLE"))
+		return
+	}
+
+	found, err := h.store.SetUserRole(id, req.Role)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("User not found", "NOT_FOUND"))
+		return
+	}
+	user, _, err := h.store.GetUser(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(user, "Role updated"))
+}
+
+// issueTokens responds with a new access and refresh token for user
+func (h *Handler) issueTokens(c *gin.Context, status int, user models.User) {
+	now := time.Now()
+	access, err := h.tokens.IssueAccess(user, now)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	refresh, record, err := h.tokens.NewRefreshToken(user.ID, now)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := h.store.PutRefreshToken(record); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(status, models.NewSuccessResponse(models.TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(h.tokens.AccessTTL.Seconds()),
+		RefreshToken: refresh,
+		User:         user,
+	}, ""))
+}
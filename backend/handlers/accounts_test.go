@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// accountServer routes the account endpoints like main.go
+func accountServer(store data.Store, tokens *auth.Issuer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := New(store, Options{Tokens: tokens})
+	r := gin.New()
+	account := r.Group("/auth", Authenticate(tokens))
+	account.POST("/register", h.Register)
+	account.POST("/login", h.Login)
+	account.POST("/refresh", h.Refresh)
+	account.POST("/logout", h.Logout)
+	account.GET("/me", RequireRole(), h.Me)
+	return r
+}
+
+// call sends a JSON request, decoding the token response when there is one
+func call(t *testing.T, r http.Handler, method, path, bearer string, body any) (int, models.TokenResponse) {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp models.APIResponse[models.TokenResponse]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp.Data
+}
+
+func TestRefreshRotation(t *testing.T) {
+	store := data.NewMemoryStore()
+	r := accountServer(store, auth.NewIssuer([]byte("secret"), 0, 0))
+
+	status, first := call(t, r, http.MethodPost, "/auth/register", "",
+		models.RegisterRequest{Email: "student@example.com", Password: "long enough", Name: "Student"})
+	if status != http.StatusCreated || first.RefreshToken == "" || first.AccessToken == "" {
+		t.Fatalf("register = %d %+v, want 201 with tokens", status, first)
+	}
+
+	status, second := call(t, r, http.MethodPost, "/auth/refresh", "", models.RefreshRequest{RefreshToken: first.RefreshToken})
+	if status != http.StatusOK || second.RefreshToken == "" || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("refresh = %d %+v, want 200 with a new refresh token", status, second)
+	}
+	if status, _ := call(t, r, http.MethodGet, "/auth/me", second.AccessToken, nil); status != http.StatusOK {
+		t.Fatalf("me with the refreshed access token = %d, want 200", status)
+	}
+
+	// A refresh token is spent once used
+	if status, _ := call(t, r, http.MethodPost, "/auth/refresh", "", models.RefreshRequest{RefreshToken: first.RefreshToken}); status != http.StatusUnauthorized {
+		t.Fatalf("reusing a refresh token = %d, want 401", status)
+	}
+
+	// Role changes take effect on the next refresh
+	if _, err := store.SetUserRole(second.User.ID, models.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	status, third := call(t, r, http.MethodPost, "/auth/refresh", "", models.RefreshRequest{RefreshToken: second.RefreshToken})
+	if status != http.StatusOK || third.User.Role != models.RoleEditor {
+		t.Fatalf("refresh after a role change = %d %+v, want 200 as an editor", status, third.User)
+	}
+	user, err := auth.NewIssuer([]byte("secret"), 0, 0).ParseAccess(third.AccessToken)
+	if err != nil || user.Role != models.RoleEditor {
+		t.Fatalf("refreshed access token = %+v, %v, want the editor role", user, err)
+	}
+
+	// Logging out revokes the refresh token
+	if status, _ := call(t, r, http.MethodPost, "/auth/logout", "", models.RefreshRequest{RefreshToken: third.RefreshToken}); status != http.StatusOK {
+		t.Fatalf("logout = %d, want 200", status)
+	}
+	if status, _ := call(t, r, http.MethodPost, "/auth/refresh", "", models.RefreshRequest{RefreshToken: third.RefreshToken}); status != http.StatusUnauthorized {
+		t.Fatalf("refresh after logout = %d, want 401", status)
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	store := data.NewMemoryStore()
+	r := accountServer(store, auth.NewIssuer([]byte("secret"), 0, time.Nanosecond))
+
+	_, tokens := call(t, r, http.MethodPost, "/auth/register", "",
+		models.RegisterRequest{Email: "student@example.com", Password: "long enough"})
+	time.Sleep(time.Millisecond)
+
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"expired", models.RefreshRequest{RefreshToken: tokens.RefreshToken}, http.StatusUnauthorized},
+		{"unknown", models.RefreshRequest{RefreshToken: "0123456789abcdef"}, http.StatusUnauthorized},
+		{"missing", struct{}{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := call(t, r, http.MethodPost, "/auth/refresh", "", tt.body); status != tt.want {
+				t.Fatalf("refresh = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	r := accountServer(data.NewMemoryStore(), tokens)
+	_, registered := call(t, r, http.MethodPost, "/auth/register", "",
+		models.RegisterRequest{Email: "student@example.com", Password: "long enough"})
+	forged, err := auth.NewIssuer([]byte("other"), 0, 0).IssueAccess(registered.User, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer " + registered.AccessToken, http.StatusOK},
+		{"anonymous", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic " + registered.AccessToken, http.StatusUnauthorized},
+		{"forged", "Bearer " + forged, http.StatusUnauthorized},
+		{"refresh token as access token", "Bearer " + registered.RefreshToken, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("me = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestAccountRequestsStrict(t *testing.T) {
+	r := accountServer(data.NewMemoryStore(), auth.NewIssuer([]byte("secret"), 0, 0))
+	tests := []struct {
+		name, path, body string
+		want             string // error code
+	}{
+		{"register unknown field", "/auth/register", `{"email":"student@example.com","password":"long enough","role":"admin"}`, "INVALID_REQUEST"},
+		{"register invalid", "/auth/register", `{"email":"student","password":"short"}`, "VALIDATION_ERROR"},
+		{"login unknown field", "/auth/login", `{"email":"student@example.com","password":"long enough","remember":true}`, "INVALID_REQUEST"},
+		{"login missing password", "/auth/login", `{"email":"student@example.com"}`, "VALIDATION_ERROR"},
+		{"refresh unknown field", "/auth/refresh", `{"refreshToken":"0123456789abcdef","userId":"1"}`, "INVALID_REQUEST"},
+		{"refresh missing token", "/auth/refresh", `{}`, "VALIDATION_ERROR"},
+		{"logout missing token", "/auth/logout", `{"refreshToken":" "}`, "VALIDATION_ERROR"},
+		{"malformed", "/auth/login", `{"email":`, "INVALID_REQUEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := edit(r, http.MethodPost, tt.path, tt.body)
+			var resp models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != http.StatusBadRequest || resp.Code != tt.want {
+				t.Errorf("POST %s = %d %s, want 400 %s", tt.path, w.Code, w.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// CreateUniversity adds a university. The ID is assigned when omitted, and
+// the rating is its editorial rating until reviews are approved.
+func (h *Handler) CreateUniversity(c *gin.Context) {
+	var uni models.University
+	if !bindStrict(c, &uni) {
+		return
+	}
+	uni.Rating = uni.EditorialRating
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if uni.ID == "" {
+		unis, ok := h.universities(c)
+		if !ok {
+			return
+		}
+		uni.ID = data.NextUniversityID(unis)
+	}
+	if !h.checkUniversity(c, uni) {
+		return
+	}
+
+	err := h.store.CreateUniversity(uni)
+	if errors.Is(err, data.ErrUniversityExists) {
+		c.JSON(http.StatusConflict, models.NewErrorResponse("University ID already exists", "CONFLICT"))
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	h.invalidateCatalog()
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(uni, "University created"))
+}
+
+// ReplaceUniversity overwrites an existing university. One deleted in the
+// meantime is not re-created.
+func (h *Handler) ReplaceUniversity(c *gin.Context) {
+	id := c.Param("id")
+	var uni models.University
+	if !bindStrict(c, &uni) {
+		return
+	}
+	if uni.ID == "" {
+		uni.ID = id
+	}
+	if uni.ID != id {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Body ID does not match URL", "ID_MISMATCH"))
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if _, ok := h.loadUniversity(c, id); !ok {
+		return
+	}
+	uni, ok := h.saveUniversity(c, uni)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(uni, "University updated"))
+}
+
+// PatchUniversity applies a JSON merge patch (RFC 7386) to a university
+func (h *Handler) PatchUniversity(c *gin.Context) {
+	id := c.Param("id")
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+	var patch any
+	if err := json.Unmarshal(body, &patch); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+	if _, isObject := patch.(map[string]any); !isObject {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Patch must be a JSON object", "INVALID_REQUEST"))
+		return
+	}
+
+	// The patch applies to the university as stored when it is saved
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	existing, ok := h.loadUniversity(c, id)
+	if !ok {
+		return
+	}
+	current, err := json.Marshal(existing)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	var doc any
+	if err := json.Unmarshal(current, &doc); err != nil {
+		internalError(c, err)
+		return
+	}
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	var uni models.University
+	if err := decodeStrict(merged, &uni); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid patch", "INVALID_REQUEST", err.Error()))
+		return
+	}
+	if uni.ID != id {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("University ID cannot be changed", "ID_MISMATCH"))
+		return
+	}
+
+	uni, ok = h.saveUniversity(c, uni)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(uni, "University updated"))
+}
+
+// DeleteUniversity removes a university
+func (h *Handler) DeleteUniversity(c *gin.Context) {
+	id := c.Param("id")
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	deleted, err := h.store.DeleteUniversity(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("University not found", "NOT_FOUND"))
+		return
+	}
+	h.invalidateCatalog()
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"id": id}, "University deleted"))
+}
+
+// PutFaculty creates or replaces a detailed faculty keyed by its Arabic name,
+// adding the name to the university's faculty lists when it is new
+func (h *Handler) PutFaculty(c *gin.Context) {
+	var faculty models.Faculty
+	if !bindStrict(c, &faculty) {
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	uni, ok := h.loadUniversity(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	key := c.Param("faculty")
+	_, existed := uni.DetailedFaculties[key]
+	if uni.DetailedFaculties == nil {
+		uni.DetailedFaculties = make(map[string]models.Faculty)
+	}
+	uni.DetailedFaculties[key] = faculty
+
+	if !containsString(uni.Faculties, key) {
+		// Keep the English list aligned with the Arabic one
+		if len(uni.FacultiesEn) == len(uni.Faculties) {
+			uni.FacultiesEn = append(uni.FacultiesEn, strings.TrimPrefix(faculty.NameEn, "Faculty of "))
+		}
+		uni.Faculties = append(uni.Faculties, key)
+	}
+
+	if _, ok := h.saveUniversity(c, uni); !ok {
+		return
+	}
+	status := http.StatusOK
+	if !existed {
+		status = http.StatusCreated
+	}
+	c.JSON(status, models.NewSuccessResponse(faculty, ""))
+}
+
+// DeleteFaculty removes a detailed faculty. The faculty stays in the
+// university's name lists; only its details are dropped.
+func (h *Handler) DeleteFaculty(c *gin.Context) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	uni, ok := h.loadUniversity(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	key := c.Param("faculty")
+	if _, found := uni.DetailedFaculties[key]; !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Faculty not found", "FACULTY_NOT_FOUND"))
+		return
+	}
+	delete(uni.DetailedFaculties, key)
+
+	if _, ok := h.saveUniversity(c, uni); !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"faculty": key}, "Faculty deleted"))
+}
+
+// CreateDepartment appends a department to a detailed faculty
+func (h *Handler) CreateDepartment(c *gin.Context) {
+	createEntry(h, c, departments)
+}
+
+// ReplaceDepartment overwrites the department at :index
+func (h *Handler) ReplaceDepartment(c *gin.Context) {
+	replaceEntry(h, c, departments)
+}
+
+// DeleteDepartment removes the department at :index
+func (h *Handler) DeleteDepartment(c *gin.Context) {
+	deleteEntry(h, c, departments)
+}
+
+// CreateSpecialization appends a specialization to a detailed faculty
+func (h *Handler) CreateSpecialization(c *gin.Context) {
+	createEntry(h, c, specializations)
+}
+
+// ReplaceSpecialization overwrites the specialization at :index
+func (h *Handler) ReplaceSpecialization(c *gin.Context) {
+	replaceEntry(h, c, specializations)
+}
+
+// DeleteSpecialization removes the specialization at :index
+func (h *Handler) DeleteSpecialization(c *gin.Context) {
+	deleteEntry(h, c, specializations)
+}
+
+// facultyList selects one of the nested lists of a faculty
+type facultyList[T any] func(f *models.Faculty) *[]T
+
+func departments(f *models.Faculty) *[]models.Department {
+	return &f.Departments
+}
+
+func specializations(f *models.Faculty) *[]models.Specialization {
+	return &f.Specializations
+}
+
+func createEntry[T any](h *Handler, c *gin.Context, list facultyList[T]) {
+	var entry T
+	if !bindStrict(c, &entry) {
+		return
+	}
+
+	ok := h.updateFaculty(c, func(f *models.Faculty) bool {
+		*list(f) = append(*list(f), entry)
+		return true
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(entry, ""))
+}
+
+func replaceEntry[T any](h *Handler, c *gin.Context, list facultyList[T]) {
+	var entry T
+	if !bindStrict(c, &entry) {
+		return
+	}
+
+	ok := h.updateFaculty(c, func(f *models.Faculty) bool {
+		i, ok := entryIndex(c, len(*list(f)))
+		if ok {
+			(*list(f))[i] = entry
+		}
+		return ok
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(entry, ""))
+}
+
+func deleteEntry[T any](h *Handler, c *gin.Context, list facultyList[T]) {
+	var removed T
+	ok := h.updateFaculty(c, func(f *models.Faculty) bool {
+		i, ok := entryIndex(c, len(*list(f)))
+		if ok {
+			removed = (*list(f))[i]
+			*list(f) = append((*list(f))[:i], (*list(f))[i+1:]...)
+		}
+		return ok
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(removed, "Entry deleted"))
+}
+
+// updateFaculty loads the university and detailed faculty named in the path,
+// applies fn and saves the result. fn writes its own error response and
+// returns false to abort.
+func (h *Handler) updateFaculty(c *gin.Context, fn func(f *models.Faculty) bool) bool {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	uni, ok := h.loadUniversity(c, c.Param("id"))
+	if !ok {
+		return false
+	}
+
+	key := c.Param("faculty")
+	faculty, found := uni.DetailedFaculties[key]
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Faculty not found", "FACULTY_NOT_FOUND"))
+		return false
+	}
+	if !fn(&faculty) {
+		return false
+	}
+	uni.DetailedFaculties[key] = faculty
+
+	_, ok = h.saveUniversity(c, uni)
+	return ok
+}
+
+// entryIndex parses the :index path parameter against a list length
+func entryIndex(c *gin.Context, length int) (int, bool) {
+	i, err := strconv.Atoi(c.Param("index"))
+	if err != nil || i < 0 || i >= length {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Entry not found", "NOT_FOUND"))
+		return 0, false
+	}
+	return i, true
+}
+
+// loadUniversity fetches a university, writing a 404 when it does not exist
+func (h *Handler) loadUniversity(c *gin.Context, id string) (models.University, bool) {
+	uni, found, err := h.store.GetUniversity(id)
+	if err != nil {
+		internalError(c, err)
+		return models.University{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("University not found", "NOT_FOUND"))
+		return models.University{}, false
+	}
+	return uni, true
+}
+
+// saveUniversity validates and stores a university, returning what was
+// stored. The rating is computed from the editorial rating and approved
+// reviews; the one given is ignored. It writes the error response on
+// failure. Callers hold writeMu.
+func (h *Handler) saveUniversity(c *gin.Context, uni models.University) (models.University, bool) {
+	approved, err := h.store.ListReviews(data.ReviewFilter{UniversityID: uni.ID, Status: models.ReviewApproved})
+	if err == nil && len(approved) > 0 && uni.EditorialRating == 0 {
+		// Rated against the catalogue-wide mean
+		approved, err = h.store.ListReviews(data.ReviewFilter{Status: models.ReviewApproved})
+	}
+	if err != nil {
+		internalError(c, err)
+		return models.University{}, false
+	}
+	uni.Rating = data.ReviewRatings([]models.University{uni}, approved)[uni.ID]
+
+	if !h.checkUniversity(c, uni) {
+		return models.University{}, false
+	}
+	if err := h.store.PutUniversity(uni); err != nil {
+		internalError(c, err)
+		return models.University{}, false
+	}
+	h.invalidateCatalog()
+	return uni, true
+}
+
+// checkUniversity validates uni and checks its fee currency can be
+// converted, writing a 400 when it cannot be stored
+func (h *Handler) checkUniversity(c *gin.Context, uni models.University) bool {
+	if err := data.ValidateUniversity(uni); err != nil {
+		var verr data.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Validation failed", "VALIDATION_ERROR", verr))
+			return false
+		}
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "VALIDATION_ERROR"))
+		return false
+	}
+	if missing := h.rates.MissingRates([]models.University{uni}, time.Now()); len(missing) > 0 {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("No exchange rate for "+strings.Join(missing, ", "), "INVALID_CURRENCY",
+			gin.H{"currencies": h.rates.Currencies(time.Now())}))
+		return false
+	}
+	return true
+}
+
+// bindStrict decodes the JSON body into v, rejecting unknown fields
+func bindStrict(c *gin.Context, v any) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err == nil {
+		err = decodeStrict(body, v)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid request body", "INVALID_REQUEST", err.Error()))
+		return false
+	}
+	return true
+}
+
+func decodeStrict(body []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// mergePatch applies an RFC 7386 JSON merge patch to target
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// adminServer routes the catalogue editing endpoints like main.go, without
+// the editor check
+func adminServer(store data.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := New(store, Options{})
+	r := gin.New()
+	r.PUT("/universities/:id", h.ReplaceUniversity)
+	r.PATCH("/universities/:id", h.PatchUniversity)
+	r.DELETE("/universities/:id", h.DeleteUniversity)
+	r.PUT("/universities/:id/faculties/:faculty", h.PutFaculty)
+	r.POST("/universities/:id/faculties/:faculty/departments", h.CreateDepartment)
+	return r
+}
+
+// edit sends a raw JSON body, returning the response
+func edit(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func adminUniversity() models.University {
+	return models.University{
+		ID: "1", Name: "جامعة القاهرة", NameEn: "Cairo University", Type: "public", Region: "cairo",
+		DescriptionEn: "The oldest public university", EditorialRating: 4, Rating: 4,
+		Fees:        models.FeesRange{Min: 1000, Max: 3000},
+		Faculties:   []string{"الهندسة"},
+		FacultiesEn: []string{"Engineering"},
+		DetailedFaculties: map[string]models.Faculty{
+			"الهندسة": {NameEn: "Faculty of Engineering", AnnualFees: models.FeesRange{Min: 2000, Max: 3000}},
+		},
+		Costs: &models.CostModel{AnnualIncrease: 10, Housing: 6000},
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name, target, patch, want string
+	}{
+		{"adds and replaces", `{"a":1,"b":2}`, `{"b":3,"c":4}`, `{"a":1,"b":3,"c":4}`},
+		{"null removes", `{"a":1,"b":2}`, `{"a":null}`, `{"b":2}`},
+		{"null for a missing field", `{"a":1}`, `{"z":null}`, `{"a":1}`},
+		{"nested merge", `{"fees":{"min":1,"max":2},"name":"x"}`, `{"fees":{"max":5}}`, `{"fees":{"min":1,"max":5},"name":"x"}`},
+		{"nested null removes", `{"fees":{"min":1,"max":2}}`, `{"fees":{"min":null}}`, `{"fees":{"max":2}}`},
+		{"arrays are replaced", `{"tags":["a","b"]}`, `{"tags":["c"]}`, `{"tags":["c"]}`},
+		{"object over a scalar", `{"fees":1}`, `{"fees":{"max":2}}`, `{"fees":{"max":2}}`},
+		{"scalar over an object", `{"fees":{"max":2}}`, `{"fees":7}`, `{"fees":7}`},
+		{"nested null in a new object", `{}`, `{"fees":{"min":null,"max":2}}`, `{"fees":{"max":2}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target, patch, want any
+			for _, doc := range []struct {
+				raw string
+				v   *any
+			}{{tt.target, &target}, {tt.patch, &patch}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(doc.raw), doc.v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPatchUniversity(t *testing.T) {
+	tests := []struct {
+		name   string
+		patch  string
+		status int
+		check  func(t *testing.T, uni models.University)
+	}{
+		{"nested field", `{"fees":{"max":4000}}`, http.StatusOK, func(t *testing.T, uni models.University) {
+			if want := (models.FeesRange{Min: 1000, Max: 4000}); uni.Fees != want {
+				t.Errorf("fees = %+v, want %+v", uni.Fees, want)
+			}
+		}},
+		{"null removes a field", `{"descriptionEn":null,"costs":null}`, http.StatusOK, func(t *testing.T, uni models.University) {
+			if uni.DescriptionEn != "" || uni.Costs != nil {
+				t.Errorf("descriptionEn %q and costs %+v kept", uni.DescriptionEn, uni.Costs)
+			}
+			if uni.NameEn != "Cairo University" {
+				t.Errorf("nameEn = %q, want it untouched", uni.NameEn)
+			}
+		}},
+		{"nested faculty", `{"detailedFaculties":{"الهندسة":{"descriptionEn":"Founded 1816"}}}`, http.StatusOK, func(t *testing.T, uni models.University) {
+			got := uni.DetailedFaculties["الهندسة"]
+			if got.DescriptionEn != "Founded 1816" || got.NameEn != "Faculty of Engineering" || got.AnnualFees.Max != 3000 {
+				t.Errorf("faculty = %+v, want the description added and the rest kept", got)
+			}
+		}},
+		{"rating ignored", `{"rating":1}`, http.StatusOK, func(t *testing.T, uni models.University) {
+			if uni.Rating != 4 {
+				t.Errorf("rating = %g, want the editorial 4", uni.Rating)
+			}
+		}},
+		{"ID change", `{"id":"2"}`, http.StatusBadRequest, nil},
+		{"unknown field", `{"motto":"x"}`, http.StatusBadRequest, nil},
+		{"invalid result", `{"region":"atlantis"}`, http.StatusBadRequest, nil},
+		{"not an object", `["fees"]`, http.StatusBadRequest, nil},
+		{"malformed", `{"fees":`, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := data.NewMemoryStore()
+			if err := store.PutUniversity(adminUniversity()); err != nil {
+				t.Fatal(err)
+			}
+			w := edit(adminServer(store), http.MethodPatch, "/universities/1", tt.patch)
+			if w.Code != tt.status {
+				t.Fatalf("PATCH = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			stored, _, err := store.GetUniversity("1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.check == nil {
+				if !reflect.DeepEqual(stored, adminUniversity()) {
+					t.Errorf("rejected patch changed the university to %+v", stored)
+				}
+				return
+			}
+			tt.check(t, stored)
+		})
+	}
+
+	store := data.NewMemoryStore()
+	if w := edit(adminServer(store), http.MethodPatch, "/universities/1", `{}`); w.Code != http.StatusNotFound {
+		t.Errorf("PATCH of a missing university = %d, want 404", w.Code)
+	}
+}
+
+// TestSaveUniversityRating rates an edited university from its own reviews,
+// and against the catalogue-wide mean when it has no editorial rating
+func TestSaveUniversityRating(t *testing.T) {
+	store := data.NewMemoryStore()
+	unrated := adminUniversity()
+	unrated.ID, unrated.EditorialRating, unrated.Rating = "2", 0, 0
+	unis := []models.University{adminUniversity(), unrated}
+	for _, uni := range unis {
+		if err := store.PutUniversity(uni); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reviews := []models.Review{
+		{ID: "r1", UniversityID: "1", UserID: "u1", Overall: 5, Status: models.ReviewApproved},
+		{ID: "r2", UniversityID: "1", UserID: "u2", Overall: 1, Status: models.ReviewPending},
+		{ID: "r3", UniversityID: "2", UserID: "u1", Overall: 2, Status: models.ReviewApproved},
+	}
+	for _, r := range reviews {
+		if err := store.PutReview(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	approved := []models.Review{reviews[0], reviews[2]}
+	want := data.ReviewRatings(unis, approved)
+
+	r := adminServer(store)
+	for _, id := range []string{"1", "2"} {
+		if w := edit(r, http.MethodPatch, "/universities/"+id, `{"descriptionEn":"Edited"}`); w.Code != http.StatusOK {
+			t.Fatalf("PATCH %s = %d: %s", id, w.Code, w.Body)
+		}
+		if got := universityRating(t, store, id); got != want[id] {
+			t.Errorf("university %s rating = %g, want %g", id, got, want[id])
+		}
+	}
+}
+
+func TestReplaceDeletedUniversity(t *testing.T) {
+	store := data.NewMemoryStore()
+	if err := store.PutUniversity(adminUniversity()); err != nil {
+		t.Fatal(err)
+	}
+	r := adminServer(store)
+	if w := edit(r, http.MethodDelete, "/universities/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("DELETE = %d: %s", w.Code, w.Body)
+	}
+
+	body, err := json.Marshal(adminUniversity())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range []struct{ method, path, body string }{
+		{http.MethodPut, "/universities/1", string(body)},
+		{http.MethodPatch, "/universities/1", `{"nameEn":"Cairo"}`},
+		{http.MethodPut, "/universities/1/faculties/الطب", `{"nameEn":"Faculty of Medicine"}`},
+	} {
+		if w := edit(r, req.method, req.path, req.body); w.Code != http.StatusNotFound {
+			t.Errorf("%s %s after DELETE = %d, want 404", req.method, req.path, w.Code)
+		}
+	}
+	if _, found, err := store.GetUniversity("1"); err != nil || found {
+		t.Errorf("university re-created after DELETE: %v, %v", found, err)
+	}
+}
+
+// slowReads pauses after reading a university, so concurrent edits overlap
+type slowReads struct {
+	data.Store
+}
+
+func (s slowReads) GetUniversity(id string) (models.University, bool, error) {
+	defer time.Sleep(time.Millisecond)
+	return s.Store.GetUniversity(id)
+}
+
+// TestConcurrentUniversityEdits edits different parts of one university at
+// once; every edit must survive
+func TestConcurrentUniversityEdits(t *testing.T) {
+	store := data.NewMemoryStore()
+	if err := store.PutUniversity(adminUniversity()); err != nil {
+		t.Fatal(err)
+	}
+	r := adminServer(slowReads{store})
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			path := fmt.Sprintf("/universities/1/faculties/كلية%d", i)
+			if w := edit(r, http.MethodPut, path, fmt.Sprintf(`{"nameEn":"Faculty %d"}`, i)); w.Code != http.StatusCreated {
+				t.Errorf("PUT %s = %d: %s", path, w.Code, w.Body)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"name":"قسم %d","nameEn":"Department %d"}`, i, i)
+			if w := edit(r, http.MethodPost, "/universities/1/faculties/الهندسة/departments", body); w.Code != http.StatusCreated {
+				t.Errorf("POST department %d = %d: %s", i, w.Code, w.Body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	uni, _, err := store.GetUniversity("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uni.DetailedFaculties) != n+1 || len(uni.Faculties) != n+1 || len(uni.FacultiesEn) != n+1 {
+		t.Errorf("%d detailed faculties, %d names, %d English names; want %d of each",
+			len(uni.DetailedFaculties), len(uni.Faculties), len(uni.FacultiesEn), n+1)
+	}
+	if got := len(uni.DetailedFaculties["الهندسة"].Departments); got != n {
+		t.Errorf("%d departments, want %d", got, n)
+	}
+}
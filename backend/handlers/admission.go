@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// EstimateAdmission classifies the student's chances at each university or
+// requested faculty as safe, likely, reach or out of range
+func (h *Handler) EstimateAdmission(c *gin.Context) {
+	var req models.AdmissionEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	cutoffs, err := h.store.ListCutoffs()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	resp, err := data.EstimateAdmission(unis, cat.faculties, cutoffs, req)
+	switch {
+	case errors.Is(err, data.ErrInvalidPercentage):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_PERCENTAGE"))
+		return
+	case errors.Is(err, data.ErrInvalidBackground):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_CERTIFICATE"))
+		return
+	case errors.Is(err, data.ErrInvalidTrack):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_TRACK"))
+		return
+	case errors.Is(err, data.ErrInvalidGender):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_GENDER"))
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(resp, ""))
+}
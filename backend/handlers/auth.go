@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/models"
+)
+
+// userKey is the context key of the authenticated user
+const userKey = "user"
+
+// Authenticate reads the bearer access token of a request, if any, and
+// stores its user for CurrentUser. Requests without a token pass through
+// anonymously; an invalid or expired token is rejected so clients know to
+// refresh it.
+func Authenticate(tokens *auth.Issuer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.NewErrorResponse("Authorization must be a bearer token", "INVALID_TOKEN"))
+			return
+		}
+		user, err := tokens.ParseAccess(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.NewErrorResponse("Invalid or expired access token", "INVALID_TOKEN"))
+			return
+		}
+		c.Set(userKey, user)
+		c.Next()
+	}
+}
+
+// RequireRole lets through authenticated users holding one of roles, or
+// any role when none are given. Admins hold every role.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.NewErrorResponse("Authentication required", "UNAUTHORIZED"))
+			return
+		}
+		if len(roles) > 0 && user.Role != models.RoleAdmin && !containsString(roles, user.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.NewErrorResponse("Insufficient permissions", "FORBIDDEN"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUser returns the user authenticated by the request's access token.
+// It carries the ID, email and role the token was issued with.
+func CurrentUser(c *gin.Context) (models.User, bool) {
+	v, found := c.Get(userKey)
+	if !found {
+		return models.User{}, false
+	}
+	user, ok := v.(models.User)
+	return user, ok
+}
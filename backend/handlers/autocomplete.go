@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+// defaultCompletions is the autocomplete limit when none is given
+const defaultCompletions = 10
+
+// Autocomplete returns typed name suggestions for the search bar prefix in
+// ?q. ?lang (ar or en) picks the language of each suggestion's text and
+// defaults to the script of the query; ?limit caps the result count.
+func (h *Handler) Autocomplete(c *gin.Context) {
+	query := c.Query("q")
+
+	lang := c.Query("lang")
+	switch lang {
+	case "ar", "en":
+	case "":
+		lang = "en"
+		if search.IsArabicText(query) {
+			lang = "ar"
+		}
+	default:
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("lang must be ar or en", "INVALID_LANG"))
+		return
+	}
+
+	limit := defaultCompletions
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > search.MaxCompletions {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				"limit must be between 1 and "+strconv.Itoa(search.MaxCompletions), "INVALID_LIMIT"))
+			return
+		}
+		limit = n
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	completions := cat.completer.Complete(query, limit)
+	suggestions := make([]models.AutocompleteSuggestion, len(completions))
+	for i, comp := range completions {
+		text := comp.NameEn
+		if lang == "ar" || text == "" {
+			text = comp.Name
+		}
+		if text == "" {
+			text = comp.NameEn
+		}
+		suggestions[i] = models.AutocompleteSuggestion{
+			Type:         string(comp.Kind),
+			Text:         text,
+			Name:         comp.Name,
+			NameEn:       comp.NameEn,
+			UniversityID: comp.UniversityID,
+			Count:        comp.Count,
+		}
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(suggestions, ""))
+}
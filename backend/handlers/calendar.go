@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// GetCalendar lists admission events by date, optionally narrowed by
+// ?universityId and ?faculty (catalogue ID or name), which keep nationwide
+// and university-wide events, ?kind, and the ?from and ?to dates
+func (h *Handler) GetCalendar(c *gin.Context) {
+	events, ok := h.calendar(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(events, ""))
+}
+
+// GetCalendarICS exports the events GetCalendar selects as an iCalendar
+// feed, titled in ?lang (ar or en, the default), for calendar apps to
+// subscribe to
+func (h *Handler) GetCalendarICS(c *gin.Context) {
+	lang := c.DefaultQuery("lang", "en")
+	if lang != "ar" && lang != "en" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("lang must be ar or en", "INVALID_LANG"))
+		return
+	}
+	events, ok := h.calendar(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="admissions.ics"`)
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := data.WriteICalendar(c.Writer, events, lang, time.Now()); err != nil {
+		internalError(c, err)
+	}
+}
+
+// calendar selects the events of the request's filters, writing an error
+// response when they are invalid
+func (h *Handler) calendar(c *gin.Context) ([]models.CalendarEvent, bool) {
+	var query data.CalendarQuery
+
+	if id := c.Query("universityId"); id != "" {
+		if _, ok := h.loadUniversity(c, id); !ok {
+			return nil, false
+		}
+		query.UniversityID = id
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return nil, false
+	}
+	if faculty := strings.TrimSpace(c.Query("faculty")); faculty != "" {
+		query.FacultyID = faculty
+		if d, found := cat.faculties.Resolve(faculty, faculty); found {
+			query.FacultyID = d.ID
+		}
+	}
+	if kind := c.Query("kind"); kind != "" {
+		if !models.IsValidEventKind(kind) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid event kind", "INVALID_KIND",
+				gin.H{"kinds": models.EventKinds}))
+			return nil, false
+		}
+		query.Kind = kind
+	}
+	for _, bound := range []struct {
+		param string
+		dest  *string
+	}{{"from", &query.From}, {"to", &query.To}} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(bound.param+" must be a date in YYYY-MM-DD form", "INVALID_DATE"))
+			return nil, false
+		}
+		*bound.dest = raw
+	}
+
+	events, err := h.store.ListEvents()
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return data.Calendar(events, cat.universities, cat.faculties, query), true
+}
+
+// PutEvent creates or replaces the admission event :id. Its university and
+// faculty, when given, must be in the catalogue.
+func (h *Handler) PutEvent(c *gin.Context) {
+	var event models.AdmissionEvent
+	if !bindStrict(c, &event) {
+		return
+	}
+	id := c.Param("id")
+	if event.ID == "" {
+		event.ID = id
+	}
+	if event.ID != id {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Body ID does not match URL", "ID_MISMATCH"))
+		return
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+	if err := data.CheckAdmissionEvent(event, cat.universities, cat.faculties); err != nil {
+		respondValidation(c, err)
+		return
+	}
+
+	events, err := h.store.ListEvents()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	existed := false
+	for _, e := range events {
+		existed = existed || e.ID == id
+	}
+	if err := h.store.PutEvents([]models.AdmissionEvent{event}); err != nil {
+		internalError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if !existed {
+		status = http.StatusCreated
+	}
+	c.JSON(status, models.NewSuccessResponse(event, ""))
+}
+
+// DeleteEvent removes the admission event :id
+func (h *Handler) DeleteEvent(c *gin.Context) {
+	deleted, err := h.store.DeleteEvent(c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Event not found", "NOT_FOUND"))
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"id": c.Param("id")}, "Event deleted"))
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+	"roadtouniversities/search"
+)
+
+// catalogTTL bounds how stale the cached catalogue may get when the store is
+// edited outside the API; writes through the API invalidate it immediately
+const catalogTTL = time.Minute
+
+// catalog is a snapshot of the universities with the search structures
+// derived from them, and the faculty catalogue
+type catalog struct {
+	universities []models.University
+	index        *search.Index
+	completer    *search.Completer
+	faculties    *data.FacultyCatalog
+	built        time.Time
+}
+
+// catalog returns the cached snapshot, rebuilding it when missing or expired.
+// It writes an error response on failure.
+func (h *Handler) catalog(c *gin.Context) (*catalog, bool) {
+	cat, err := h.loadCatalog()
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return cat, true
+}
+
+// loadCatalog returns the cached snapshot, rebuilding it when missing or
+// expired
+func (h *Handler) loadCatalog() (*catalog, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cached != nil && time.Since(h.cached.built) < catalogTTL {
+		return h.cached, nil
+	}
+
+	unis, err := h.store.ListUniversities()
+	if err != nil {
+		return nil, err
+	}
+	disciplines, err := h.store.ListDisciplines()
+	if err != nil {
+		return nil, err
+	}
+	h.cached = &catalog{
+		universities: unis,
+		index:        search.Build(unis),
+		completer:    search.BuildCompleter(unis),
+		faculties:    data.NewFacultyCatalog(disciplines),
+		built:        time.Now(),
+	}
+	return h.cached, nil
+}
+
+// invalidateCatalog drops the cached snapshot after a write and has saved
+// searches checked against the new data
+func (h *Handler) invalidateCatalog() {
+	h.mu.Lock()
+	h.cached = nil
+	h.mu.Unlock()
+	h.watcher.schedule()
+}
+
+// stored returns the catalogue's copies of unis, in order, with their fees as
+// stored rather than converted or discounted
+func (cat *catalog) stored(unis []models.University) []models.University {
+	byID := make(map[string]models.University, len(cat.universities))
+	for _, uni := range cat.universities {
+		byID[uni.ID] = uni
+	}
+	result := make([]models.University, len(unis))
+	for i, uni := range unis {
+		result[i] = byID[uni.ID]
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// CompareUniversities sets the universities in ?ids=1,4,5 side by side
+func (h *Handler) CompareUniversities(c *gin.Context) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Duplicate university ID "+id, "INVALID_IDS"))
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) < data.MinCompared || len(ids) > data.MaxCompared {
+		msg := fmt.Sprintf("Between %d and %d university IDs are required", data.MinCompared, data.MaxCompared)
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(msg, "INVALID_IDS"))
+		return
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	all, ok := h.inCurrency(c, cat.universities, models.CurrencyEGP)
+	if !ok {
+		return
+	}
+	byID := make(map[string]models.University, len(all))
+	for _, uni := range all {
+		byID[uni.ID] = uni
+	}
+	unis := make([]models.University, 0, len(ids))
+	var missing []string
+	for _, id := range ids {
+		uni, found := byID[id]
+		if !found {
+			missing = append(missing, id)
+			continue
+		}
+		unis = append(unis, uni)
+	}
+	if len(missing) > 0 {
+		c.JSON(http.StatusNotFound, models.NewErrorResponseWithDetails("University not found", "NOT_FOUND", gin.H{"ids": missing}))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(data.CompareUniversities(unis, cat.faculties), ""))
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// EstimateCost returns the year-by-year and total cost of a program in EGP,
+// with USD figures for tuition billed in dollars or on request, less the
+// best eligible scholarship when asked
+func (h *Handler) EstimateCost(c *gin.Context) {
+	var req models.CostEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+
+	uni, found, err := h.store.GetUniversity(req.UniversityID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("University not found", "NOT_FOUND"))
+		return
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	var scholarships []models.Scholarship
+	if req.ApplyScholarships {
+		if scholarships, err = h.store.ListScholarships(); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	est, err := data.EstimateCost(uni, cat.faculties, scholarships, h.rates, time.Now(), req)
+	switch {
+	case errors.Is(err, data.ErrInvalidCostRequest):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_REQUEST"))
+		return
+	case errors.Is(err, data.ErrUnknownProgram):
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error(), "NOT_FOUND"))
+		return
+	case errors.Is(err, data.ErrNoExchangeRate):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "NO_EXCHANGE_RATE"))
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(est, ""))
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/models"
+)
+
+// inCurrency returns unis with every fee converted to annual amounts in the
+// ?currency= of the request, or in fallback when it has none. An empty
+// currency leaves the fees as stored. It writes an error response on
+// failure.
+func (h *Handler) inCurrency(c *gin.Context, unis []models.University, fallback string) ([]models.University, bool) {
+	currency, ok := h.requestCurrency(c, fallback)
+	if !ok {
+		return nil, false
+	}
+	return h.convert(c, unis, currency)
+}
+
+// requestCurrency returns the ?currency= of the request, or fallback. It
+// writes an error response for a currency without an exchange rate.
+func (h *Handler) requestCurrency(c *gin.Context, fallback string) (string, bool) {
+	currency := strings.ToUpper(strings.TrimSpace(c.DefaultQuery("currency", fallback)))
+	if currency != "" && !h.rates.Known(currency, time.Now()) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Unknown currency "+currency, "INVALID_CURRENCY",
+			gin.H{"currencies": h.rates.Currencies(time.Now())}))
+		return "", false
+	}
+	return currency, true
+}
+
+// convert returns unis with fees in currency, or unchanged when currency is
+// empty. It writes an error response on failure.
+func (h *Handler) convert(c *gin.Context, unis []models.University, currency string) ([]models.University, bool) {
+	if currency == "" {
+		return unis, true
+	}
+	converted, err := h.rates.ConvertUniversities(unis, currency, time.Now())
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return converted, true
+}
+
+// convertAmount converts a whole amount between currencies at today's rates
+func (h *Handler) convertAmount(amount int, from, to string) (int, error) {
+	v, err := h.rates.Convert(float64(amount), from, to, time.Now())
+	return int(math.Round(v)), err
+}
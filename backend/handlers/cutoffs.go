@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// GetUniversityCutoffs returns the cut-off history of a university's
+// faculties, optionally narrowed by ?track and ?year
+func (h *Handler) GetUniversityCutoffs(c *gin.Context) {
+	query, ok := cutoffQuery(c)
+	if !ok {
+		return
+	}
+	query.UniversityID = c.Param("id")
+
+	if _, ok := h.loadUniversity(c, query.UniversityID); !ok {
+		return
+	}
+	h.respondCutoffs(c, query)
+}
+
+// GetFacultyCutoffs returns the cut-off history of a faculty across
+// universities, optionally narrowed by ?track and ?year
+func (h *Handler) GetFacultyCutoffs(c *gin.Context) {
+	query, ok := cutoffQuery(c)
+	if !ok {
+		return
+	}
+	query.FacultyID = c.Param("id")
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+	if _, found := data.GetFacultyByID(cat.universities, cat.faculties, query.FacultyID); !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Faculty not found", "NOT_FOUND"))
+		return
+	}
+	h.respondCutoffs(c, query)
+}
+
+func (h *Handler) respondCutoffs(c *gin.Context, query data.CutoffQuery) {
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	cutoffs, err := h.store.ListCutoffs()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	series := data.CutoffHistory(cutoffs, unis, query)
+	if series == nil {
+		series = []models.CutoffSeries{}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(series, ""))
+}
+
+// cutoffQuery parses the ?track and ?year filters, writing a 400 when they
+// are invalid
+func cutoffQuery(c *gin.Context) (data.CutoffQuery, bool) {
+	var query data.CutoffQuery
+
+	if track := c.Query("track"); track != "" {
+		if !models.IsValidTrack(track) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid track", "INVALID_TRACK"))
+			return query, false
+		}
+		query.Track = track
+	}
+	if raw := c.Query("year"); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil || year <= 0 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid year", "INVALID_YEAR"))
+			return query, false
+		}
+		query.Year = year
+	}
+	return query, true
+}
+
+// ImportCutoffs adds or replaces cut-offs from a CSV request body with the
+// columns university_id, faculty, faculty_en, track, gender, year,
+// percentage, score and max_score. The whole file is rejected when any row is invalid.
+func (h *Handler) ImportCutoffs(c *gin.Context) {
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	cutoffs, err := data.ImportCutoffs(c.Request.Body, "request body", unis, cat.faculties)
+	var seedErrs data.SeedErrors
+	if errors.As(err, &seedErrs) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid cut-off data", "VALIDATION_ERROR", seedErrs))
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	if err := h.store.PutCutoffs(cutoffs); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"imported": len(cutoffs)}, "Cut-offs imported"))
+}
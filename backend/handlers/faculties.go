@@ -8,18 +8,35 @@ import (
 	"roadtouniversities/models"
 )
 
-// GetAllFaculties returns all faculties
-func GetAllFaculties(c *gin.Context) {
-	faculties := data.GetAllFaculties()
+// GetAllFaculties returns the faculty catalogue with the number of
+// universities offering each faculty
+func (h *Handler) GetAllFaculties(c *gin.Context) {
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	faculties := data.GetAllFaculties(cat.universities, cat.faculties)
 	response := models.NewSuccessResponse(faculties, "")
 	c.JSON(http.StatusOK, response)
 }
 
-// GetFacultyByID returns a single faculty by ID
-func GetFacultyByID(c *gin.Context) {
+// GetFacultyByID returns a faculty with every university offering it, their
+// fees and departments
+func (h *Handler) GetFacultyByID(c *gin.Context) {
 	id := c.Param("id")
 	
-	faculty, found := data.GetFacultyByID(id)
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+	
+	unis, ok := h.inCurrency(c, cat.universities, models.CurrencyEGP)
+	if !ok {
+		return
+	}
+	
+	faculty, found := data.GetFacultyByID(unis, cat.faculties, id)
 	if !found {
 		c.JSON(http.StatusNotFound, models.NewErrorResponse("Faculty not found", "NOT_FOUND"))
 		return
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+	"roadtouniversities/notify"
+)
+
+// Handler serves the API routes from a data store
+type Handler struct {
+	store    data.Store
+	weights  models.RecommendationWeights
+	rates    *data.ExchangeRates
+	tokens   *auth.Issuer
+	notifier notify.Notifier
+	watcher  *searchWatcher
+	abuse    *data.AbuseFilter
+
+	mu     sync.Mutex
+	cached *catalog
+	// writeMu serialises writes to universities, which read the stored
+	// record, change it and store it whole, and the assignment of IDs
+	writeMu sync.Mutex
+}
+
+// Options holds the settings handlers take from the server configuration
+type Options struct {
+	// RecommendationWeights defaults to data.DefaultRecommendationWeights
+	RecommendationWeights models.RecommendationWeights
+	// Rates converts fees between currencies; only EGP is known without it
+	Rates *data.ExchangeRates
+	// Tokens issues account tokens; without it a random secret is used, so
+	// tokens do not outlive the process
+	Tokens *auth.Issuer
+	// Notifier tells users their saved searches changed; defaults to
+	// logging the changes
+	Notifier notify.Notifier
+	// AbuseFilter flags submitted reviews for moderators; without it only
+	// personal data and duplicates are flagged
+	AbuseFilter *data.AbuseFilter
+}
+
+// New creates a Handler backed by store
+func New(store data.Store, opts Options) *Handler {
+	h := &Handler{store: store, weights: opts.RecommendationWeights, rates: opts.Rates, tokens: opts.Tokens, notifier: opts.Notifier,
+		abuse: opts.AbuseFilter}
+	if h.weights == nil {
+		h.weights = data.DefaultRecommendationWeights()
+	}
+	if h.rates == nil {
+		h.rates = data.NewExchangeRates(nil)
+	}
+	if h.tokens == nil {
+		secret, err := auth.RandomString(32)
+		if err != nil {
+			panic(err)
+		}
+		h.tokens = auth.NewIssuer([]byte(secret), 0, 0)
+	}
+	if h.notifier == nil {
+		h.notifier = notify.Log{}
+	}
+	if h.abuse == nil {
+		h.abuse = data.NewAbuseFilter(nil)
+	}
+	h.watcher = newSearchWatcher(h.checkSavedSearches)
+	return h
+}
+
+// universities loads the catalogue, writing an error response on failure
+func (h *Handler) universities(c *gin.Context) ([]models.University, bool) {
+	unis, err := h.store.ListUniversities()
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return unis, true
+}
+
+// internalError logs err and responds with a generic 500
+func internalError(c *gin.Context, err error) {
+	log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Internal server error", "INTERNAL_ERROR"))
+}
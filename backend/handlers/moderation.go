@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// ListModerationQueue returns reviews for moderators, oldest submission or
+// edit first: pending ones by default, or those in ?status. ?flag narrows
+// them to one flag kind, any (flagged at all) or none, and ?universityId to
+// one university.
+func (h *Handler) ListModerationQueue(c *gin.Context) {
+	filter := data.ReviewFilter{Status: models.ReviewPending, UniversityID: c.Query("universityId")}
+	if status := c.Query("status"); status != "" {
+		if !models.IsValidReviewStatus(status) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid review status", "INVALID_STATUS",
+				gin.H{"statuses": models.ReviewStatuses}))
+			return
+		}
+		filter.Status = status
+	}
+	flag := c.Query("flag")
+	if flag != "" && flag != "any" && flag != "none" && !models.IsValidFlagKind(flag) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Invalid flag", "INVALID_FLAG",
+			gin.H{"flags": append([]string{"any", "none"}, models.FlagKinds...)}))
+		return
+	}
+
+	reviews, err := h.store.ListReviews(filter)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	users, err := h.usersByID()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	queue := []models.ModerationEntry{}
+	for _, r := range reviews {
+		if hasFlag(r.Flags, flag) {
+			queue = append(queue, moderationEntry(r, users))
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].UpdatedAt.Before(queue[j].UpdatedAt) })
+	c.JSON(http.StatusOK, models.NewSuccessResponse(queue, ""))
+}
+
+// GetModerationReview returns a review as moderators see it, with its
+// moderation history
+func (h *Handler) GetModerationReview(c *gin.Context) {
+	review, ok := h.loadReview(c)
+	if !ok {
+		return
+	}
+	users, err := h.usersByID()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	actions, err := h.store.ListModerationActions(data.ModerationFilter{ReviewID: review.ID})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	entry := moderationEntry(review, users)
+	entry.History = moderationActionEntries(actions, users)
+	c.JSON(http.StatusOK, models.NewSuccessResponse(entry, ""))
+}
+
+// ApproveReview publishes a review, with an optional reason
+func (h *Handler) ApproveReview(c *gin.Context) {
+	h.moderateReview(c, models.ModerationApprove, models.ReviewApproved)
+}
+
+// RejectReview withdraws a review or keeps it from being published; the
+// reason is required and shown to its author
+func (h *Handler) RejectReview(c *gin.Context) {
+	h.moderateReview(c, models.ModerationReject, models.ReviewRejected)
+}
+
+// ListModerationActions returns the moderation audit trail, newest first,
+// optionally narrowed to one ?reviewId or ?moderatorId
+func (h *Handler) ListModerationActions(c *gin.Context) {
+	actions, err := h.store.ListModerationActions(data.ModerationFilter{
+		ReviewID:    c.Query("reviewId"),
+		ModeratorID: c.Query("moderatorId"),
+	})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	users, err := h.usersByID()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	entries := moderationActionEntries(actions, users)
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(entries, ""))
+}
+
+// moderateReview moves the review :id to status, recording the decision in
+// the audit trail under the current user, and updates the rating of its
+// university when it is published or withdrawn. A review edited or
+// moderated since the moderator read it is left alone with a 409.
+func (h *Handler) moderateReview(c *gin.Context, action, status string) {
+	var req models.ModerationRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	if err := data.ValidateModerationRequest(action, req); err != nil {
+		respondValidation(c, err)
+		return
+	}
+	review, ok := h.loadReview(c)
+	if !ok {
+		return
+	}
+	if !review.UpdatedAt.Equal(req.UpdatedAt) {
+		reviewChanged(c, review)
+		return
+	}
+	if review.Status == status {
+		c.JSON(http.StatusConflict, models.NewErrorResponse("Review is already "+status, "ALREADY_MODERATED"))
+		return
+	}
+
+	id, err := auth.RandomString(8)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	moderator, _ := CurrentUser(c)
+	entry := models.ModerationAction{
+		ID:             id,
+		ReviewID:       review.ID,
+		UniversityID:   review.UniversityID,
+		ModeratorID:    moderator.ID,
+		Action:         action,
+		PreviousStatus: review.Status,
+		Status:         status,
+		Reason:         strings.TrimSpace(req.Reason),
+		Flags:          review.Flags,
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	changesRating := review.Status == models.ReviewApproved || status == models.ReviewApproved
+	review.Status, review.StatusReason = status, entry.Reason
+	err = h.store.ModerateReview(review, entry)
+	if errors.Is(err, data.ErrReviewChanged) {
+		// Edited, moderated or deleted since it was loaded above
+		if current, found, err := h.store.GetReview(review.ID); err == nil && found {
+			reviewChanged(c, current)
+			return
+		}
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Review not found", "NOT_FOUND"))
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if changesRating {
+		if err := h.refreshRatings(); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(moderationEntry(review, nil), "Review "+status))
+}
+
+// reviewChanged writes the 409 for a decision on an outdated version of a
+// review, with the current one to read again
+func reviewChanged(c *gin.Context, current models.Review) {
+	c.JSON(http.StatusConflict, models.NewErrorResponseWithDetails("Review changed since it was read", "REVIEW_CHANGED",
+		gin.H{"updatedAt": current.UpdatedAt, "status": current.Status}))
+}
+
+// loadReview fetches the review :id, writing a 404 when it does not exist
+func (h *Handler) loadReview(c *gin.Context) (models.Review, bool) {
+	review, found, err := h.store.GetReview(c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return models.Review{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Review not found", "NOT_FOUND"))
+		return models.Review{}, false
+	}
+	return review, true
+}
+
+// usersByID indexes every account by ID
+func (h *Handler) usersByID() (map[string]models.User, error) {
+	users, err := h.store.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	return byID, nil
+}
+
+// hasFlag reports whether flags pass a ?flag filter
+func hasFlag(flags []models.ReviewFlag, filter string) bool {
+	switch filter {
+	case "":
+		return true
+	case "any":
+		return len(flags) > 0
+	case "none":
+		return len(flags) == 0
+	}
+	for _, f := range flags {
+		if f.Kind == filter {
+			return true
+		}
+	}
+	return false
+}
+
+func moderationEntry(r models.Review, users map[string]models.User) models.ModerationEntry {
+	entry := models.ModerationEntry{Review: r, Flags: r.Flags}
+	if entry.Flags == nil {
+		entry.Flags = []models.ReviewFlag{}
+	}
+	if u, found := users[r.UserID]; found {
+		entry.Author = &u
+	}
+	return entry
+}
+
+func moderationActionEntries(actions []models.ModerationAction, users map[string]models.User) []models.ModerationActionEntry {
+	entries := make([]models.ModerationActionEntry, len(actions))
+	for i, a := range actions {
+		if a.Flags == nil {
+			a.Flags = []models.ReviewFlag{}
+		}
+		entries[i] = models.ModerationActionEntry{ModerationAction: a}
+		if u, found := users[a.ModeratorID]; found {
+			entries[i].Moderator = &u
+		}
+	}
+	return entries
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// reviewServer routes the review and moderation endpoints like main.go
+func reviewServer(store data.Store, tokens *auth.Issuer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := New(store, Options{Tokens: tokens})
+	r := gin.New()
+	v1 := r.Group("", Authenticate(tokens))
+	v1.POST("/universities/:id/reviews", RequireRole(), h.CreateReview)
+	v1.PUT("/me/reviews/:id", RequireRole(), h.ReplaceMyReview)
+	v1.DELETE("/me/reviews/:id", RequireRole(), h.DeleteMyReview)
+	moderation := v1.Group("/moderation", RequireRole(models.RoleModerator))
+	moderation.GET("/reviews/:id", h.GetModerationReview)
+	moderation.POST("/reviews/:id/approve", h.ApproveReview)
+	moderation.POST("/reviews/:id/reject", h.RejectReview)
+	return r
+}
+
+// send sends a JSON request as user, decoding the response data into out
+// when given
+func send(t *testing.T, r http.Handler, tokens *auth.Issuer, user models.User, method, path string, body, out any) int {
+	t.Helper()
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	token, err := tokens.IssueAccess(user, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if out != nil {
+		resp := models.APIResponse[json.RawMessage]{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			t.Fatalf("%s %s: data: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w.Code
+}
+
+// moderationFixture stores two universities, two students and a moderator
+func moderationFixture(t *testing.T) (data.Store, []models.User) {
+	t.Helper()
+	store := data.NewMemoryStore()
+	for _, uni := range []models.University{
+		{ID: "minia", Name: "جامعة المنيا", NameEn: "Minia University", Rating: 3.9, EditorialRating: 3.9},
+		{ID: "assiut", Name: "جامعة أسيوط", NameEn: "Assiut University", Rating: 4.2, EditorialRating: 4.2},
+	} {
+		if err := store.PutUniversity(uni); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users := []models.User{
+		{ID: "s1", Email: "s1@example.com", Role: models.RoleStudent},
+		{ID: "s2", Email: "s2@example.com", Role: models.RoleStudent},
+		{ID: "m1", Email: "m1@example.com", Role: models.RoleModerator},
+	}
+	for _, u := range users {
+		if err := store.CreateUser(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, users
+}
+
+func universityRating(t *testing.T, store data.Store, id string) float64 {
+	t.Helper()
+	uni, found, err := store.GetUniversity(id)
+	if err != nil || !found {
+		t.Fatalf("GetUniversity(%s) = %v, %v", id, found, err)
+	}
+	return uni.Rating
+}
+
+// reviewFlags returns the stored flags of a review, which its author does
+// not see
+func reviewFlags(t *testing.T, store data.Store, id string) []models.ReviewFlag {
+	t.Helper()
+	review, found, err := store.GetReview(id)
+	if err != nil || !found {
+		t.Fatalf("GetReview(%s) = %v, %v", id, found, err)
+	}
+	return review.Flags
+}
+
+func TestModerateReview(t *testing.T) {
+	store, users := moderationFixture(t)
+	student, moderator := users[0], users[2]
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	r := reviewServer(store, tokens)
+
+	var review models.Review
+	status := send(t, r, tokens, student, http.MethodPost, "/universities/minia/reviews",
+		models.ReviewRequest{Overall: 5, Body: "Great lecturers; call 0101 234 5678 for my notes."}, &review)
+	if status != http.StatusCreated || review.Status != models.ReviewPending {
+		t.Fatalf("create = %d %+v, want 201 pending", status, review)
+	}
+	want := []models.ReviewFlag{{Kind: models.FlagPersonalData, Detail: "phone number"}}
+	if flags := reviewFlags(t, store, review.ID); !reflect.DeepEqual(flags, want) {
+		t.Errorf("flags = %+v, want %+v", flags, want)
+	}
+	if status := send(t, r, tokens, student, http.MethodPost, "/universities/minia/reviews",
+		models.ReviewRequest{Overall: 4, Body: "A second review of the same university."}, nil); status != http.StatusConflict {
+		t.Errorf("second review = %d, want 409", status)
+	}
+
+	path := "/moderation/reviews/" + review.ID
+	seen := models.ModerationRequest{UpdatedAt: review.UpdatedAt}
+	if status := send(t, r, tokens, student, http.MethodPost, path+"/approve", seen, nil); status != http.StatusForbidden {
+		t.Errorf("approve as a student = %d, want 403", status)
+	}
+	if status := send(t, r, tokens, moderator, http.MethodPost, path+"/reject", seen, nil); status != http.StatusBadRequest {
+		t.Errorf("reject without a reason = %d, want 400", status)
+	}
+	if status := send(t, r, tokens, moderator, http.MethodPost, path+"/approve", models.ModerationRequest{}, nil); status != http.StatusBadRequest {
+		t.Errorf("approve without updatedAt = %d, want 400", status)
+	}
+
+	// Approving moves the editorial 3.9 towards the review; rejecting it
+	// again restores the editorial rating
+	if status := send(t, r, tokens, moderator, http.MethodPost, path+"/approve", seen, nil); status != http.StatusOK {
+		t.Fatalf("approve = %d, want 200", status)
+	}
+	if got := universityRating(t, store, "minia"); got != 4.1 {
+		t.Errorf("rating after approval = %g, want 4.1", got)
+	}
+	if status := send(t, r, tokens, moderator, http.MethodPost, path+"/approve", seen, nil); status != http.StatusConflict {
+		t.Errorf("second approval = %d, want 409", status)
+	}
+	status = send(t, r, tokens, moderator, http.MethodPost, path+"/reject",
+		models.ModerationRequest{Reason: "Contains a phone number", UpdatedAt: review.UpdatedAt}, &review)
+	if status != http.StatusOK || review.Status != models.ReviewRejected || review.StatusReason != "Contains a phone number" {
+		t.Fatalf("reject = %d %+v, want 200 rejected with the reason", status, review)
+	}
+	if got := universityRating(t, store, "minia"); got != 3.9 {
+		t.Errorf("rating after rejection = %g, want 3.9", got)
+	}
+
+	var entry models.ModerationEntry
+	if status := send(t, r, tokens, moderator, http.MethodGet, path, nil, &entry); status != http.StatusOK {
+		t.Fatalf("get = %d, want 200", status)
+	}
+	if len(entry.History) != 2 || entry.History[0].Action != models.ModerationApprove ||
+		entry.History[1].Action != models.ModerationReject || entry.History[1].Moderator == nil ||
+		entry.History[1].Moderator.ID != moderator.ID || len(entry.History[1].Flags) != 1 {
+		t.Errorf("history = %+v, want the approval then the rejection by %s with the flags", entry.History, moderator.ID)
+	}
+	if entry.Author == nil || entry.Author.ID != student.ID {
+		t.Errorf("author = %+v, want %s", entry.Author, student.ID)
+	}
+}
+
+func TestDeletingApprovedReviewRestoresRating(t *testing.T) {
+	store, users := moderationFixture(t)
+	student, moderator := users[0], users[2]
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	r := reviewServer(store, tokens)
+
+	var review models.Review
+	send(t, r, tokens, student, http.MethodPost, "/universities/minia/reviews",
+		models.ReviewRequest{Overall: 5, Body: "Excellent teaching hospital and labs."}, &review)
+	send(t, r, tokens, moderator, http.MethodPost, "/moderation/reviews/"+review.ID+"/approve",
+		models.ModerationRequest{UpdatedAt: review.UpdatedAt}, nil)
+	if got := universityRating(t, store, "minia"); got != 4.1 {
+		t.Fatalf("rating after approval = %g, want 4.1", got)
+	}
+
+	if status := send(t, r, tokens, student, http.MethodDelete, "/me/reviews/"+review.ID, nil, nil); status != http.StatusOK {
+		t.Fatalf("delete = %d, want 200", status)
+	}
+	if got := universityRating(t, store, "minia"); got != 3.9 {
+		t.Errorf("rating after the review is deleted = %g, want 3.9", got)
+	}
+}
+
+func TestModerateChangedReview(t *testing.T) {
+	store, users := moderationFixture(t)
+	student, moderator := users[0], users[2]
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	r := reviewServer(store, tokens)
+
+	var review models.Review
+	send(t, r, tokens, student, http.MethodPost, "/universities/minia/reviews",
+		models.ReviewRequest{Overall: 4, Body: "Good labs and helpful teaching assistants."}, &review)
+	var read models.ModerationEntry
+	send(t, r, tokens, moderator, http.MethodGet, "/moderation/reviews/"+review.ID, nil, &read)
+	seen := models.ModerationRequest{UpdatedAt: read.UpdatedAt}
+
+	// The author edits the review after the moderator read it
+	var edited models.Review
+	status := send(t, r, tokens, student, http.MethodPut, "/me/reviews/"+review.ID,
+		models.ReviewRequest{Overall: 1, Body: "Edited after the moderator read the review."}, &edited)
+	if status != http.StatusOK || !edited.UpdatedAt.After(read.UpdatedAt) {
+		t.Fatalf("edit = %d, updatedAt %v, want 200 after %v", status, edited.UpdatedAt, read.UpdatedAt)
+	}
+	if status := send(t, r, tokens, moderator, http.MethodPost, "/moderation/reviews/"+review.ID+"/approve", seen, nil); status != http.StatusConflict {
+		t.Errorf("approving the version read = %d, want 409", status)
+	}
+	if got, _, _ := store.GetReview(review.ID); got.Status != models.ReviewPending {
+		t.Errorf("status after the stale approval = %s, want pending", got.Status)
+	}
+
+	// Or deletes it
+	if status := send(t, r, tokens, student, http.MethodDelete, "/me/reviews/"+review.ID, nil, nil); status != http.StatusOK {
+		t.Fatalf("delete = %d, want 200", status)
+	}
+	seen.UpdatedAt = edited.UpdatedAt
+	if status := send(t, r, tokens, moderator, http.MethodPost, "/moderation/reviews/"+review.ID+"/approve", seen, nil); status != http.StatusNotFound {
+		t.Errorf("approving a deleted review = %d, want 404", status)
+	}
+	if _, found, _ := store.GetReview(review.ID); found {
+		t.Error("approving brought a deleted review back")
+	}
+}
+
+func TestReviewDuplicateFlags(t *testing.T) {
+	store, users := moderationFixture(t)
+	first, second := users[0], users[1]
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	r := reviewServer(store, tokens)
+
+	const body = "Crowded lecture halls but very good professors."
+	var original models.Review
+	send(t, r, tokens, first, http.MethodPost, "/universities/minia/reviews", models.ReviewRequest{Overall: 4, Body: body}, &original)
+	want := models.ReviewFlag{Kind: models.FlagDuplicate, Detail: "same text as review " + original.ID}
+
+	tests := []struct {
+		name       string
+		user       models.User
+		university string
+		flagged    bool
+	}{
+		{"same author at another university", first, "assiut", true},
+		{"another author at the same university", second, "minia", true},
+		{"another author at another university", second, "assiut", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each case starts from the original review alone
+			reviews, err := store.ListReviews(data.ReviewFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, rv := range reviews {
+				if rv.ID != original.ID {
+					if _, err := store.DeleteReview(rv.ID); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			var review models.Review
+			status := send(t, r, tokens, tt.user, http.MethodPost, "/universities/"+tt.university+"/reviews",
+				models.ReviewRequest{Overall: 4, Body: body}, &review)
+			if status != http.StatusCreated {
+				t.Fatalf("create = %d, want 201", status)
+			}
+			flags := reviewFlags(t, store, review.ID)
+			if flagged := len(flags) == 1 && flags[0] == want; flagged != tt.flagged {
+				t.Errorf("flags = %+v, want duplicate flag %v", flags, tt.flagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// SearchPrograms handles program-level search over faculty departments
+func (h *Handler) SearchPrograms(c *gin.Context) {
+	var params models.ProgramSearchParams
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+
+	// Set defaults
+	params.Page, params.PageSize = pageDefaults(params.Page, params.PageSize)
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	unis, ok := h.inCurrency(c, cat.universities, models.CurrencyEGP)
+	if !ok {
+		return
+	}
+
+	programs, err := data.SearchPrograms(unis, params)
+	switch {
+	case errors.Is(err, data.ErrInvalidSort):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_SORT"))
+		return
+	case errors.Is(err, data.ErrInvalidBackground):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_BACKGROUND"))
+		return
+	case errors.Is(err, data.ErrInvalidDegreeType):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_DEGREE_TYPE"))
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	// Apply pagination
+	page, totalPages := paginate(programs, params.Page, params.PageSize)
+	if page == nil {
+		page = []models.Program{}
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(models.ProgramSearchResponse{
+		Programs:   page,
+		Total:      len(programs),
+		Query:      params.SearchQuery,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, ""))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// Recommend ranks the universities matching the student's profile, with a
+// per-factor breakdown of each score
+func (h *Handler) Recommend(c *gin.Context) {
+	var req models.RecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	unis, ok := h.inCurrency(c, cat.universities, models.CurrencyEGP)
+	if !ok {
+		return
+	}
+
+	resp, err := data.Recommend(unis, cat.faculties, h.weights, req)
+	switch {
+	case errors.Is(err, data.ErrInvalidPercentage):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_PERCENTAGE"))
+		return
+	case errors.Is(err, data.ErrInvalidBackground):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_CERTIFICATE"))
+		return
+	case errors.Is(err, data.ErrInvalidWeights):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_WEIGHTS"))
+		return
+	case errors.Is(err, data.ErrInvalidProfile):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_PROFILE"))
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(resp, ""))
+}
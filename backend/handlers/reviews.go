@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// GetUniversityReviews returns a page of the approved reviews of a
+// university with a summary of them, optionally narrowed to one ?faculty
+// (catalogue ID or name) and ordered by ?sort: newest (the default), oldest,
+// highest or lowest
+func (h *Handler) GetUniversityReviews(c *gin.Context) {
+	uni, ok := h.loadUniversity(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	page, pageSize, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	approved, err := h.store.ListReviews(data.ReviewFilter{Status: models.ReviewApproved})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	var reviews []models.Review
+	for _, r := range approved {
+		if r.UniversityID == uni.ID {
+			reviews = append(reviews, r)
+		}
+	}
+	if faculty := strings.TrimSpace(c.Query("faculty")); faculty != "" {
+		cat, ok := h.catalog(c)
+		if !ok {
+			return
+		}
+		if d, found := cat.faculties.Resolve(faculty, faculty); found {
+			faculty = d.ID
+		}
+		kept := reviews[:0]
+		for _, r := range reviews {
+			if r.Faculty == faculty {
+				kept = append(kept, r)
+			}
+		}
+		reviews = kept
+	}
+	if err := data.SortReviews(reviews, c.Query("sort")); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_SORT"))
+		return
+	}
+
+	onPage, totalPages := paginate(reviews, page, pageSize)
+	// Reasons for approving are notes between moderators and authors
+	published := make([]models.Review, len(onPage))
+	for i, r := range onPage {
+		r.StatusReason = ""
+		published[i] = r
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(models.ReviewsResponse{
+		Reviews:    published,
+		Total:      len(reviews),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Summary:    data.SummarizeReviews(reviews, data.ReviewPrior(uni.EditorialRating, approved)),
+	}, ""))
+}
+
+// CreateReview submits a review of a university by the current user. It
+// awaits moderation before it is published. Each user reviews a university,
+// and each of its faculties, once.
+func (h *Handler) CreateReview(c *gin.Context) {
+	var req models.ReviewRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	uni, ok := h.loadUniversity(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	id, err := auth.RandomString(8)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	user, _ := CurrentUser(c)
+	now := time.Now().UTC().Truncate(time.Second)
+	review := models.Review{ID: id, UserID: user.ID, UniversityID: uni.ID, CreatedAt: now}
+	if review, ok := h.saveReview(c, uni, review, req); ok {
+		c.JSON(http.StatusCreated, models.NewSuccessResponse(review, "Review submitted for moderation"))
+	}
+}
+
+// ListMyReviews returns the current user's reviews in every moderation state
+func (h *Handler) ListMyReviews(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	reviews, err := h.store.ListReviews(data.ReviewFilter{UserID: user.ID})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if reviews == nil {
+		reviews = []models.Review{}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(reviews, ""))
+}
+
+// ReplaceMyReview edits one of the current user's reviews, which goes back
+// to moderation
+func (h *Handler) ReplaceMyReview(c *gin.Context) {
+	var req models.ReviewRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	review, ok := h.loadMyReview(c)
+	if !ok {
+		return
+	}
+	uni, ok := h.loadUniversity(c, review.UniversityID)
+	if !ok {
+		return
+	}
+
+	wasApproved := review.Status == models.ReviewApproved
+	saved, ok := h.saveReview(c, uni, review, req)
+	if !ok {
+		return
+	}
+	if wasApproved {
+		if err := h.refreshRatings(); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(saved, "Review resubmitted for moderation"))
+}
+
+// DeleteMyReview removes one of the current user's reviews
+func (h *Handler) DeleteMyReview(c *gin.Context) {
+	review, ok := h.loadMyReview(c)
+	if !ok {
+		return
+	}
+	if _, err := h.store.DeleteReview(review.ID); err != nil {
+		internalError(c, err)
+		return
+	}
+	if review.Status == models.ReviewApproved {
+		if err := h.refreshRatings(); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"id": review.ID}, "Review deleted"))
+}
+
+// loadMyReview fetches the current user's review :id, writing a 404 when it
+// does not exist
+func (h *Handler) loadMyReview(c *gin.Context) (models.Review, bool) {
+	review, ok := h.loadReview(c)
+	if !ok {
+		return models.Review{}, false
+	}
+	if user, _ := CurrentUser(c); review.UserID != user.ID {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Review not found", "NOT_FOUND"))
+		return models.Review{}, false
+	}
+	return review, true
+}
+
+// saveReview applies req to a review of uni, checks it and stores it
+// pending moderation with what the abuse filter flags, returning what was
+// stored. It writes an error response on failure.
+func (h *Handler) saveReview(c *gin.Context, uni models.University, review models.Review, req models.ReviewRequest) (models.Review, bool) {
+	review.Overall = req.Overall
+	review.Ratings = req.Ratings
+	review.Title = strings.TrimSpace(req.Title)
+	review.Body = strings.TrimSpace(req.Body)
+	review.Status = models.ReviewPending
+	// UpdatedAt is the version moderators decide on, so it moves on with
+	// every edit, even one within the same second
+	updated := time.Now().UTC().Truncate(time.Second)
+	if !updated.After(review.UpdatedAt) {
+		updated = review.UpdatedAt.Add(time.Second)
+	}
+	review.UpdatedAt = updated
+	if err := data.ValidateReview(review); err != nil {
+		respondValidation(c, err)
+		return models.Review{}, false
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return models.Review{}, false
+	}
+	faculty, err := data.ResolveReviewFaculty(strings.TrimSpace(req.Faculty), uni, cat.faculties)
+	if err != nil {
+		respondValidation(c, err)
+		return models.Review{}, false
+	}
+	review.Faculty = faculty
+
+	mine, err := h.store.ListReviews(data.ReviewFilter{UserID: review.UserID})
+	if err != nil {
+		internalError(c, err)
+		return models.Review{}, false
+	}
+	for _, r := range mine {
+		if r.UniversityID == uni.ID && r.ID != review.ID && r.Faculty == review.Faculty {
+			c.JSON(http.StatusConflict, models.NewErrorResponseWithDetails("Already reviewed", "ALREADY_REVIEWED",
+				gin.H{"id": r.ID}))
+			return models.Review{}, false
+		}
+	}
+
+	// Duplicates are looked for where copied text does harm: in the author's
+	// other reviews and in the other reviews of the same university
+	ours, err := h.store.ListReviews(data.ReviewFilter{UniversityID: uni.ID})
+	if err != nil {
+		internalError(c, err)
+		return models.Review{}, false
+	}
+	review.StatusReason = ""
+	review.Flags = h.abuse.Check(review, append(mine, ours...))
+	if err := h.store.PutReview(review); err != nil {
+		internalError(c, err)
+		return models.Review{}, false
+	}
+	return review, true
+}
+
+// refreshRatings recomputes the rating of every university after the
+// approved reviews changed: the Bayesian average of its approved reviews, or
+// its editorial rating when it has none left. Universities without an
+// editorial rating are rated against the catalogue-wide mean, so theirs can
+// change with any review. Only the ratings are written, so an editor's
+// concurrent change to a university is kept.
+func (h *Handler) refreshRatings() error {
+	// After an editor's save, so its rating is not left stale
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	approved, err := h.store.ListReviews(data.ReviewFilter{Status: models.ReviewApproved})
+	if err != nil {
+		return err
+	}
+	unis, err := h.store.ListUniversities()
+	if err != nil {
+		return err
+	}
+	ratings := data.ReviewRatings(unis, approved)
+
+	changed := make(map[string]float64)
+	for _, uni := range unis {
+		if rating := ratings[uni.ID]; rating != uni.Rating {
+			changed[uni.ID] = rating
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if err := h.store.SetRatings(changed); err != nil {
+		return err
+	}
+	h.invalidateCatalog()
+	return nil
+}
+
+// pageQuery parses ?page and ?pageSize, writing an error response when
+// either is invalid
+func pageQuery(c *gin.Context) (page, pageSize int, ok bool) {
+	if raw := c.Query("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("page must be a positive number", "INVALID_REQUEST"))
+			return 0, 0, false
+		}
+		page = n
+	}
+	if raw := c.Query("pageSize"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				"pageSize must be between 1 and "+strconv.Itoa(maxPageSize), "INVALID_REQUEST"))
+			return 0, 0, false
+		}
+		pageSize = n
+	}
+	page, pageSize = pageDefaults(page, pageSize)
+	return page, pageSize, true
+}
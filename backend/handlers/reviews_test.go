@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+func TestGetUniversityReviewsPageSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := data.NewMemoryStore()
+	if err := store.PutUniversity(models.University{ID: "1", Name: "جامعة", NameEn: "University"}); err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.GET("/universities/:id/reviews", New(store, Options{}).GetUniversityReviews)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"?pageSize=100", http.StatusOK},
+		{"?pageSize=101", http.StatusBadRequest},
+		{"?pageSize=0", http.StatusBadRequest},
+		{"?page=0", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/universities/1/reviews"+tt.query, nil))
+		if w.Code != tt.want {
+			t.Errorf("GET reviews%s = %d, want %d: %s", tt.query, w.Code, tt.want, w.Body)
+		}
+	}
+}
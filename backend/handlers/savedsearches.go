@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// ListSavedSearches returns the current user's saved searches
+func (h *Handler) ListSavedSearches(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	searches, err := h.store.ListSavedSearches(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if searches == nil {
+		searches = []models.SavedSearch{}
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(searches, ""))
+}
+
+// GetSavedSearch returns one of the current user's saved searches
+func (h *Handler) GetSavedSearch(c *gin.Context) {
+	search, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(search, ""))
+}
+
+// CreateSavedSearch names a search for the current user, recording its
+// current results to compare later ones against
+func (h *Handler) CreateSavedSearch(c *gin.Context) {
+	var req models.SavedSearchRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+
+	user, _ := CurrentUser(c)
+	searches, err := h.store.ListSavedSearches(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if len(searches) >= data.MaxSavedSearches {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Too many saved searches", "LIMIT_REACHED",
+			gin.H{"limit": data.MaxSavedSearches}))
+		return
+	}
+
+	id, err := auth.RandomString(8)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	search := models.SavedSearch{ID: id, UserID: user.ID, CreatedAt: now}
+	if search, ok := h.saveSearch(c, search, req); ok {
+		c.JSON(http.StatusCreated, models.NewSuccessResponse(search, "Search saved"))
+	}
+}
+
+// ReplaceSavedSearch replaces the name, parameters and settings of a saved
+// search, recording its current results afresh
+func (h *Handler) ReplaceSavedSearch(c *gin.Context) {
+	var req models.SavedSearchRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	search, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	if search, ok := h.saveSearch(c, search, req); ok {
+		c.JSON(http.StatusOK, models.NewSuccessResponse(search, "Search updated"))
+	}
+}
+
+// DeleteSavedSearch removes one of the current user's saved searches
+func (h *Handler) DeleteSavedSearch(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	deleted, err := h.store.DeleteSavedSearch(user.ID, c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Saved search not found", "NOT_FOUND"))
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"id": c.Param("id")}, "Saved search deleted"))
+}
+
+// RunSavedSearch replays a saved search like POST /universities/search,
+// returning the results with what changed since it was last checked, which
+// it records. ?page overrides the saved page and ?currency the currency of
+// the results.
+func (h *Handler) RunSavedSearch(c *gin.Context) {
+	search, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+	params := search.Params
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("page must be a positive number", "INVALID_REQUEST"))
+			return
+		}
+		params.Page = page
+	}
+	params = withPageDefaults(params)
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+	currency, ok := h.requestCurrency(c, search.Currency)
+	if !ok {
+		return
+	}
+	run, err := h.runSearch(cat, params, search.Currency)
+	if err != nil {
+		searchError(c, err)
+		return
+	}
+	results, ok := h.searchResponse(c, cat, params, currency, run)
+	if !ok {
+		return
+	}
+
+	matches, err := h.searchMatches(cat, run, search.Currency)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	changes := data.DiffMatches(search.Matches, matches)
+	if _, err := h.store.SetSearchMatches(search.ID, matches, time.Now().UTC().Truncate(time.Second)); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(models.SavedSearchRun{Results: results, Changes: changes}, ""))
+}
+
+// loadSavedSearch fetches the current user's saved search :id, writing a 404
+// when it does not exist
+func (h *Handler) loadSavedSearch(c *gin.Context) (models.SavedSearch, bool) {
+	user, _ := CurrentUser(c)
+	search, found, err := h.store.GetSavedSearch(user.ID, c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return models.SavedSearch{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Saved search not found", "NOT_FOUND"))
+		return models.SavedSearch{}, false
+	}
+	return search, true
+}
+
+// saveSearch applies req to a saved search, runs it to check its parameters
+// and record its results, and stores it, returning what was stored. It
+// writes an error response on failure.
+func (h *Handler) saveSearch(c *gin.Context, search models.SavedSearch, req models.SavedSearchRequest) (models.SavedSearch, bool) {
+	search.Name = strings.TrimSpace(req.Name)
+	search.Params = req.Params
+	search.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if search.Currency == "" {
+		search.Currency = models.CurrencyEGP
+	}
+	search.Notify = req.Notify == nil || *req.Notify
+	if err := data.ValidateSavedSearch(search); err != nil {
+		respondValidation(c, err)
+		return models.SavedSearch{}, false
+	}
+	if !h.rates.Known(search.Currency, time.Now()) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Unknown currency "+search.Currency, "INVALID_CURRENCY",
+			gin.H{"currencies": h.rates.Currencies(time.Now())}))
+		return models.SavedSearch{}, false
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return models.SavedSearch{}, false
+	}
+	run, err := h.runSearch(cat, search.Params, search.Currency)
+	if err != nil {
+		searchError(c, err)
+		return models.SavedSearch{}, false
+	}
+	if search.Matches, err = h.searchMatches(cat, run, search.Currency); err != nil {
+		internalError(c, err)
+		return models.SavedSearch{}, false
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	search.UpdatedAt, search.CheckedAt = now, now
+
+	if err := h.store.PutSavedSearch(search); err != nil {
+		internalError(c, err)
+		return models.SavedSearch{}, false
+	}
+	return search, true
+}
+
+// searchMatches records the results of run with their fees in currency.
+// The fees are converted from those stored rather than taken from the
+// results, which are in EGP less any scholarships, so a university priced in
+// the currency of the search only shows a fee change when its fees are
+// edited, not when exchange rates move.
+func (h *Handler) searchMatches(cat *catalog, run searchRun, currency string) ([]models.SearchMatch, error) {
+	unis, err := h.rates.ConvertUniversities(cat.stored(run.result.Universities), currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return data.SearchMatches(unis), nil
+}
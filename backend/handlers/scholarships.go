@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// GetScholarships lists the scholarship catalogue, optionally narrowed by
+// ?universityId, ?faculty (catalogue ID or name), ?type and ?grade, which
+// keeps the scholarships whose grade threshold it meets
+func (h *Handler) GetScholarships(c *gin.Context) {
+	var query data.ScholarshipQuery
+
+	if id := c.Query("universityId"); id != "" {
+		if _, ok := h.loadUniversity(c, id); !ok {
+			return
+		}
+		query.UniversityID = id
+	}
+	if faculty := strings.TrimSpace(c.Query("faculty")); faculty != "" {
+		cat, ok := h.catalog(c)
+		if !ok {
+			return
+		}
+		query.FacultyID = faculty
+		if d, found := cat.faculties.Resolve(faculty, faculty); found {
+			query.FacultyID = d.ID
+		}
+	}
+	if t := c.Query("type"); t != "" {
+		if !models.IsValidScholarshipType(t) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid scholarship type", "INVALID_TYPE"))
+			return
+		}
+		query.Type = t
+	}
+	if raw := c.Query("grade"); raw != "" {
+		grade, err := strconv.ParseFloat(raw, 64)
+		if err != nil || grade < 0 || grade > 100 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Grade must be a percentage between 0 and 100", "INVALID_PERCENTAGE"))
+			return
+		}
+		query.Grade = &grade
+	}
+
+	scholarships, err := h.store.ListScholarships()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(data.FilterScholarships(scholarships, query), ""))
+}
+
+// applyScholarships takes the scholarships open to a student with grade off
+// the fees of unis, which must be converted to one currency, returning the
+// scholarships applied by university ID. It writes an error response on
+// failure.
+func (h *Handler) applyScholarships(c *gin.Context, unis []models.University, scholarships []models.Scholarship, faculties *data.FacultyCatalog, grade *int) ([]models.University, map[string][]models.AppliedScholarship, bool) {
+	result, applied, err := h.discount(unis, scholarships, faculties, grade, time.Now())
+	if err != nil {
+		internalError(c, err)
+		return nil, nil, false
+	}
+	return result, applied, true
+}
+
+// discount is applyScholarships as of on, returning errors
+func (h *Handler) discount(unis []models.University, scholarships []models.Scholarship, faculties *data.FacultyCatalog, grade *int, on time.Time) ([]models.University, map[string][]models.AppliedScholarship, error) {
+	var percentage *float64
+	if grade != nil {
+		g := float64(*grade)
+		percentage = &g
+	}
+
+	result := make([]models.University, len(unis))
+	applied := make(map[string][]models.AppliedScholarship)
+	for i, uni := range unis {
+		discounted, used, err := h.rates.ApplyScholarships(uni, scholarships, faculties, percentage, on)
+		if err != nil {
+			return nil, nil, err
+		}
+		result[i] = discounted
+		if len(used) > 0 {
+			applied[uni.ID] = used
+		}
+	}
+	return result, applied, nil
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// Pagination of listings
+const (
+	defaultPage     = 1
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// searchRun is a search over the whole catalogue, before paging
+type searchRun struct {
+	result data.SearchResult
+	// scholarships were applied to the fees when the search asked for them
+	scholarships []models.Scholarship
+}
+
+// withPageDefaults fills in the page and page size of a search
+func withPageDefaults(params models.SearchParams) models.SearchParams {
+	params.Page, params.PageSize = pageDefaults(params.Page, params.PageSize)
+	return params
+}
+
+// pageDefaults fills in a missing page or page size and caps the size at
+// maxPageSize
+func pageDefaults(page, pageSize int) (int, int) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if page <= 0 {
+		page = defaultPage
+	}
+	return page, min(pageSize, maxPageSize)
+}
+
+// paginate returns the items on page, pageSize to a page, with the number
+// of pages. Pages past the last one are empty.
+func paginate[T any](items []T, page, pageSize int) ([]T, int) {
+	total := len(items)
+	totalPages := (total + pageSize - 1) / pageSize
+	// Compared in pages, as (page-1)*pageSize overflows for absurd pages
+	start := total
+	if page-1 < totalPages {
+		start = (page - 1) * pageSize
+	}
+	end := min(start+pageSize, total)
+	return items[start:end], totalPages
+}
+
+// runSearch searches the catalogue, reading filterByFees in currency. Fees
+// are compared in EGP, so the fee filter, sort and facets hold across
+// universities pricing in different currencies; the results carry EGP fees,
+// less any scholarships applied.
+func (h *Handler) runSearch(cat *catalog, params models.SearchParams, currency string) (searchRun, error) {
+	var run searchRun
+	now := time.Now()
+
+	unis, err := h.rates.ConvertUniversities(cat.universities, models.CurrencyEGP, now)
+	if err != nil {
+		return run, err
+	}
+	if params.FilterByFees != nil {
+		fees, err := h.convertAmount(*params.FilterByFees, currency, models.CurrencyEGP)
+		if err != nil {
+			return run, err
+		}
+		params.FilterByFees = &fees
+	}
+	// Scholarships come off the fees before they are filtered and sorted
+	if params.ApplyScholarships {
+		if run.scholarships, err = h.store.ListScholarships(); err != nil {
+			return run, err
+		}
+		if unis, _, err = h.discount(unis, run.scholarships, cat.faculties, params.FilterByGrade, now); err != nil {
+			return run, err
+		}
+	}
+
+	run.result, err = data.SearchUniversities(unis, cat.index, params)
+	return run, err
+}
+
+// searchError writes the response for an error of runSearch
+func searchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, data.ErrInvalidSort):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_SORT"))
+	case errors.Is(err, data.ErrInvalidBackground):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_BACKGROUND"))
+	default:
+		internalError(c, err)
+	}
+}
+
+// searchResponse pages a search, giving the fees of the page in currency. It
+// writes an error response on failure.
+func (h *Handler) searchResponse(c *gin.Context, cat *catalog, params models.SearchParams, currency string, run searchRun) (models.SearchResponse, bool) {
+	result := run.result
+
+	results, totalPages := paginate(result.Universities, params.Page, params.PageSize)
+
+	// Convert the page from the stored fees rather than the EGP figures
+	paginatedResults, ok := h.convert(c, cat.stored(results), currency)
+	if !ok {
+		return models.SearchResponse{}, false
+	}
+	var applied map[string][]models.AppliedScholarship
+	if params.ApplyScholarships {
+		if paginatedResults, applied, ok = h.applyScholarships(c, paginatedResults, run.scholarships, cat.faculties, params.FilterByGrade); !ok {
+			return models.SearchResponse{}, false
+		}
+	}
+	for i, bucket := range result.Facets.Fees {
+		lower, err := h.convertAmount(bucket.Min, models.CurrencyEGP, currency)
+		if err != nil {
+			internalError(c, err)
+			return models.SearchResponse{}, false
+		}
+		result.Facets.Fees[i].Min = lower
+		if bucket.Max != nil {
+			upper, err := h.convertAmount(*bucket.Max, models.CurrencyEGP, currency)
+			if err != nil {
+				internalError(c, err)
+				return models.SearchResponse{}, false
+			}
+			result.Facets.Fees[i].Max = &upper
+		}
+	}
+
+	response := models.SearchResponse{
+		Universities: paginatedResults,
+		Total:        len(result.Universities),
+		Query:        params.SearchQuery,
+		Page:         params.Page,
+		PageSize:     params.PageSize,
+		TotalPages:   totalPages,
+		Suggestions:  result.Suggestions,
+		Facets:       &result.Facets,
+		Scholarships: applied,
+	}
+
+	// Only explain eligibility and scores for the universities on this page
+	if result.Eligibility != nil {
+		response.Eligibility = make(map[string]models.Eligibility, len(paginatedResults))
+		for _, uni := range paginatedResults {
+			response.Eligibility[uni.ID] = result.Eligibility[uni.ID]
+		}
+	}
+	if result.Scores != nil {
+		response.Scores = make(map[string]float64, len(paginatedResults))
+		for _, uni := range paginatedResults {
+			response.Scores[uni.ID] = result.Scores[uni.ID]
+		}
+	}
+	return response, true
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		page, pageSize int
+		want           []int
+		totalPages     int
+	}{
+		{1, 2, []int{1, 2}, 3},
+		{3, 2, []int{5}, 3},
+		{4, 2, []int{}, 3},
+		{1, 10, []int{1, 2, 3, 4, 5}, 1},
+		{math.MaxInt64/2 + 1, 20, []int{}, 1},
+		{math.MaxInt64, 100, []int{}, 1},
+	}
+	for _, tt := range tests {
+		got, totalPages := paginate(items, tt.page, tt.pageSize)
+		if !reflect.DeepEqual(got, tt.want) || totalPages != tt.totalPages {
+			t.Errorf("paginate(page %d of %d) = %v, %d pages; want %v, %d", tt.page, tt.pageSize, got, totalPages, tt.want, tt.totalPages)
+		}
+	}
+}
+
+// TestListingsPastLastPage requests a page so far out that (page-1)*pageSize
+// overflows
+func TestListingsPastLastPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := data.NewMemoryStore()
+	uni := models.University{ID: "1", Name: "جامعة", NameEn: "University", Type: "public", Region: "cairo"}
+	if err := store.PutUniversity(uni); err != nil {
+		t.Fatal(err)
+	}
+	h := New(store, Options{})
+	r := gin.New()
+	r.POST("/universities/search", h.SearchUniversities)
+	r.POST("/programs/search", h.SearchPrograms)
+	r.GET("/universities/:id/reviews", h.GetUniversityReviews)
+
+	const page = math.MaxInt64/2 + 1
+	tests := []struct {
+		name, method, path string
+		body               any
+	}{
+		{"universities", http.MethodPost, "/universities/search", models.SearchParams{Page: page}},
+		{"programs", http.MethodPost, "/programs/search", map[string]int{"page": page}},
+		{"reviews", http.MethodGet, "/universities/1/reviews?page=" + strconv.Itoa(page), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader(raw))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+			}
+
+			var resp models.APIResponse[map[string]json.RawMessage]
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"universities", "programs", "reviews"} {
+				if list, found := resp.Data[key]; found && string(list) != "[]" {
+					t.Errorf("%s = %s, want an empty page", key, list)
+				}
+			}
+		})
+	}
+}
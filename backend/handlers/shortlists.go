@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// ListFavorites returns the current user's favorite universities with their
+// current data, fees in the ?currency= of the request
+func (h *Handler) ListFavorites(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	favorites, err := h.store.ListFavorites(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	ids := make([]string, len(favorites))
+	for i, fav := range favorites {
+		ids[i] = fav.UniversityID
+	}
+	unis, ok := h.referencedUniversities(c, ids)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(data.FavoriteEntries(favorites, unis), ""))
+}
+
+// AddFavorite bookmarks a university for the current user. Adding one
+// already bookmarked succeeds without changing it.
+func (h *Handler) AddFavorite(c *gin.Context) {
+	var req models.FavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+	uni, ok := h.loadUniversity(c, strings.TrimSpace(req.UniversityID))
+	if !ok {
+		return
+	}
+
+	user, _ := CurrentUser(c)
+	favorites, err := h.store.ListFavorites(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	for _, fav := range favorites {
+		if fav.UniversityID == uni.ID {
+			h.respondFavorite(c, http.StatusOK, fav, uni, "Already a favorite")
+			return
+		}
+	}
+	if len(favorites) >= data.MaxFavorites {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Too many favorites", "LIMIT_REACHED",
+			gin.H{"limit": data.MaxFavorites}))
+		return
+	}
+
+	fav := models.Favorite{UserID: user.ID, UniversityID: uni.ID, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	added, err := h.store.AddFavorite(fav)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !added {
+		h.respondFavorite(c, http.StatusOK, fav, uni, "Already a favorite")
+		return
+	}
+	h.respondFavorite(c, http.StatusCreated, fav, uni, "Favorite added")
+}
+
+// respondFavorite writes a favorite with its university, fees in the
+// ?currency= of the request
+func (h *Handler) respondFavorite(c *gin.Context, status int, fav models.Favorite, uni models.University, msg string) {
+	unis, ok := h.inCurrency(c, []models.University{uni}, models.CurrencyEGP)
+	if !ok {
+		return
+	}
+	c.JSON(status, models.NewSuccessResponse(models.FavoriteEntry{Favorite: fav, University: unis[0]}, msg))
+}
+
+// RemoveFavorite removes a university from the current user's favorites
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	removed, err := h.store.RemoveFavorite(user.ID, c.Param("universityId"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Favorite not found", "NOT_FOUND"))
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"universityId": c.Param("universityId")}, "Favorite removed"))
+}
+
+// ListShortlists returns the current user's shortlists with the current data
+// of their items
+func (h *Handler) ListShortlists(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	h.respondShortlists(c, user.ID)
+}
+
+// ListStudentShortlists returns the shortlists of the student :id, for
+// counsellors following them
+func (h *Handler) ListStudentShortlists(c *gin.Context) {
+	student, ok := h.loadStudent(c)
+	if !ok {
+		return
+	}
+	h.respondShortlists(c, student.ID)
+}
+
+// GetStudentShortlist returns the shortlist :shortlistId of the student :id
+func (h *Handler) GetStudentShortlist(c *gin.Context) {
+	student, ok := h.loadStudent(c)
+	if !ok {
+		return
+	}
+	list, found, err := h.store.GetShortlist(student.ID, c.Param("shortlistId"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Shortlist not found", "NOT_FOUND"))
+		return
+	}
+	h.respondShortlist(c, http.StatusOK, list, "")
+}
+
+// loadStudent fetches the student account :id, writing a 404 when there is
+// none or the account holds another role
+func (h *Handler) loadStudent(c *gin.Context) (models.User, bool) {
+	user, found, err := h.store.GetUser(c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return models.User{}, false
+	}
+	if !found || user.Role != models.RoleStudent {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Student not found", "NOT_FOUND"))
+		return models.User{}, false
+	}
+	return user, true
+}
+
+// respondShortlists writes the shortlists of a user with the current data of
+// their items
+func (h *Handler) respondShortlists(c *gin.Context, userID string) {
+	lists, err := h.store.ListShortlists(userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	var ids []string
+	for _, list := range lists {
+		for _, item := range list.Items {
+			ids = append(ids, item.UniversityID)
+		}
+	}
+	views, ok := h.viewShortlists(c, lists, ids)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(views, ""))
+}
+
+// GetShortlist returns one of the current user's shortlists
+func (h *Handler) GetShortlist(c *gin.Context) {
+	list, ok := h.loadShortlist(c)
+	if !ok {
+		return
+	}
+	h.respondShortlist(c, http.StatusOK, list, "")
+}
+
+// CreateShortlist creates a named shortlist for the current user
+func (h *Handler) CreateShortlist(c *gin.Context) {
+	var req models.ShortlistRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+
+	user, _ := CurrentUser(c)
+	lists, err := h.store.ListShortlists(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if len(lists) >= data.MaxShortlists {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Too many shortlists", "LIMIT_REACHED",
+			gin.H{"limit": data.MaxShortlists}))
+		return
+	}
+
+	id, err := auth.RandomString(8)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	list := models.Shortlist{ID: id, UserID: user.ID, Name: req.Name, Items: req.Items, CreatedAt: now, UpdatedAt: now}
+	if list, ok := h.saveShortlist(c, list); ok {
+		h.respondShortlist(c, http.StatusCreated, list, "Shortlist created")
+	}
+}
+
+// ReplaceShortlist renames a shortlist and replaces its items
+func (h *Handler) ReplaceShortlist(c *gin.Context) {
+	var req models.ShortlistRequest
+	if !bindStrict(c, &req) {
+		return
+	}
+	list, ok := h.loadShortlist(c)
+	if !ok {
+		return
+	}
+
+	list.Name, list.Items = req.Name, req.Items
+	if list, ok := h.saveShortlist(c, list); ok {
+		h.respondShortlist(c, http.StatusOK, list, "Shortlist updated")
+	}
+}
+
+// DeleteShortlist removes one of the current user's shortlists
+func (h *Handler) DeleteShortlist(c *gin.Context) {
+	user, _ := CurrentUser(c)
+	deleted, err := h.store.DeleteShortlist(user.ID, c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Shortlist not found", "NOT_FOUND"))
+		return
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"id": c.Param("id")}, "Shortlist deleted"))
+}
+
+// AddShortlistItem appends an item to a shortlist
+func (h *Handler) AddShortlistItem(c *gin.Context) {
+	var item models.ShortlistItem
+	if !bindStrict(c, &item) {
+		return
+	}
+	list, ok := h.loadShortlist(c)
+	if !ok {
+		return
+	}
+
+	list.Items = append(list.Items, item)
+	if list, ok := h.saveShortlist(c, list); ok {
+		h.respondShortlist(c, http.StatusCreated, list, "Item added")
+	}
+}
+
+// DeleteShortlistItem removes the item at :index of a shortlist
+func (h *Handler) DeleteShortlistItem(c *gin.Context) {
+	list, ok := h.loadShortlist(c)
+	if !ok {
+		return
+	}
+	i, ok := entryIndex(c, len(list.Items))
+	if !ok {
+		return
+	}
+
+	list.Items = append(list.Items[:i], list.Items[i+1:]...)
+	if list, ok := h.saveShortlist(c, list); ok {
+		h.respondShortlist(c, http.StatusOK, list, "Item deleted")
+	}
+}
+
+// loadShortlist fetches the current user's shortlist :id, writing a 404
+// when it does not exist
+func (h *Handler) loadShortlist(c *gin.Context) (models.Shortlist, bool) {
+	user, _ := CurrentUser(c)
+	list, found, err := h.store.GetShortlist(user.ID, c.Param("id"))
+	if err != nil {
+		internalError(c, err)
+		return models.Shortlist{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Shortlist not found", "NOT_FOUND"))
+		return models.Shortlist{}, false
+	}
+	return list, true
+}
+
+// saveShortlist validates a shortlist, resolves its items against the
+// catalogue and stores it, returning what was stored. It writes an error
+// response on failure.
+func (h *Handler) saveShortlist(c *gin.Context, list models.Shortlist) (models.Shortlist, bool) {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Items == nil {
+		list.Items = []models.ShortlistItem{}
+	}
+	if err := data.ValidateShortlist(list); err != nil {
+		respondValidation(c, err)
+		return models.Shortlist{}, false
+	}
+
+	cat, ok := h.catalog(c)
+	if !ok {
+		return models.Shortlist{}, false
+	}
+	items, err := data.ResolveShortlistItems(list.Items, cat.universities, cat.faculties)
+	if err != nil {
+		respondValidation(c, err)
+		return models.Shortlist{}, false
+	}
+	list.Items = items
+	list.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	if err := h.store.PutShortlist(list); err != nil {
+		internalError(c, err)
+		return models.Shortlist{}, false
+	}
+	return list, true
+}
+
+// respondShortlist writes a shortlist with the current data of its items
+func (h *Handler) respondShortlist(c *gin.Context, status int, list models.Shortlist, msg string) {
+	ids := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		ids[i] = item.UniversityID
+	}
+	views, ok := h.viewShortlists(c, []models.Shortlist{list}, ids)
+	if !ok {
+		return
+	}
+	c.JSON(status, models.NewSuccessResponse(views[0], msg))
+}
+
+// viewShortlists resolves the items of lists, which refer to the
+// universities ids, with fees in the ?currency= of the request. It writes an
+// error response on failure.
+func (h *Handler) viewShortlists(c *gin.Context, lists []models.Shortlist, ids []string) ([]models.ShortlistView, bool) {
+	unis, ok := h.referencedUniversities(c, ids)
+	if !ok {
+		return nil, false
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return nil, false
+	}
+
+	views := make([]models.ShortlistView, len(lists))
+	for i, list := range lists {
+		views[i] = data.ViewShortlist(list, unis, cat.faculties)
+	}
+	return views, true
+}
+
+// referencedUniversities returns the universities of the catalogue with the
+// given IDs, with fees in the ?currency= of the request. It writes an error
+// response on failure.
+func (h *Handler) referencedUniversities(c *gin.Context, ids []string) ([]models.University, bool) {
+	cat, ok := h.catalog(c)
+	if !ok {
+		return nil, false
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var unis []models.University
+	for _, uni := range cat.universities {
+		if wanted[uni.ID] {
+			unis = append(unis, uni)
+		}
+	}
+	return h.inCurrency(c, unis, models.CurrencyEGP)
+}
+
+// respondValidation writes a 400 for a failed validation
+func respondValidation(c *gin.Context, err error) {
+	var verr data.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponseWithDetails("Validation failed", "VALIDATION_ERROR", verr))
+		return
+	}
+	c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "VALIDATION_ERROR"))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+func TestStudentShortlists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := data.NewMemoryStore()
+	if err := store.PutUniversity(models.University{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo"}); err != nil {
+		t.Fatal(err)
+	}
+	users := make(map[string]models.User)
+	for _, role := range models.Roles {
+		user, err := data.NewUser(role+"@example.com", "long enough", "", role, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CreateUser(user); err != nil {
+			t.Fatal(err)
+		}
+		users[role] = user
+	}
+	for _, role := range []string{models.RoleStudent, models.RoleEditor} {
+		list := models.Shortlist{ID: "plan-" + role, UserID: users[role].ID, Name: "Plan A", Items: []models.ShortlistItem{{UniversityID: "1"}}}
+		if err := store.PutShortlist(list); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tokens := auth.NewIssuer([]byte("secret"), 0, 0)
+	h := New(store, Options{Tokens: tokens})
+	r := gin.New()
+	students := r.Group("/students", Authenticate(tokens), RequireRole(models.RoleCounsellor))
+	students.GET("/:id/shortlists", h.ListStudentShortlists)
+	students.GET("/:id/shortlists/:shortlistId", h.GetStudentShortlist)
+
+	get := func(as, path string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if as != "" {
+			bearer, err := tokens.IssueAccess(users[as], time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	student := "/students/" + users[models.RoleStudent].ID
+	for _, as := range []string{models.RoleCounsellor, models.RoleAdmin} {
+		w := get(as, student+"/shortlists")
+		var resp models.APIResponse[[]models.ShortlistView]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || len(resp.Data) != 1 || resp.Data[0].Name != "Plan A" {
+			t.Errorf("%s listing = %d %s, want the student's shortlist", as, w.Code, w.Body)
+		}
+	}
+	if w := get(models.RoleCounsellor, student+"/shortlists/plan-student"); w.Code != http.StatusOK {
+		t.Errorf("counsellor reading a shortlist = %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name, as, path string
+		want           int
+	}{
+		{"anonymous", "", student + "/shortlists", http.StatusUnauthorized},
+		{"student", models.RoleStudent, student + "/shortlists", http.StatusForbidden},
+		{"moderator", models.RoleModerator, student + "/shortlists", http.StatusForbidden},
+		{"not a student", models.RoleCounsellor, "/students/" + users[models.RoleEditor].ID + "/shortlists", http.StatusNotFound},
+		{"no such account", models.RoleCounsellor, "/students/missing/shortlists", http.StatusNotFound},
+		{"another user's shortlist", models.RoleCounsellor, student + "/shortlists/plan-editor", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := get(tt.as, tt.path); w.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d: %s", tt.path, w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
@@ -9,32 +9,38 @@ import (
 )
 
 // GetOverallStats returns overall university statistics
-func GetOverallStats(c *gin.Context) {
-	stats := data.GetOverallStats()
+func (h *Handler) GetOverallStats(c *gin.Context) {
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+
+	stats := data.GetOverallStats(unis)
 	response := models.NewSuccessResponse(stats, "")
 	c.JSON(http.StatusOK, response)
 }
 
 // GetStatsByRegion returns statistics for a specific region
-func GetStatsByRegion(c *gin.Context) {
+func (h *Handler) GetStatsByRegion(c *gin.Context) {
 	region := c.Param("region")
 	
 	// Validate region
-	validRegions := []string{"cairo", "alexandria", "delta", "upper-egypt", "suez-canal"}
-	isValid := false
-	for _, r := range validRegions {
-		if r == region {
-			isValid = true
-			break
-		}
+	if !models.IsValidRegion(region) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid region", "INVALID_REGION"))
+		return
 	}
 	
-	if !isValid {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid region", "INVALID_REGION"))
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	
+	unis, ok = h.inCurrency(c, unis, models.CurrencyEGP)
+	if !ok {
 		return
 	}
 	
-	stats := data.GetStatsByRegion(region)
+	stats := data.GetStatsByRegion(unis, region)
 	response := models.NewSuccessResponse(stats, "")
 	c.JSON(http.StatusOK, response)
 }
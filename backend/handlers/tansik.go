@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// SimulateTansik places the student in their ordered preference list using
+// the stored cut-offs and flags the wasted entries
+func (h *Handler) SimulateTansik(c *gin.Context) {
+	var req models.TansikSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", "INVALID_REQUEST"))
+		return
+	}
+
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
+	}
+
+	cutoffs, err := h.store.ListCutoffs()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	resp, err := data.SimulateTansik(unis, cat.faculties, cutoffs, req)
+	switch {
+	case errors.Is(err, data.ErrInvalidPercentage):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_PERCENTAGE"))
+		return
+	case errors.Is(err, data.ErrInvalidTrack):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_TRACK"))
+		return
+	case errors.Is(err, data.ErrInvalidGender):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_GENDER"))
+		return
+	case errors.Is(err, data.ErrInvalidZone):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_ZONE"))
+		return
+	case errors.Is(err, data.ErrInvalidPreferences):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), "INVALID_PREFERENCES"))
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(resp, ""))
+}
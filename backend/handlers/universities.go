@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"roadtouniversities/data"
@@ -10,53 +9,70 @@ import (
 )
 
 // GetAllUniversities returns all universities
-func GetAllUniversities(c *gin.Context) {
-	universities := data.GetAllUniversities()
+func (h *Handler) GetAllUniversities(c *gin.Context) {
+	universities, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	universities, ok = h.inCurrency(c, universities, models.CurrencyEGP)
+	if !ok {
+		return
+	}
 	
 	response := models.NewSuccessResponse(universities, "")
 	c.JSON(http.StatusOK, response)
 }
 
-// GetUniversityByID returns a single university by ID
-func GetUniversityByID(c *gin.Context) {
+// GetUniversityByID returns a single university by ID, with its stored fees
+// unless ?currency asks for them converted, so that what an editor reads can
+// be written back unchanged
+func (h *Handler) GetUniversityByID(c *gin.Context) {
 	id := c.Param("id")
 	
-	university, found := data.GetUniversityByID(id)
+	university, found, err := h.store.GetUniversity(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
 	if !found {
 		c.JSON(http.StatusNotFound, models.NewErrorResponse("University not found", "NOT_FOUND"))
 		return
 	}
+	converted, ok := h.inCurrency(c, []models.University{university}, "")
+	if !ok {
+		return
+	}
 	
-	response := models.NewSuccessResponse(university, "")
+	response := models.NewSuccessResponse(converted[0], "")
 	c.JSON(http.StatusOK, response)
 }
 
 // GetUniversitiesByType returns universities filtered by type
-func GetUniversitiesByType(c *gin.Context) {
+func (h *Handler) GetUniversitiesByType(c *gin.Context) {
 	uniType := c.Param("type")
 	
 	// Validate type
-	validTypes := []string{"public", "private", "national", "azhar"}
-	isValid := false
-	for _, t := range validTypes {
-		if t == uniType {
-			isValid = true
-			break
-		}
+	if !models.IsValidType(uniType) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid university type", "INVALID_TYPE"))
+		return
 	}
 	
-	if !isValid {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid university type", "INVALID_TYPE"))
+	unis, ok := h.universities(c)
+	if !ok {
+		return
+	}
+	unis, ok = h.inCurrency(c, unis, models.CurrencyEGP)
+	if !ok {
 		return
 	}
 	
-	universities := data.GetUniversitiesByType(uniType)
+	universities := data.GetUniversitiesByType(unis, uniType)
 	response := models.NewSuccessResponse(universities, "")
 	c.JSON(http.StatusOK, response)
 }
 
 // SearchUniversities handles university search
-func SearchUniversities(c *gin.Context) {
+func (h *Handler) SearchUniversities(c *gin.Context) {
 	var params models.SearchParams
 	
 	if err := c.ShouldBindJSON(&params); err != nil {
@@ -65,52 +81,27 @@ func SearchUniversities(c *gin.Context) {
 	}
 	
 	// Set defaults
-	if params.PageSize <= 0 {
-		params.PageSize = 20
-	}
-	if params.Page <= 0 {
-		params.Page = 1
-	}
-	
-	results := data.SearchUniversities(params)
-	
-	// Calculate pagination
-	total := len(results)
-	totalPages := (total + params.PageSize - 1) / params.PageSize
+	params = withPageDefaults(params)
 	
-	// Apply pagination
-	start := (params.Page - 1) * params.PageSize
-	end := start + params.PageSize
-	if end > total {
-		end = total
+	cat, ok := h.catalog(c)
+	if !ok {
+		return
 	}
-	if start > total {
-		start = total
+	currency, ok := h.requestCurrency(c, models.CurrencyEGP)
+	if !ok {
+		return
 	}
 	
-	paginatedResults := results[start:end]
-	
-	searchResponse := models.SearchResponse{
-		Universities: paginatedResults,
-		Total:        total,
-		Query:        params.SearchQuery,
-		Page:         params.Page,
-		PageSize:     params.PageSize,
-		TotalPages:   totalPages,
+	run, err := h.runSearch(cat, params, currency)
+	if err != nil {
+		searchError(c, err)
+		return
+	}
+	searchResponse, ok := h.searchResponse(c, cat, params, currency, run)
+	if !ok {
+		return
 	}
 	
 	response := models.NewSuccessResponse(searchResponse, "")
 	c.JSON(http.StatusOK, response)
 }
-
-// Simple search implementation
-func matchesSearch(uni models.University, query string) bool {
-	query = strings.ToLower(query)
-	
-	return strings.Contains(strings.ToLower(uni.Name), query) ||
-		strings.Contains(strings.ToLower(uni.NameEn), query) ||
-		strings.Contains(strings.ToLower(uni.Location), query) ||
-		strings.Contains(strings.ToLower(uni.LocationEn), query) ||
-		strings.Contains(strings.ToLower(uni.Description), query) ||
-		strings.Contains(strings.ToLower(uni.DescriptionEn), query)
-}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+func TestGetUniversityByIDKeepsStoredFees(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := data.NewMemoryStore()
+	stored := models.FeesRange{Min: 4000, Max: 6000, Currency: "USD", Period: models.PeriodAnnual}
+	if err := store.PutUniversity(models.University{ID: "1", Name: "جامعة", NameEn: "University", Fees: stored}); err != nil {
+		t.Fatal(err)
+	}
+	rates := data.NewExchangeRates([]models.ExchangeRate{{Currency: "USD", Rate: 50, EffectiveFrom: "2020-01-01"}})
+	r := gin.New()
+	r.GET("/universities/:id", New(store, Options{Rates: rates}).GetUniversityByID)
+
+	tests := []struct {
+		query string
+		want  models.FeesRange
+	}{
+		{"", stored},
+		{"?currency=egp", models.FeesRange{Min: 200000, Max: 300000, Currency: "EGP", Period: models.PeriodAnnual}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/universities/1"+tt.query, nil))
+		var resp models.APIResponse[models.University]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET%s = %d, %v: %s", tt.query, w.Code, err, w.Body)
+		}
+		if resp.Data.Fees != tt.want {
+			t.Errorf("GET%s fees = %+v, want %+v", tt.query, resp.Data.Fees, tt.want)
+		}
+	}
+}
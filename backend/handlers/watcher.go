@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// searchCheckDelay lets a burst of catalogue edits settle before saved
+// searches are checked against them
+const searchCheckDelay = 2 * time.Second
+
+// searchRetryDelay is how long after a failed notification the saved
+// searches are checked again
+const searchRetryDelay = 5 * time.Minute
+
+// searchWatcher runs a check in the background after the catalogue changes.
+// Changes made while a check is pending are covered by it.
+type searchWatcher struct {
+	pending chan struct{}
+}
+
+// newSearchWatcher starts a watcher running check
+func newSearchWatcher(check func()) *searchWatcher {
+	w := &searchWatcher{pending: make(chan struct{}, 1)}
+	go func() {
+		for range w.pending {
+			time.Sleep(searchCheckDelay)
+			check()
+		}
+	}()
+	return w
+}
+
+// schedule has the check run unless one is already pending
+func (w *searchWatcher) schedule() {
+	select {
+	case w.pending <- struct{}{}:
+	default:
+	}
+}
+
+// checkSavedSearches re-runs every saved search with notifications on,
+// notifying its user when the results changed since it was last checked.
+// When a search cannot be checked or its notification not delivered, the
+// check is run again after searchRetryDelay.
+func (h *Handler) checkSavedSearches() {
+	searches, err := h.store.ListWatchedSearches()
+	if err != nil {
+		log.Printf("check saved searches: %v", err)
+		return
+	}
+	if len(searches) == 0 {
+		return
+	}
+	cat, err := h.loadCatalog()
+	if err != nil {
+		log.Printf("check saved searches: %v", err)
+		return
+	}
+
+	failed := false
+	for _, search := range searches {
+		if err := h.checkSavedSearch(cat, search); err != nil {
+			log.Printf("check saved search %s: %v", search.ID, err)
+			failed = true
+		}
+	}
+	if failed {
+		time.AfterFunc(searchRetryDelay, h.watcher.schedule)
+	}
+}
+
+// checkSavedSearch notifies the user of search when its results changed,
+// then records them. Results are recorded only once the notification is
+// delivered, so a failed one is sent again by the next check.
+func (h *Handler) checkSavedSearch(cat *catalog, search models.SavedSearch) error {
+	run, err := h.runSearch(cat, search.Params, search.Currency)
+	if err != nil {
+		return err
+	}
+	matches, err := h.searchMatches(cat, run, search.Currency)
+	if err != nil {
+		return err
+	}
+	changes := data.DiffMatches(search.Matches, matches)
+	if changes.Empty() {
+		return nil
+	}
+
+	user, found, err := h.store.GetUser(search.UserID)
+	if err != nil || !found {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err = h.notifier.Notify(ctx, models.SearchNotification{
+		UserID:     user.ID,
+		Email:      user.Email,
+		UserName:   user.Name,
+		SearchID:   search.ID,
+		SearchName: search.Name,
+		Total:      len(matches),
+		Changes:    changes,
+		CheckedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = h.store.SetSearchMatches(search.ID, matches, now)
+	return err
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"roadtouniversities/data"
+	"roadtouniversities/models"
+)
+
+// recordingNotifier counts notifications, failing them with err
+type recordingNotifier struct {
+	sent []models.SearchNotification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, sn models.SearchNotification) error {
+	n.sent = append(n.sent, sn)
+	return n.err
+}
+
+func TestCheckSavedSearchRecordsAfterDelivery(t *testing.T) {
+	store := data.NewMemoryStore()
+	for _, uni := range []models.University{
+		{ID: "1", Name: "جامعة أ", NameEn: "University A", Type: "public", Region: "cairo"},
+		{ID: "2", Name: "جامعة ب", NameEn: "University B", Type: "private", Region: "cairo"},
+	} {
+		if err := store.PutUniversity(uni); err != nil {
+			t.Fatal(err)
+		}
+	}
+	user, err := data.NewUser("student@example.com", "long enough", "", models.RoleStudent, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	search := models.SavedSearch{ID: "s1", UserID: user.ID, Name: "Cairo", Currency: models.CurrencyEGP, Notify: true,
+		Params: models.SearchParams{SelectedRegion: "cairo"}}
+	if err := store.PutSavedSearch(search); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier := &recordingNotifier{err: errors.New("mail server down")}
+	h := New(store, Options{Notifier: notifier})
+	cat, err := h.loadCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := func() models.SavedSearch {
+		t.Helper()
+		s, _, err := store.GetSavedSearch(user.ID, search.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// An undelivered notification leaves the changes to be sent again
+	if err := h.checkSavedSearch(cat, stored()); !errors.Is(err, notifier.err) {
+		t.Fatalf("check = %v, want the delivery error", err)
+	}
+	if got := stored(); len(got.Matches) != 0 {
+		t.Fatalf("matches recorded after a failed delivery: %+v", got.Matches)
+	}
+
+	notifier.err = nil
+	if err := h.checkSavedSearch(cat, stored()); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if len(notifier.sent) != 2 || len(notifier.sent[1].Changes.Added) != 2 {
+		t.Fatalf("notifications = %+v, want the two universities added twice", notifier.sent)
+	}
+	if got := stored(); len(got.Matches) != 2 || got.CheckedAt.IsZero() {
+		t.Fatalf("stored search = %+v, want two matches recorded", got)
+	}
+
+	// Nothing changed since the delivered notification
+	if err := h.checkSavedSearch(cat, stored()); err != nil || len(notifier.sent) != 2 {
+		t.Fatalf("check = %v with %d notifications, want none sent", err, len(notifier.sent))
+	}
+}
+
+func TestCheckSavedSearchFeesInSearchCurrency(t *testing.T) {
+	store := data.NewMemoryStore()
+	fees := models.FeesRange{Min: 4000, Max: 6000, Currency: "USD", Period: models.PeriodAnnual}
+	if err := store.PutUniversity(models.University{ID: "1", Name: "جامعة", NameEn: "University", Type: "private", Region: "cairo", Fees: fees}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := data.NewUser("student@example.com", "long enough", "", models.RoleStudent, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	search := models.SavedSearch{ID: "s1", UserID: user.ID, Name: "Cairo", Currency: "USD", Notify: true,
+		Params: models.SearchParams{SelectedRegion: "cairo"}}
+	if err := store.PutSavedSearch(search); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier := &recordingNotifier{}
+	h := New(store, Options{Notifier: notifier,
+		Rates: data.NewExchangeRates([]models.ExchangeRate{{Currency: "USD", Rate: 50, EffectiveFrom: "2020-01-01"}})})
+	cat, err := h.loadCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func() models.SavedSearch {
+		t.Helper()
+		s, _, err := store.GetSavedSearch(user.ID, search.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h.checkSavedSearch(cat, s); err != nil {
+			t.Fatalf("check: %v", err)
+		}
+		s, _, err = store.GetSavedSearch(user.ID, search.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	if got := check(); len(got.Matches) != 1 || got.Matches[0].Fees != fees {
+		t.Fatalf("matches = %+v, want the fees in USD as stored", got.Matches)
+	}
+
+	// A new exchange rate alone does not change fees priced in USD
+	h.rates = data.NewExchangeRates([]models.ExchangeRate{{Currency: "USD", Rate: 60, EffectiveFrom: "2020-01-01"}})
+	check()
+	if len(notifier.sent) != 1 {
+		t.Fatalf("notifications = %+v, want none after the rate changed", notifier.sent[1:])
+	}
+}
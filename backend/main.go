@@ -1,59 +1,279 @@
 package main
 
 import (
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"roadtouniversities/auth"
+	"roadtouniversities/data"
 	"roadtouniversities/handlers"
+	"roadtouniversities/models"
+	"roadtouniversities/notify"
 )
 
 func main() {
-	// Get port from environment or default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	cfg := loadConfig()
+	port := cfg.Port
+
+	// Load the catalogue from the data directory, or the built-in seed files
+	seedFS := data.DefaultSeed()
+	if cfg.DataDir != "" {
+		seedFS = os.DirFS(cfg.DataDir)
+	}
+	catalogue, err := data.LoadUniversities(seedFS)
+	if err != nil {
+		log.Fatalf("Invalid seed data in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+	log.Printf("📦 Loaded %d universities from %s", len(catalogue), seedSource(cfg.DataDir))
+
+	// Open the configured store and seed it when empty
+	store, err := data.Open(cfg.Store)
+	if err != nil {
+		log.Fatal("Failed to open store:", err)
+	}
+	defer store.Close()
+
+	if err := data.Seed(store, catalogue); err != nil {
+		log.Fatal("Failed to seed store:", err)
+	}
+
+	disciplines, err := data.LoadDisciplines(seedFS)
+	if err != nil {
+		log.Fatalf("Invalid faculty catalogue in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+	if err := data.SeedDisciplines(store, disciplines); err != nil {
+		log.Fatal("Failed to seed faculty catalogue:", err)
+	}
+
+	// Historical cut-offs are checked against the stored catalogue, which may
+	// have been edited since it was first seeded
+	stored, err := store.ListUniversities()
+	if err != nil {
+		log.Fatal("Failed to read store:", err)
+	}
+	storedDisciplines, err := store.ListDisciplines()
+	if err != nil {
+		log.Fatal("Failed to read store:", err)
+	}
+	cutoffs, err := data.LoadCutoffs(seedFS, stored, data.NewFacultyCatalog(storedDisciplines))
+	if err != nil {
+		log.Fatalf("Invalid cut-off data in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+	if err := data.SeedCutoffs(store, cutoffs); err != nil {
+		log.Fatal("Failed to seed cut-offs:", err)
+	}
+
+	scholarships, err := data.LoadScholarships(seedFS, stored, data.NewFacultyCatalog(storedDisciplines))
+	if err != nil {
+		log.Fatalf("Invalid scholarship data in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+	if err := data.SeedScholarships(store, scholarships); err != nil {
+		log.Fatal("Failed to seed scholarships:", err)
+	}
+
+	events, err := data.LoadCalendar(seedFS, stored, data.NewFacultyCatalog(storedDisciplines))
+	if err != nil {
+		log.Fatalf("Invalid admissions calendar in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+	if err := data.SeedEvents(store, events); err != nil {
+		log.Fatal("Failed to seed admissions calendar:", err)
+	}
+
+	rates, err := loadExchangeRates(seedFS, cfg.ExchangeRates)
+	if err != nil {
+		source := seedSource(cfg.DataDir)
+		if cfg.ExchangeRates != "" {
+			source = cfg.ExchangeRates
+		}
+		log.Fatalf("Invalid exchange rates in %s:\n%v", source, err)
+	}
+	if missing := rates.MissingRates(stored, time.Now()); len(missing) > 0 {
+		log.Fatalf("No exchange rate in effect today for %s, used in university fees", strings.Join(missing, ", "))
+	}
+	if cfg.AdminEmail != "" {
+		if err := data.EnsureAdmin(store, cfg.AdminEmail, cfg.AdminPassword); err != nil {
+			log.Fatal("Failed to create admin account: ", err)
+		}
+	}
+	secret := cfg.JWTSecret
+	if secret == "" {
+		log.Println("⚠️  No JWT secret configured; sign-ins will not survive a restart")
+		if secret, err = auth.RandomString(32); err != nil {
+			log.Fatal("Failed to generate JWT secret: ", err)
+		}
+	}
+	tokens := auth.NewIssuer([]byte(secret), cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+
+	notifier, err := notify.Open(cfg.Notify)
+	if err != nil {
+		log.Fatal("Invalid notifier: ", err)
+	}
+
+	abuse, err := data.LoadAbuseFilter(seedFS)
+	if err != nil {
+		log.Fatalf("Invalid moderation word lists in %s:\n%v", seedSource(cfg.DataDir), err)
+	}
+
+	opts := handlers.Options{Rates: rates, Tokens: tokens, Notifier: notifier, AbuseFilter: abuse}
+	if cfg.RecommendationWeights != "" {
+		opts.RecommendationWeights, err = data.LoadRecommendationWeights(cfg.RecommendationWeights)
+		if err != nil {
+			log.Fatal("Invalid recommendation weights: ", err)
+		}
 	}
 
+	h := handlers.New(store, opts)
+
 	// Initialize Gin router
 	r := gin.Default()
 
 	// Configure CORS for frontend
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		AllowCredentials: true,
 	}))
 
 	// API v1 routes
-	v1 := r.Group("/api/v1")
+	v1 := r.Group("/api/v1", handlers.Authenticate(tokens))
 	{
 		// Health check
 		v1.GET("/health", handlers.HealthCheck)
 
+		// Program-level search
+		v1.POST("/programs/search", h.SearchPrograms)
+
+		// Admission chance calculator
+		v1.POST("/admission/estimate", h.EstimateAdmission)
+		v1.POST("/tansik/simulate", h.SimulateTansik)
+
+		// Total cost of study
+		v1.POST("/cost/estimate", h.EstimateCost)
+
+		// Scholarships and financial aid
+		v1.GET("/scholarships", h.GetScholarships)
+
+		// Admissions calendar
+		v1.GET("/calendar", h.GetCalendar)
+		v1.GET("/calendar.ics", h.GetCalendarICS)
+		v1.PUT("/calendar/events/:id", handlers.RequireRole(models.RoleEditor), h.PutEvent)
+		v1.DELETE("/calendar/events/:id", handlers.RequireRole(models.RoleEditor), h.DeleteEvent)
+
+		// Personalised recommendations
+		v1.POST("/recommendations", h.Recommend)
+
+		// Search bar autocomplete
+		v1.GET("/autocomplete", h.Autocomplete)
+
+		// Side-by-side comparison
+		v1.GET("/compare", h.CompareUniversities)
+
 		// Universities routes
 		universities := v1.Group("/universities")
 		{
-			universities.GET("", handlers.GetAllUniversities)
-			universities.GET("/:id", handlers.GetUniversityByID)
-			universities.GET("/type/:type", handlers.GetUniversitiesByType)
-			universities.POST("/search", handlers.SearchUniversities)
+			universities.GET("", h.GetAllUniversities)
+			universities.GET("/:id", h.GetUniversityByID)
+			universities.GET("/type/:type", h.GetUniversitiesByType)
+			universities.GET("/:id/cutoffs", h.GetUniversityCutoffs)
+			universities.POST("/search", h.SearchUniversities)
+			universities.GET("/:id/reviews", h.GetUniversityReviews)
+			universities.POST("/:id/reviews", handlers.RequireRole(), h.CreateReview)
+
+			// Catalogue editing routes; deleting a university takes an admin
+			editor := handlers.RequireRole(models.RoleEditor)
+			universities.POST("", editor, h.CreateUniversity)
+			universities.PUT("/:id", editor, h.ReplaceUniversity)
+			universities.PATCH("/:id", editor, h.PatchUniversity)
+			universities.DELETE("/:id", handlers.RequireRole(models.RoleAdmin), h.DeleteUniversity)
+			universities.PUT("/:id/faculties/:faculty", editor, h.PutFaculty)
+			universities.DELETE("/:id/faculties/:faculty", editor, h.DeleteFaculty)
+			universities.POST("/:id/faculties/:faculty/departments", editor, h.CreateDepartment)
+			universities.PUT("/:id/faculties/:faculty/departments/:index", editor, h.ReplaceDepartment)
+			universities.DELETE("/:id/faculties/:faculty/departments/:index", editor, h.DeleteDepartment)
+			universities.POST("/:id/faculties/:faculty/specializations", editor, h.CreateSpecialization)
+			universities.PUT("/:id/faculties/:faculty/specializations/:index", editor, h.ReplaceSpecialization)
+			universities.DELETE("/:id/faculties/:faculty/specializations/:index", editor, h.DeleteSpecialization)
 		}
 
 		// Statistics routes
 		stats := v1.Group("/stats")
 		{
-			stats.GET("", handlers.GetOverallStats)
-			stats.GET("/region/:region", handlers.GetStatsByRegion)
+			stats.GET("", h.GetOverallStats)
+			stats.GET("/region/:region", h.GetStatsByRegion)
 		}
 
 		// Faculties routes
 		faculties := v1.Group("/faculties")
 		{
-			faculties.GET("", handlers.GetAllFaculties)
-			faculties.GET("/:id", handlers.GetFacultyByID)
+			faculties.GET("", h.GetAllFaculties)
+			faculties.GET("/:id", h.GetFacultyByID)
+			faculties.GET("/:id/cutoffs", h.GetFacultyCutoffs)
+		}
+
+		// Cut-off import
+		v1.POST("/cutoffs/import", handlers.RequireRole(models.RoleEditor), h.ImportCutoffs)
+
+		// Review moderation
+		moderation := v1.Group("/moderation", handlers.RequireRole(models.RoleModerator))
+		{
+			moderation.GET("/reviews", h.ListModerationQueue)
+			moderation.GET("/reviews/:id", h.GetModerationReview)
+			moderation.POST("/reviews/:id/approve", h.ApproveReview)
+			moderation.POST("/reviews/:id/reject", h.RejectReview)
+			moderation.GET("/actions", h.ListModerationActions)
+		}
+
+		// Accounts
+		account := v1.Group("/auth")
+		{
+			account.POST("/register", h.Register)
+			account.POST("/login", h.Login)
+			account.POST("/refresh", h.Refresh)
+			account.POST("/logout", h.Logout)
+			account.GET("/me", handlers.RequireRole(), h.Me)
+		}
+		users := v1.Group("/users", handlers.RequireRole(models.RoleAdmin))
+		{
+			users.GET("", h.ListUsers)
+			users.PUT("/:id/role", h.SetUserRole)
+		}
+
+		// Counsellors read the shortlists of students
+		students := v1.Group("/students", handlers.RequireRole(models.RoleCounsellor))
+		{
+			students.GET("/:id/shortlists", h.ListStudentShortlists)
+			students.GET("/:id/shortlists/:shortlistId", h.GetStudentShortlist)
+		}
+
+		// Personal favorites, shortlists, saved searches and reviews
+		me := v1.Group("/me", handlers.RequireRole())
+		{
+			me.GET("/favorites", h.ListFavorites)
+			me.POST("/favorites", h.AddFavorite)
+			me.DELETE("/favorites/:universityId", h.RemoveFavorite)
+			me.GET("/shortlists", h.ListShortlists)
+			me.POST("/shortlists", h.CreateShortlist)
+			me.GET("/shortlists/:id", h.GetShortlist)
+			me.PUT("/shortlists/:id", h.ReplaceShortlist)
+			me.DELETE("/shortlists/:id", h.DeleteShortlist)
+			me.POST("/shortlists/:id/items", h.AddShortlistItem)
+			me.DELETE("/shortlists/:id/items/:index", h.DeleteShortlistItem)
+			me.GET("/searches", h.ListSavedSearches)
+			me.POST("/searches", h.CreateSavedSearch)
+			me.GET("/searches/:id", h.GetSavedSearch)
+			me.PUT("/searches/:id", h.ReplaceSavedSearch)
+			me.DELETE("/searches/:id", h.DeleteSavedSearch)
+			me.POST("/searches/:id/run", h.RunSavedSearch)
+			me.GET("/reviews", h.ListMyReviews)
+			me.PUT("/reviews/:id", h.ReplaceMyReview)
+			me.DELETE("/reviews/:id", h.DeleteMyReview)
 		}
 	}
 
@@ -65,3 +285,34 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// seedSource describes where seed data was loaded from
+func seedSource(dataDir string) string {
+	if dataDir == "" {
+		return "built-in seed files"
+	}
+	return dataDir
+}
+
+// loadExchangeRates reads the exchange-rate table from path, or from the seed
+// files when path is empty
+func loadExchangeRates(seedFS fs.FS, path string) (*data.ExchangeRates, error) {
+	if path == "" {
+		rates, err := data.LoadExchangeRates(seedFS)
+		if err != nil {
+			return nil, err
+		}
+		return data.NewExchangeRates(rates), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rates, err := data.ReadExchangeRates(f, filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	return data.NewExchangeRates(rates), nil
+}
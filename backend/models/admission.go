@@ -0,0 +1,64 @@
+package models
+
+// Certificate types a student can hold
+const (
+	CertificateThanawiya     = "thanawiya"     // Egyptian general secondary
+	CertificateAzhari        = "azhari"        // Al-Azhar secondary
+	CertificateInternational = "international" // any international certificate
+	CertificateIGCSE         = "igcse"
+	CertificateAmerican      = "american"
+	CertificateIB            = "ib"
+)
+
+// Certificates lists the valid certificate types
+var Certificates = []string{
+	CertificateThanawiya, CertificateAzhari, CertificateInternational,
+	CertificateIGCSE, CertificateAmerican, CertificateIB,
+}
+
+// IsValidCertificate reports whether c is a known certificate type
+func IsValidCertificate(c string) bool {
+	return contains(Certificates, c)
+}
+
+// IsInternationalCertificate reports whether c is an international certificate
+func IsInternationalCertificate(c string) bool {
+	switch c {
+	case CertificateInternational, CertificateIGCSE, CertificateAmerican, CertificateIB:
+		return true
+	}
+	return false
+}
+
+// AdmissionRule is the minimum requirement for one certificate type.
+// International certificates may set MinScore on their own scale (e.g. 28 of
+// 45 IB points); a native score converts to a percentage as
+// score / ScoreScale * 100 for comparison against MinPercentage.
+type AdmissionRule struct {
+	Certificate   string  `json:"certificate"`
+	MinPercentage float64 `json:"minPercentage,omitempty"`
+	MinScore      float64 `json:"minScore,omitempty"`
+	ScoreScale    float64 `json:"scoreScale,omitempty"`
+	Note          string  `json:"note,omitempty"`
+	NoteEn        string  `json:"noteEn,omitempty"`
+}
+
+// Eligibility explains whether a student meets a university's requirements
+type Eligibility struct {
+	Eligible      bool                 `json:"eligible"`
+	Certificate   string               `json:"certificate"`
+	MinPercentage float64              `json:"minPercentage,omitempty"`
+	MinScore      float64              `json:"minScore,omitempty"`
+	Reason        string               `json:"reason"`
+	Faculties     []FacultyEligibility `json:"faculties,omitempty"`
+}
+
+// FacultyEligibility explains eligibility for a faculty with its own rules
+type FacultyEligibility struct {
+	Faculty       string  `json:"faculty"`
+	FacultyEn     string  `json:"facultyEn"`
+	Eligible      bool    `json:"eligible"`
+	MinPercentage float64 `json:"minPercentage,omitempty"`
+	MinScore      float64 `json:"minScore,omitempty"`
+	Reason        string  `json:"reason"`
+}
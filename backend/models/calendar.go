@@ -0,0 +1,48 @@
+package models
+
+// Admission event kinds
+const (
+	EventApplicationOpen  = "application-open"
+	EventApplicationClose = "application-close"
+	EventEntranceTest     = "entrance-test"
+	EventInterview        = "interview"
+	EventTansikPhase      = "tansik-phase"
+)
+
+// EventKinds lists the valid values of AdmissionEvent.Kind
+var EventKinds = []string{
+	EventApplicationOpen, EventApplicationClose, EventEntranceTest, EventInterview, EventTansikPhase,
+}
+
+// IsValidEventKind reports whether k is a known admission event kind
+func IsValidEventKind(k string) bool {
+	return contains(EventKinds, k)
+}
+
+// AdmissionEvent is a date in the admissions calendar. Events run whole days
+// from Start to End inclusive, or on Start alone when End is empty.
+type AdmissionEvent struct {
+	ID            string `json:"id"`
+	UniversityID  string `json:"universityId,omitempty"` // empty for nationwide events such as Tansik phases
+	Faculty       string `json:"faculty,omitempty"`      // faculty ID; the whole university when empty
+	Kind          string `json:"kind"`
+	Title         string `json:"title"`
+	TitleEn       string `json:"titleEn"`
+	Description   string `json:"description,omitempty"`
+	DescriptionEn string `json:"descriptionEn,omitempty"`
+	Start         string `json:"start"`         // YYYY-MM-DD
+	End           string `json:"end,omitempty"` // YYYY-MM-DD
+	Location      string `json:"location,omitempty"`
+	LocationEn    string `json:"locationEn,omitempty"`
+	URL           string `json:"url,omitempty"`
+}
+
+// CalendarEvent is an admission event with the names of its university and
+// faculty
+type CalendarEvent struct {
+	AdmissionEvent
+	University    string `json:"university,omitempty"`
+	UniversityEn  string `json:"universityEn,omitempty"`
+	FacultyName   string `json:"facultyName,omitempty"`
+	FacultyNameEn string `json:"facultyNameEn,omitempty"`
+}
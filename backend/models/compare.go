@@ -0,0 +1,60 @@
+package models
+
+// Which way a comparison metric improves
+const (
+	BetterHigher = "higher"
+	BetterLower  = "lower"
+)
+
+// ComparedUniversity identifies one university of a comparison
+type ComparedUniversity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	NameEn      string `json:"nameEn"`
+	Type        string `json:"type"`
+	Region      string `json:"region"`
+	Location    string `json:"location"`
+	LocationEn  string `json:"locationEn"`
+	Established int    `json:"established,omitempty"`
+	Students    int    `json:"students,omitempty"`
+}
+
+// ComparisonMetric is one measure across the compared universities. Values
+// are aligned with Comparison.Universities and null where a university does
+// not publish the measure. Best lists the IDs holding the best value; it is
+// empty for measures without a better direction.
+type ComparisonMetric struct {
+	Key    string     `json:"key"`
+	Label  string     `json:"label"`
+	Better string     `json:"better,omitempty"` // higher or lower
+	Values []*float64 `json:"values"`
+	Best   []string   `json:"best"`
+}
+
+// ComparedFaculty is a faculty offered by some of the compared universities
+type ComparedFaculty struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	NameEn string `json:"nameEn"`
+}
+
+// FacultyFeeComparison compares the annual fees of one faculty at the
+// universities publishing them. Fees are aligned with
+// Comparison.Universities; Difference is the gap between the highest and
+// lowest upper bounds.
+type FacultyFeeComparison struct {
+	ComparedFaculty
+	Fees       []*FeesRange `json:"fees"`
+	Cheapest   []string     `json:"cheapest"`
+	Difference int          `json:"difference"`
+}
+
+// Comparison sets two or more universities side by side, in the requested
+// order
+type Comparison struct {
+	Universities    []ComparedUniversity         `json:"universities"`
+	Metrics         []ComparisonMetric           `json:"metrics"`
+	CommonFaculties []ComparedFaculty            `json:"commonFaculties"`
+	UniqueFaculties map[string][]ComparedFaculty `json:"uniqueFaculties"` // by university ID
+	FacultyFees     []FacultyFeeComparison       `json:"facultyFees"`
+}
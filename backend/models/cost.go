@@ -0,0 +1,65 @@
+package models
+
+// CostModel holds what studying at a university costs besides tuition.
+// Amounts are in EGP per academic year unless noted.
+type CostModel struct {
+	AnnualIncrease  float64 `json:"annualIncrease,omitempty"`  // yearly tuition increase in percent
+	RegistrationFee int     `json:"registrationFee,omitempty"` // paid once, on enrolment
+	OtherFees       int     `json:"otherFees,omitempty"`       // activities, books, insurance
+	Housing         int     `json:"housing,omitempty"`         // dormitory or shared rent
+	Transport       int     `json:"transport,omitempty"`
+}
+
+// CostEstimateRequest selects a program to cost. Faculty is needed for
+// Department; without them the university's fees are used. The overrides
+// replace the university's cost model, e.g. housing 0 for a student living
+// at home. ApplyScholarships takes the best scholarship open to a student
+// with Grade off the tuition.
+type CostEstimateRequest struct {
+	UniversityID   string   `json:"universityId" binding:"required"`
+	Faculty        string   `json:"faculty,omitempty"`    // Arabic or English name, or catalogue ID
+	Department     string   `json:"department,omitempty"` // Arabic or English name
+	Years          int      `json:"years,omitempty"`      // overrides the program's duration
+	AnnualIncrease *float64 `json:"annualIncrease,omitempty"`
+	Housing        *int     `json:"housing,omitempty"`
+	Transport      *int     `json:"transport,omitempty"`
+	IncludeUSD     bool     `json:"includeUsd,omitempty"`
+
+	ApplyScholarships bool     `json:"applyScholarships,omitempty"`
+	Grade             *float64 `json:"grade,omitempty"` // Thanawiya percentage
+}
+
+// YearCost is the cost of one academic year in EGP
+type YearCost struct {
+	Year         int      `json:"year"`
+	Tuition      int      `json:"tuition"`
+	Registration int      `json:"registration,omitempty"`
+	OtherFees    int      `json:"otherFees,omitempty"`
+	Housing      int      `json:"housing,omitempty"`
+	Transport    int      `json:"transport,omitempty"`
+	Discount     int      `json:"discount,omitempty"` // scholarship, off the tuition
+	Total        int      `json:"total"`
+	TotalUSD     *float64 `json:"totalUsd,omitempty"`
+}
+
+// CostEstimate is the year-by-year cost of a program in EGP. USD totals and
+// the exchange rate (EGP per USD) are given for universities billing in
+// dollars and when requested. Totals are net of any scholarship applied.
+type CostEstimate struct {
+	UniversityID   string              `json:"universityId"`
+	University     string              `json:"university"`
+	UniversityEn   string              `json:"universityEn"`
+	Faculty        string              `json:"faculty,omitempty"`
+	FacultyEn      string              `json:"facultyEn,omitempty"`
+	Department     string              `json:"department,omitempty"`
+	DepartmentEn   string              `json:"departmentEn,omitempty"`
+	BilledIn       string              `json:"billedIn"`
+	Years          int                 `json:"years"`
+	AnnualTuition  int                 `json:"annualTuition"` // first-year tuition
+	AnnualIncrease float64             `json:"annualIncrease"`
+	Scholarship    *AppliedScholarship `json:"scholarship,omitempty"`
+	Breakdown      []YearCost          `json:"breakdown"`
+	Total          int                 `json:"total"`
+	TotalUSD       *float64            `json:"totalUsd,omitempty"`
+	ExchangeRate   float64             `json:"exchangeRate,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// Currencies with a fixed meaning; any ISO 4217 code with an exchange rate
+// may be used for fees
+const (
+	CurrencyEGP = "EGP"
+	CurrencyUSD = "USD"
+)
+
+// Fee periods. Fees without a period are annual; a faculty's departments
+// and specializations inherit its currency and period.
+const (
+	PeriodAnnual   = "annual"
+	PeriodSemester = "semester"
+)
+
+// FeePeriods lists the valid fee periods
+var FeePeriods = []string{PeriodAnnual, PeriodSemester}
+
+// IsValidFeePeriod reports whether p is a known fee period
+func IsValidFeePeriod(p string) bool {
+	return contains(FeePeriods, p)
+}
+
+// IsCurrencyCode reports whether c looks like an ISO 4217 code, e.g. "USD"
+func IsCurrencyCode(c string) bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ExchangeRate is the value of one unit of a currency in EGP from a date
+// until the next rate for the currency takes effect
+type ExchangeRate struct {
+	Currency      string  `json:"currency"`
+	Rate          float64 `json:"rate"`          // EGP per unit
+	EffectiveFrom string  `json:"effectiveFrom"` // YYYY-MM-DD
+}
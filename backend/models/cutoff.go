@@ -0,0 +1,58 @@
+package models
+
+// Applicant genders. Cut-offs for segregated faculties differ by gender.
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+)
+
+// Genders lists the valid genders
+var Genders = []string{GenderMale, GenderFemale}
+
+// IsValidGender reports whether g is a known gender
+func IsValidGender(g string) bool {
+	return contains(Genders, g)
+}
+
+// Cutoff is the lowest grade admitted to a faculty through the yearly
+// coordination (Tansik) for one secondary school track. Gender is empty when
+// the cut-off applies to every applicant. Score and MaxScore keep the raw
+// mark when the source gave one.
+type Cutoff struct {
+	UniversityID string  `json:"universityId"`
+	FacultyID    string  `json:"facultyId"`
+	Faculty      string  `json:"faculty"`
+	FacultyEn    string  `json:"facultyEn"`
+	Track        string  `json:"track"`
+	Gender       string  `json:"gender,omitempty"`
+	Year         int     `json:"year"`
+	Percentage   float64 `json:"percentage"`
+	Score        float64 `json:"score,omitempty"`
+	MaxScore     float64 `json:"maxScore,omitempty"`
+}
+
+// CutoffPoint is one year of a cut-off series. Change is the difference in
+// percentage points from the previous year in the series, when there is one.
+type CutoffPoint struct {
+	Year       int      `json:"year"`
+	Percentage float64  `json:"percentage"`
+	Score      float64  `json:"score,omitempty"`
+	MaxScore   float64  `json:"maxScore,omitempty"`
+	Change     *float64 `json:"change,omitempty"`
+}
+
+// CutoffSeries is the cut-off history of one university faculty, track and
+// gender
+type CutoffSeries struct {
+	UniversityID  string        `json:"universityId"`
+	University    string        `json:"university"`
+	UniversityEn  string        `json:"universityEn"`
+	FacultyID     string        `json:"facultyId"`
+	Faculty       string        `json:"faculty"`
+	FacultyEn     string        `json:"facultyEn"`
+	Track         string        `json:"track"`
+	Gender        string        `json:"gender,omitempty"`
+	Points        []CutoffPoint `json:"points"`
+	Latest        *CutoffPoint  `json:"latest,omitempty"`
+	AverageChange *float64      `json:"averageChange,omitempty"` // per year, over the whole series
+}
@@ -0,0 +1,56 @@
+package models
+
+// FieldsOfStudy lists the valid values of Discipline.Field
+var FieldsOfStudy = []string{
+	"medical", "engineering", "computing", "sciences", "business", "humanities",
+	"languages", "law", "education", "arts", "agriculture", "religious-studies",
+}
+
+// IsValidFieldOfStudy reports whether f is a known field of study
+func IsValidFieldOfStudy(f string) bool {
+	return contains(FieldsOfStudy, f)
+}
+
+// Discipline is a canonical faculty, such as Medicine, offered by many
+// universities under their own names. University faculties are linked to a
+// discipline by their Arabic or English name, or one of its aliases.
+type Discipline struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	NameEn        string   `json:"nameEn"`
+	Description   string   `json:"description"`
+	DescriptionEn string   `json:"descriptionEn"`
+	Field         string   `json:"field"`             // field of study
+	Aliases       []string `json:"aliases,omitempty"` // other Arabic or English names
+}
+
+// FacultySummary is a discipline with the number of universities offering it
+type FacultySummary struct {
+	Discipline
+	UniversityCount int `json:"universityCount"`
+}
+
+// FacultyOffering is a discipline as taught at one university. Fees,
+// departments and specializations come from the university's detailed
+// faculty when it has one, otherwise fees are the university's range.
+type FacultyOffering struct {
+	UniversityID    string           `json:"universityId"`
+	University      string           `json:"university"`
+	UniversityEn    string           `json:"universityEn"`
+	UniversityType  string           `json:"universityType"`
+	Region          string           `json:"region"`
+	Name            string           `json:"name"`   // the university's name for the faculty
+	NameEn          string           `json:"nameEn"` // the university's English name for it
+	Description     string           `json:"description,omitempty"`
+	DescriptionEn   string           `json:"descriptionEn,omitempty"`
+	AnnualFees      FeesRange        `json:"annualFees"`
+	AnnualFeesEn    string           `json:"annualFeesEn,omitempty"`
+	Departments     []Department     `json:"departments,omitempty"`
+	Specializations []Specialization `json:"specializations,omitempty"`
+}
+
+// FacultyDetail is a discipline with every university offering it
+type FacultyDetail struct {
+	Discipline
+	Universities []FacultyOffering `json:"universities"`
+}
@@ -0,0 +1,62 @@
+package models
+
+// Secondary school tracks
+const (
+	TrackScience  = "science"  // علمي علوم
+	TrackMath     = "math"     // علمي رياضة
+	TrackLiterary = "literary" // أدبي
+)
+
+// Tracks lists the valid secondary school tracks
+var Tracks = []string{TrackScience, TrackMath, TrackLiterary}
+
+// IsValidTrack reports whether t is a known track
+func IsValidTrack(t string) bool {
+	return contains(Tracks, t)
+}
+
+// Admission chance classifications, from most to least certain
+const (
+	ChanceSafe       = "safe"
+	ChanceLikely     = "likely"
+	ChanceReach      = "reach"
+	ChanceOutOfRange = "out_of_range"
+)
+
+// Chances lists the classifications in order
+var Chances = []string{ChanceSafe, ChanceLikely, ChanceReach, ChanceOutOfRange}
+
+// AdmissionEstimateRequest represents an admission estimate request body
+type AdmissionEstimateRequest struct {
+	Percentage       *float64 `json:"percentage"`
+	Certificate      string   `json:"certificate"`
+	CertificateScore *float64 `json:"certificateScore,omitempty"`
+	Track            string   `json:"track,omitempty"`
+	Gender           string   `json:"gender,omitempty"`    // skips cut-offs for the other gender
+	Faculties        []string `json:"faculties,omitempty"` // Arabic or English names
+	SelectedType     string   `json:"selectedType,omitempty"`
+	SelectedRegion   string   `json:"selectedRegion,omitempty"`
+}
+
+// AdmissionEstimate classifies a student's chance at one university or
+// faculty. Faculty is empty for a university-wide estimate.
+type AdmissionEstimate struct {
+	UniversityID   string   `json:"universityId"`
+	University     string   `json:"university"`
+	UniversityEn   string   `json:"universityEn"`
+	Faculty        string   `json:"faculty,omitempty"`
+	FacultyEn      string   `json:"facultyEn,omitempty"`
+	Cutoff         float64  `json:"cutoff"`
+	CutoffSource   string   `json:"cutoffSource"`
+	Margin         float64  `json:"margin"`
+	AcceptanceRate int      `json:"acceptanceRate,omitempty"`
+	Classification string   `json:"classification"`
+	Reasons        []string `json:"reasons"`
+}
+
+// AdmissionEstimateResponse represents an admission estimate response
+type AdmissionEstimateResponse struct {
+	Estimates          []AdmissionEstimate `json:"estimates"`
+	Summary            map[string]int      `json:"summary"`
+	UnmatchedFaculties []string            `json:"unmatchedFaculties,omitempty"`
+}
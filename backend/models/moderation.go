@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Kinds of ReviewFlag raised by the abuse pre-filter
+const (
+	FlagProfanity    = "profanity"
+	FlagPersonalData = "personal_data"
+	FlagDuplicate    = "duplicate"
+)
+
+// FlagKinds lists the valid values of ReviewFlag.Kind
+var FlagKinds = []string{FlagProfanity, FlagPersonalData, FlagDuplicate}
+
+// IsValidFlagKind reports whether k is a known flag kind
+func IsValidFlagKind(k string) bool {
+	return contains(FlagKinds, k)
+}
+
+// ReviewFlag is something in a review for a moderator to look at, such as
+// a profane word or a phone number, described by Detail
+type ReviewFlag struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// Moderation decisions recorded in the audit trail
+const (
+	ModerationApprove = "approve"
+	ModerationReject  = "reject"
+)
+
+// ModerationAction is an entry of the moderation audit trail: a moderator's
+// decision on a review, with the flags the review had at the time
+type ModerationAction struct {
+	ID             string       `json:"id"`
+	ReviewID       string       `json:"reviewId"`
+	UniversityID   string       `json:"universityId"`
+	ModeratorID    string       `json:"moderatorId"`
+	Action         string       `json:"action"`
+	PreviousStatus string       `json:"previousStatus"`
+	Status         string       `json:"status"`
+	Reason         string       `json:"reason,omitempty"`
+	Flags          []ReviewFlag `json:"flags"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+// ModerationRequest approves or rejects a review. UpdatedAt is the
+// review's updatedAt as the moderator read it, so that a review edited in
+// the meantime is not decided unread. Rejections need a reason.
+type ModerationRequest struct {
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ModerationEntry is a review as moderators see it, with its author, flags
+// and, for a single review, its moderation history
+type ModerationEntry struct {
+	Review
+	Flags   []ReviewFlag            `json:"flags"`
+	Author  *User                   `json:"author,omitempty"`
+	History []ModerationActionEntry `json:"history,omitempty"`
+}
+
+// ModerationActionEntry is an audit trail entry with the moderator's account
+type ModerationActionEntry struct {
+	ModerationAction
+	Moderator *User `json:"moderator,omitempty"`
+}
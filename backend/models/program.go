@@ -0,0 +1,72 @@
+package models
+
+// Degree types a program can award
+const (
+	DegreeBachelor  = "bachelor"
+	DegreeMaster    = "master"
+	DegreeDoctorate = "doctorate"
+	DegreeDiploma   = "diploma"
+)
+
+// DegreeTypes lists the valid degree types
+var DegreeTypes = []string{DegreeBachelor, DegreeMaster, DegreeDoctorate, DegreeDiploma}
+
+// IsValidDegreeType reports whether d is a known degree type
+func IsValidDegreeType(d string) bool {
+	return contains(DegreeTypes, d)
+}
+
+// Program is a department of a detailed faculty, flattened with its faculty
+// and university for program-level search
+type Program struct {
+	UniversityID      string   `json:"universityId"`
+	University        string   `json:"university"`
+	UniversityEn      string   `json:"universityEn"`
+	UniversityType    string   `json:"universityType"`
+	Region            string   `json:"region"`
+	City              string   `json:"city"`
+	CityEn            string   `json:"cityEn"`
+	Faculty           string   `json:"faculty"`
+	FacultyEn         string   `json:"facultyEn"`
+	Department        string   `json:"department"`
+	DepartmentEn      string   `json:"departmentEn"`
+	Duration          string   `json:"duration,omitempty"`
+	DurationEn        string   `json:"durationEn,omitempty"`
+	DurationYears     int      `json:"durationYears,omitempty"`
+	AnnualFees        int      `json:"annualFees"`
+	AnnualFeesEn      string   `json:"annualFeesEn,omitempty"`
+	Degrees           []string `json:"degrees,omitempty"`
+	DegreesEn         []string `json:"degreesEn,omitempty"`
+	DegreeTypes       []string `json:"degreeTypes,omitempty"`
+	MinGrade          int      `json:"minGrade"`
+	Rating            float64  `json:"rating"`
+	EligibilityReason string   `json:"eligibilityReason,omitempty"`
+}
+
+// ProgramSearchParams represents a program search request body
+type ProgramSearchParams struct {
+	SearchQuery           string   `json:"searchQuery"`
+	SelectedType          string   `json:"selectedType"`
+	SelectedRegion        string   `json:"selectedRegion"`
+	City                  string   `json:"city"`
+	DegreeType            string   `json:"degreeType"`
+	FilterByFees          *int     `json:"filterByFees,omitempty"`     // annual fees ceiling
+	FilterByDuration      *int     `json:"filterByDuration,omitempty"` // maximum years
+	FilterByGrade         *int     `json:"filterByGrade,omitempty"`
+	EducationalBackground string   `json:"educationalBackground"`
+	CertificateScore      *float64 `json:"certificateScore,omitempty"`
+	SortBy                string   `json:"sortBy,omitempty"`
+	SortOrder             string   `json:"sortOrder,omitempty"`
+	Page                  int      `json:"page,omitempty"`
+	PageSize              int      `json:"pageSize,omitempty"`
+}
+
+// ProgramSearchResponse represents a program search response
+type ProgramSearchResponse struct {
+	Programs   []Program `json:"programs"`
+	Total      int       `json:"total"`
+	Query      string    `json:"query"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"pageSize"`
+	TotalPages int       `json:"totalPages"`
+}
@@ -0,0 +1,66 @@
+package models
+
+// Recommendation factors, each scored from 0 to 1
+const (
+	FactorGrade      = "grade"      // margin over the admission cut-off
+	FactorBudget     = "budget"     // share of the fee range within budget
+	FactorRegion     = "region"     // in one of the preferred regions
+	FactorInterests  = "interests"  // share of interests the university teaches
+	FactorLanguage   = "language"   // teaches in the preferred language
+	FactorEmployment = "employment" // graduate employment rate
+	FactorRating     = "rating"     // overall rating
+)
+
+// RecommendationFactors lists the factors in response order
+var RecommendationFactors = []string{
+	FactorGrade, FactorBudget, FactorRegion, FactorInterests, FactorLanguage, FactorEmployment, FactorRating,
+}
+
+// IsValidFactor reports whether f is a known recommendation factor
+func IsValidFactor(f string) bool {
+	return contains(RecommendationFactors, f)
+}
+
+// RecommendationWeights maps factors to their relative weight
+type RecommendationWeights map[string]float64
+
+// RecommendationRequest is a student profile. Every field is optional;
+// factors the profile says nothing about are left out of the score.
+type RecommendationRequest struct {
+	Percentage       *float64              `json:"percentage,omitempty"`
+	Certificate      string                `json:"certificate,omitempty"`
+	CertificateScore *float64              `json:"certificateScore,omitempty"`
+	Budget           *int                  `json:"budget,omitempty"` // annual fees ceiling
+	Regions          []string              `json:"regions,omitempty"`
+	Interests        []string              `json:"interests,omitempty"` // e.g. "AI", "medicine", "الهندسة"
+	Language         string                `json:"language,omitempty"`  // language of instruction
+	Limit            int                   `json:"limit,omitempty"`
+	Weights          RecommendationWeights `json:"weights,omitempty"` // overrides the configured weights
+}
+
+// FactorScore is one factor's part of a recommendation score. Points is its
+// contribution to the 0 to 100 total.
+type FactorScore struct {
+	Factor string  `json:"factor"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+	Points float64 `json:"points"`
+	Reason string  `json:"reason"`
+}
+
+// Recommendation is a ranked university with its score breakdown and the
+// programs matching the student's interests and budget
+type Recommendation struct {
+	University University    `json:"university"`
+	Score      float64       `json:"score"`
+	Factors    []FactorScore `json:"factors"`
+	Programs   []Program     `json:"programs,omitempty"`
+}
+
+// RecommendationResponse represents a recommendation response. Total counts
+// the universities recommended before the limit was applied.
+type RecommendationResponse struct {
+	Recommendations []Recommendation      `json:"recommendations"`
+	Total           int                   `json:"total"`
+	Weights         RecommendationWeights `json:"weights"` // the weights applied
+}
@@ -12,6 +12,7 @@ type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error"`
 	Code    string `json:"code,omitempty"`
+	Details any    `json:"details,omitempty"`
 }
 
 // NewSuccessResponse creates a success response
@@ -31,3 +32,11 @@ func NewErrorResponse(err string, code string) ErrorResponse {
 		Code:    code,
 	}
 }
+
+// NewErrorResponseWithDetails creates an error response carrying extra detail,
+// such as the list of invalid fields
+func NewErrorResponseWithDetails(err string, code string, details any) ErrorResponse {
+	response := NewErrorResponse(err, code)
+	response.Details = details
+	return response
+}
@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Moderation states of a review. Reviews start pending and only approved
+// ones are published and count towards ratings.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// ReviewStatuses lists the valid values of Review.Status
+var ReviewStatuses = []string{ReviewPending, ReviewApproved, ReviewRejected}
+
+// IsValidReviewStatus reports whether s is a known moderation state
+func IsValidReviewStatus(s string) bool {
+	return contains(ReviewStatuses, s)
+}
+
+// Review is a student's review of a university, or of one of its faculties
+// when Faculty (a faculty catalogue ID) is set. Ratings are 1 to 5.
+// StatusReason is the moderator's reason for the last decision; Flags are
+// what the abuse pre-filter found and are only shown to moderators.
+type Review struct {
+	ID           string       `json:"id"`
+	UserID       string       `json:"-"`
+	UniversityID string       `json:"universityId"`
+	Faculty      string       `json:"faculty,omitempty"`
+	Overall      int          `json:"overall"`
+	Ratings      SubRatings   `json:"ratings"`
+	Title        string       `json:"title,omitempty"`
+	Body         string       `json:"body"`
+	Status       string       `json:"status"`
+	StatusReason string       `json:"statusReason,omitempty"`
+	Flags        []ReviewFlag `json:"-"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// SubRatings rate aspects of studying at a university, 0 for aspects the
+// reviewer left out
+type SubRatings struct {
+	Teaching      int `json:"teaching,omitempty"`
+	Facilities    int `json:"facilities,omitempty"`
+	Housing       int `json:"housing,omitempty"`
+	CareerSupport int `json:"careerSupport,omitempty"`
+}
+
+// ReviewRequest submits or edits a review. Faculty may be a catalogue ID or
+// name.
+type ReviewRequest struct {
+	Faculty string     `json:"faculty"`
+	Overall int        `json:"overall"`
+	Ratings SubRatings `json:"ratings"`
+	Title   string     `json:"title"`
+	Body    string     `json:"body"`
+}
+
+// ReviewSummary aggregates the approved reviews of a university. Rating is
+// the Bayesian average the university's rating is set to; Average is the
+// plain mean of the overall ratings.
+type ReviewSummary struct {
+	Count   int               `json:"count"`
+	Average float64           `json:"average"`
+	Rating  float64           `json:"rating"`
+	Ratings SubRatingAverages `json:"ratings"`
+	Stars   map[string]int    `json:"stars"`
+}
+
+// SubRatingAverages are the mean sub-ratings of reviews, omitted for
+// aspects no review rated
+type SubRatingAverages struct {
+	Teaching      float64 `json:"teaching,omitempty"`
+	Facilities    float64 `json:"facilities,omitempty"`
+	Housing       float64 `json:"housing,omitempty"`
+	CareerSupport float64 `json:"careerSupport,omitempty"`
+}
+
+// ReviewsResponse is a page of the approved reviews of a university
+type ReviewsResponse struct {
+	Reviews    []Review      `json:"reviews"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	TotalPages int           `json:"totalPages"`
+	Summary    ReviewSummary `json:"summary"`
+}
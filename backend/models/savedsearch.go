@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// SavedSearch is a university search a user named to replay it and, with
+// Notify, to hear when its results change. Matches are the results as of
+// CheckedAt, with fees in EGP.
+type SavedSearch struct {
+	ID     string       `json:"id"`
+	UserID string       `json:"-"`
+	Name   string       `json:"name"`
+	Params SearchParams `json:"params"`
+	// Currency is the currency FilterByFees is read in and results are
+	// given in
+	Currency  string        `json:"currency"`
+	Notify    bool          `json:"notify"`
+	Matches   []SearchMatch `json:"matches"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	CheckedAt time.Time     `json:"checkedAt"`
+}
+
+// SavedSearchRequest creates or replaces a saved search. Currency defaults
+// to EGP and Notify to true.
+type SavedSearchRequest struct {
+	Name     string       `json:"name"`
+	Params   SearchParams `json:"params"`
+	Currency string       `json:"currency,omitempty"`
+	Notify   *bool        `json:"notify,omitempty"`
+}
+
+// SearchMatch is a university a search found, with the fees it was compared
+// by
+type SearchMatch struct {
+	UniversityID string    `json:"universityId"`
+	Name         string    `json:"name"`
+	NameEn       string    `json:"nameEn"`
+	Fees         FeesRange `json:"fees"`
+}
+
+// FeeChange is a change in the fees of a university a search keeps finding
+type FeeChange struct {
+	UniversityID string    `json:"universityId"`
+	Name         string    `json:"name"`
+	NameEn       string    `json:"nameEn"`
+	Before       FeesRange `json:"before"`
+	After        FeesRange `json:"after"`
+}
+
+// SearchDiff is how the results of a saved search changed since it was last
+// checked
+type SearchDiff struct {
+	Added      []SearchMatch `json:"added"`
+	Removed    []SearchMatch `json:"removed"`
+	FeeChanges []FeeChange   `json:"feeChanges"`
+}
+
+// Empty reports whether nothing changed
+func (d SearchDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.FeeChanges) == 0
+}
+
+// SavedSearchRun is a replay of a saved search with the changes since it was
+// last checked
+type SavedSearchRun struct {
+	Results SearchResponse `json:"results"`
+	Changes SearchDiff     `json:"changes"`
+}
+
+// SearchNotification tells a user the results of a saved search changed
+type SearchNotification struct {
+	UserID     string     `json:"userId"`
+	Email      string     `json:"email"`
+	UserName   string     `json:"userName,omitempty"`
+	SearchID   string     `json:"searchId"`
+	SearchName string     `json:"searchName"`
+	Total      int        `json:"total"`
+	Changes    SearchDiff `json:"changes"`
+	CheckedAt  time.Time  `json:"checkedAt"`
+}
@@ -0,0 +1,47 @@
+package models
+
+// Scholarship types
+const (
+	ScholarshipMerit = "merit" // awarded on grades
+	ScholarshipNeed  = "need"  // awarded on family income
+)
+
+// ScholarshipTypes lists the valid values of Scholarship.Type
+var ScholarshipTypes = []string{ScholarshipMerit, ScholarshipNeed}
+
+// IsValidScholarshipType reports whether t is a known scholarship type
+func IsValidScholarshipType(t string) bool {
+	return contains(ScholarshipTypes, t)
+}
+
+// Scholarship is a discount on tuition at some universities. It takes off
+// either DiscountPercent of the fees or DiscountAmount a year.
+type Scholarship struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	NameEn          string   `json:"nameEn"`
+	Provider        string   `json:"provider"`
+	ProviderEn      string   `json:"providerEn"`
+	Type            string   `json:"type"`                // merit or need
+	UniversityIDs   []string `json:"universityIds"`       // eligible universities
+	Faculties       []string `json:"faculties,omitempty"` // eligible faculty IDs; every faculty when empty
+	MinGrade        float64  `json:"minGrade,omitempty"`  // Thanawiya percentage
+	DiscountPercent float64  `json:"discountPercent,omitempty"`
+	DiscountAmount  int      `json:"discountAmount,omitempty"`
+	Currency        string   `json:"currency,omitempty"` // of DiscountAmount, EGP when empty
+	Deadline        string   `json:"deadline,omitempty"` // YYYY-MM-DD
+	Documents       []string `json:"documents,omitempty"`
+	DocumentsEn     []string `json:"documentsEn,omitempty"`
+}
+
+// AppliedScholarship is a scholarship taken off a university's fees, or one
+// faculty's when Faculty is set
+type AppliedScholarship struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	NameEn          string  `json:"nameEn"`
+	Type            string  `json:"type"`
+	Faculty         string  `json:"faculty,omitempty"` // faculty ID
+	DiscountPercent float64 `json:"discountPercent,omitempty"`
+	DiscountAmount  int     `json:"discountAmount,omitempty"` // in the fees' currency
+}
@@ -2,24 +2,78 @@ package models
 
 // SearchParams represents search request body
 type SearchParams struct {
-	SearchQuery           string `json:"searchQuery"`
-	SelectedType          string `json:"selectedType"`
-	SelectedRegion        string `json:"selectedRegion"`
-	EducationalBackground string `json:"educationalBackground"`
-	FilterByFees          *int   `json:"filterByFees,omitempty"`
-	FilterByGrade         *int   `json:"filterByGrade,omitempty"`
-	SortBy                string `json:"sortBy,omitempty"`
-	SortOrder             string `json:"sortOrder,omitempty"`
-	Page                  int    `json:"page,omitempty"`
-	PageSize              int    `json:"pageSize,omitempty"`
+	SearchQuery           string   `json:"searchQuery"`
+	SelectedType          string   `json:"selectedType"`
+	SelectedRegion        string   `json:"selectedRegion"`
+	EducationalBackground string   `json:"educationalBackground"`
+	FilterByFees          *int     `json:"filterByFees,omitempty"`
+	FilterByGrade         *int     `json:"filterByGrade,omitempty"`
+	CertificateScore      *float64 `json:"certificateScore,omitempty"`
+	SortBy                string   `json:"sortBy,omitempty"`
+	SortOrder             string   `json:"sortOrder,omitempty"`
+	Page                  int      `json:"page,omitempty"`
+	PageSize              int      `json:"pageSize,omitempty"`
+	// ApplyScholarships takes the scholarships open to a student with
+	// FilterByGrade off the fees before filtering by them
+	ApplyScholarships bool `json:"applyScholarships,omitempty"`
 }
 
 // SearchResponse represents search response
 type SearchResponse struct {
-	Universities []University `json:"universities"`
-	Total        int          `json:"total"`
-	Query        string       `json:"query"`
-	Page         int          `json:"page"`
-	PageSize     int          `json:"pageSize"`
-	TotalPages   int          `json:"totalPages"`
+	Universities []University           `json:"universities"`
+	Total        int                    `json:"total"`
+	Query        string                 `json:"query"`
+	Page         int                    `json:"page"`
+	PageSize     int                    `json:"pageSize"`
+	TotalPages   int                    `json:"totalPages"`
+	Eligibility  map[string]Eligibility `json:"eligibility,omitempty"`
+	Scores       map[string]float64     `json:"scores,omitempty"`
+	Suggestions  []Suggestion           `json:"suggestions,omitempty"`
+	Facets       *SearchFacets          `json:"facets,omitempty"`
+	// Scholarships lists those applied to each university's fees by ID
+	Scholarships map[string][]AppliedScholarship `json:"scholarships,omitempty"`
+}
+
+// Suggestion is a "did you mean" name offered when a search finds nothing
+type Suggestion struct {
+	Text         string  `json:"text"`
+	Type         string  `json:"type"` // university, faculty
+	UniversityID string  `json:"universityId,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// AutocompleteSuggestion is a name offered while the user types in the
+// search bar
+type AutocompleteSuggestion struct {
+	Type         string `json:"type"` // university, faculty, specialty, city
+	Text         string `json:"text"` // the name in the requested language
+	Name         string `json:"name,omitempty"`
+	NameEn       string `json:"nameEn,omitempty"`
+	UniversityID string `json:"universityId,omitempty"`
+	Count        int    `json:"count"` // universities the name appears in
+}
+
+// SearchFacets counts the results each filter choice would give. Every
+// facet is computed over the current search with its own filter left out.
+type SearchFacets struct {
+	Types     map[string]int `json:"types"`
+	Regions   map[string]int `json:"regions"`
+	Faculties []FacetCount   `json:"faculties"`
+	Fees      []FacetBucket  `json:"fees"`   // by maximum annual fees
+	Grades    []FacetBucket  `json:"grades"` // by minimum grade
+}
+
+// FacetCount is the number of results offering a faculty
+type FacetCount struct {
+	Value   string `json:"value"`
+	ValueEn string `json:"valueEn,omitempty"`
+	Count   int    `json:"count"`
+}
+
+// FacetBucket is a histogram bucket covering [Min, Max); Max is omitted for
+// the open-ended last bucket
+type FacetBucket struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max,omitempty"`
+	Count int  `json:"count"`
 }
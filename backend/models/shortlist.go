@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Favorite is a university a user bookmarked
+type Favorite struct {
+	UserID       string    `json:"-"`
+	UniversityID string    `json:"universityId"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// FavoriteRequest bookmarks a university
+type FavoriteRequest struct {
+	UniversityID string `json:"universityId" binding:"required"`
+}
+
+// FavoriteEntry is a favorite with the current data of its university
+type FavoriteEntry struct {
+	Favorite
+	University University `json:"university"`
+}
+
+// Shortlist is a named list of universities, or faculties and departments
+// of them, a user is considering
+type Shortlist struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"-"`
+	Name      string          `json:"name"`
+	Items     []ShortlistItem `json:"items"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// ShortlistItem is one entry of a shortlist. Faculty is a faculty catalogue
+// ID and Department the Arabic name of a department of that faculty; either
+// may be empty to refer to the whole university or faculty.
+type ShortlistItem struct {
+	UniversityID string `json:"universityId"`
+	Faculty      string `json:"faculty,omitempty"`
+	Department   string `json:"department,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+// ShortlistRequest creates or replaces a shortlist. Items may name their
+// faculty and department in Arabic or English.
+type ShortlistRequest struct {
+	Name  string          `json:"name"`
+	Items []ShortlistItem `json:"items"`
+}
+
+// ShortlistEntry is a shortlist item with the current data of what it
+// refers to
+type ShortlistEntry struct {
+	ShortlistItem
+	University     University  `json:"university"`
+	FacultyName    string      `json:"facultyName,omitempty"`
+	FacultyNameEn  string      `json:"facultyNameEn,omitempty"`
+	DepartmentInfo *Department `json:"departmentInfo,omitempty"`
+}
+
+// ShortlistView is a shortlist with its items resolved
+type ShortlistView struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Items     []ShortlistEntry `json:"items"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
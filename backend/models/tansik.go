@@ -0,0 +1,61 @@
+package models
+
+// Outcomes of a Tansik preference
+const (
+	PreferenceAdmitted    = "admitted"      // the first preference whose cut-off is met
+	PreferenceBelowCutoff = "below_cutoff"  // the grade is under the cut-off
+	PreferenceNotReached  = "not_reached"   // listed after the admitted preference
+	PreferenceNoCutoff    = "no_cutoff"     // no cut-off on record to compare with
+	PreferenceOutOfZone   = "out_of_zone"   // excluded by geographic distribution
+	PreferenceWrongTrack  = "wrong_track"   // the faculty does not admit the track
+	PreferenceWrongGender = "wrong_gender"  // the faculty admits the other gender only
+	PreferenceNotInTansik = "not_in_tansik" // the university admits directly
+	PreferenceDuplicate   = "duplicate"     // repeats an earlier preference
+	PreferenceUnknown     = "unknown"       // no such university or faculty
+)
+
+// TansikPreference is one entry of a student's ordered preference list
+type TansikPreference struct {
+	UniversityID string `json:"universityId"`
+	Faculty      string `json:"faculty"` // faculty ID, Arabic or English name
+}
+
+// TansikSimulationRequest represents a Tansik simulation request body. The
+// grade is given as a percentage, or as a score with its maximum.
+type TansikSimulationRequest struct {
+	Percentage  *float64           `json:"percentage,omitempty"`
+	Score       *float64           `json:"score,omitempty"`
+	MaxScore    float64            `json:"maxScore,omitempty"`
+	Track       string             `json:"track"`
+	Gender      string             `json:"gender"`
+	Zone        string             `json:"zone"`           // region the student applies from
+	Year        int                `json:"year,omitempty"` // cut-off year, latest when omitted
+	Preferences []TansikPreference `json:"preferences"`
+}
+
+// TansikPreferenceResult is the outcome of one preference. Wasted entries
+// can never change where the student is placed.
+type TansikPreferenceResult struct {
+	Rank         int      `json:"rank"` // 1-based position in the list
+	UniversityID string   `json:"universityId"`
+	University   string   `json:"university,omitempty"`
+	UniversityEn string   `json:"universityEn,omitempty"`
+	FacultyID    string   `json:"facultyId,omitempty"`
+	Faculty      string   `json:"faculty,omitempty"`
+	FacultyEn    string   `json:"facultyEn,omitempty"`
+	Cutoff       *float64 `json:"cutoff,omitempty"`
+	CutoffYear   int      `json:"cutoffYear,omitempty"`
+	Margin       *float64 `json:"margin,omitempty"`
+	Status       string   `json:"status"`
+	Wasted       bool     `json:"wasted"`
+	Reason       string   `json:"reason"`
+}
+
+// TansikSimulationResponse represents a Tansik simulation response. Admitted
+// is nil when no preference admits the student.
+type TansikSimulationResponse struct {
+	Percentage  float64                  `json:"percentage"`
+	Admitted    *TansikPreferenceResult  `json:"admitted"`
+	Preferences []TansikPreferenceResult `json:"preferences"`
+	Wasted      []int                    `json:"wasted"` // ranks of wasted preferences
+}
@@ -1,5 +1,38 @@
 package models
 
+// UniversityTypes lists the valid values of University.Type
+var UniversityTypes = []string{"public", "private", "national", "azhar"}
+
+// Regions lists the valid values of University.Region
+var Regions = []string{"cairo", "alexandria", "delta", "upper-egypt", "suez-canal"}
+
+// Languages lists the valid values of University.Languages
+var Languages = []string{"arabic", "english", "french", "german"}
+
+// IsValidType reports whether t is a known university type
+func IsValidType(t string) bool {
+	return contains(UniversityTypes, t)
+}
+
+// IsValidRegion reports whether r is a known region
+func IsValidRegion(r string) bool {
+	return contains(Regions, r)
+}
+
+// IsValidLanguage reports whether l is a known language of instruction
+func IsValidLanguage(l string) bool {
+	return contains(Languages, l)
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
 // University represents a university entity
 type University struct {
 	ID                     string                     `json:"id"`
@@ -10,11 +43,13 @@ type University struct {
 	LocationEn             string                     `json:"locationEn"`
 	Region                 string                     `json:"region"`
 	Established            int                        `json:"established"`
-	Rating                 float64                    `json:"rating"`
+	Rating                 float64                    `json:"rating"` // EditorialRating adjusted by approved reviews; read-only
+	EditorialRating        float64                    `json:"editorialRating,omitempty"` // set by editors
 	Fees                   FeesRange                  `json:"fees"`
 	Faculties              []string                   `json:"faculties"`
 	FacultiesEn            []string                   `json:"facultiesEn"`
 	Specialties            []string                   `json:"specialties"`
+	Languages              []string                   `json:"languages,omitempty"` // languages of instruction
 	Description            string                     `json:"description"`
 	DescriptionEn          string                     `json:"descriptionEn"`
 	Image                  string                     `json:"image,omitempty"`
@@ -24,12 +59,16 @@ type University struct {
 	AcceptanceRate         int                        `json:"acceptanceRate,omitempty"`
 	EmploymentRate         int                        `json:"employmentRate,omitempty"`
 	DetailedFaculties      map[string]Faculty         `json:"detailedFaculties,omitempty"`
+	Admission              []AdmissionRule            `json:"admission,omitempty"`
+	Costs                  *CostModel                 `json:"costs,omitempty"`
 }
 
 // FeesRange represents min/max fee range
 type FeesRange struct {
-	Min int `json:"min"`
-	Max int `json:"max"`
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	Currency string `json:"currency,omitempty"` // ISO 4217 code, EGP when empty
+	Period   string `json:"period,omitempty"`   // annual when empty
 }
 
 // Faculty represents a faculty within a university
@@ -43,6 +82,7 @@ type Faculty struct {
 	CurrencyEn      string           `json:"currencyEn,omitempty"`
 	Departments     []Department     `json:"departments,omitempty"`
 	Specializations []Specialization `json:"specializations,omitempty"`
+	Admission       []AdmissionRule  `json:"admission,omitempty"`
 }
 
 // Department represents a department within a faculty
@@ -53,14 +93,18 @@ type Department struct {
 	DurationEn string   `json:"durationEn,omitempty"`
 	Fees       int      `json:"fees,omitempty"`
 	FeesEn     string   `json:"feesEn,omitempty"`
+	Currency   string   `json:"currency,omitempty"` // the faculty's when empty
+	Period     string   `json:"period,omitempty"`   // the faculty's when empty
 	Degrees    []string `json:"degrees,omitempty"`
 	DegreesEn  []string `json:"degreesEn,omitempty"`
 }
 
 // Specialization represents a specialization within a faculty
 type Specialization struct {
-	Name   string `json:"name"`
-	NameEn string `json:"nameEn"`
-	Fees   int    `json:"fees,omitempty"`
-	FeesEn string `json:"feesEn,omitempty"`
+	Name     string `json:"name"`
+	NameEn   string `json:"nameEn"`
+	Fees     int    `json:"fees,omitempty"`
+	FeesEn   string `json:"feesEn,omitempty"`
+	Currency string `json:"currency,omitempty"` // the faculty's when empty
+	Period   string `json:"period,omitempty"`   // the faculty's when empty
 }
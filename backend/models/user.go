@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Account roles. Students use the personal features, counsellors read the
+// shortlists of students, moderators review student reviews, editors maintain the
+// catalogue and admins also manage accounts.
+const (
+	RoleStudent    = "student"
+	RoleCounsellor = "counsellor"
+	RoleModerator  = "moderator"
+	RoleEditor     = "editor"
+	RoleAdmin      = "admin"
+)
+
+// Roles lists the valid values of User.Role
+var Roles = []string{RoleStudent, RoleCounsellor, RoleModerator, RoleEditor, RoleAdmin}
+
+// IsValidRole reports whether r is a known role
+func IsValidRole(r string) bool {
+	return contains(Roles, r)
+}
+
+// User is an account. PasswordHash is never serialised.
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name,omitempty"`
+	Role         string    `json:"role"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// RefreshToken is a long-lived credential exchanged for new access tokens.
+// Only a hash of the token is kept.
+type RefreshToken struct {
+	Hash      string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// RegisterRequest creates a student account
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name,omitempty"`
+}
+
+// LoginRequest exchanges credentials for tokens
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest exchanges, or revokes, a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RoleRequest changes the role of an account
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
+// TokenResponse holds a short-lived access token, sent as a bearer token,
+// and the refresh token that renews it. ExpiresIn is in seconds.
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+	RefreshToken string `json:"refreshToken"`
+	User         User   `json:"user"`
+}
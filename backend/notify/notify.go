@@ -0,0 +1,226 @@
+// Package notify delivers saved-search change notifications to users by log,
+// email or webhook
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"roadtouniversities/models"
+)
+
+// Notifier kinds selectable through configuration
+const (
+	KindLog     = "log"
+	KindEmail   = "email"
+	KindWebhook = "webhook"
+	KindNone    = "none"
+)
+
+// ErrUnknownKind is returned by Open for an unsupported notifier name
+var ErrUnknownKind = errors.New("unknown notifier")
+
+// timeout bounds the delivery of one notification
+const timeout = 10 * time.Second
+
+// Notifier delivers notifications. Implementations are safe for concurrent
+// use.
+type Notifier interface {
+	Notify(ctx context.Context, n models.SearchNotification) error
+}
+
+// Config selects and configures a Notifier
+type Config struct {
+	Kind string
+	// SMTPAddr is the host:port of the mail server for KindEmail, such as a
+	// local stand-in like MailHog
+	SMTPAddr string
+	// From is the sender address of emails
+	From string
+	// WebhookURL receives notifications as JSON for KindWebhook
+	WebhookURL string
+}
+
+// Open creates the notifier described by cfg
+func Open(cfg Config) (Notifier, error) {
+	switch cfg.Kind {
+	case "", KindLog:
+		return Log{}, nil
+	case KindEmail:
+		if cfg.SMTPAddr == "" || cfg.From == "" {
+			return nil, errors.New("notify: email needs an SMTP address and a sender")
+		}
+		return Email{Addr: cfg.SMTPAddr, From: cfg.From}, nil
+	case KindWebhook:
+		if !strings.HasPrefix(cfg.WebhookURL, "http://") && !strings.HasPrefix(cfg.WebhookURL, "https://") {
+			return nil, fmt.Errorf("notify: webhook URL must be http or https, got %q", cfg.WebhookURL)
+		}
+		return Webhook{URL: cfg.WebhookURL, Client: &http.Client{Timeout: timeout}}, nil
+	case KindNone:
+		return Discard{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, cfg.Kind)
+	}
+}
+
+// Log writes notifications to the standard logger
+type Log struct{}
+
+// Notify logs a summary of n
+func (Log) Notify(_ context.Context, n models.SearchNotification) error {
+	log.Printf("🔔 saved search %q of %s changed: %s", n.SearchName, n.Email, Summary(n.Changes))
+	return nil
+}
+
+// Discard drops notifications
+type Discard struct{}
+
+// Notify does nothing
+func (Discard) Notify(context.Context, models.SearchNotification) error {
+	return nil
+}
+
+// Webhook posts notifications as JSON
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts n to the webhook, failing on a non-2xx response
+func (w Webhook) Notify(ctx context.Context, n models.SearchNotification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s responded %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+// Email sends notifications as plain-text mail through an SMTP server
+// without authentication
+type Email struct {
+	Addr string
+	From string
+}
+
+// Notify mails n to the user
+func (e Email) Notify(ctx context.Context, n models.SearchNotification) error {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	// The dialer's timeout only covers connecting; bound the conversation too
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("email: %w", err)
+	}
+	host, _, _ := net.SplitHostPort(e.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("email: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Mail(e.From); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := client.Rcpt(n.Email); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if _, err := w.Write(message(e.From, n)); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return client.Quit()
+}
+
+// message renders n as an email with CRLF line endings
+func message(from string, n models.SearchNotification) []byte {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format+"\r\n", args...)
+	}
+
+	line("From: %s", from)
+	line("To: %s", n.Email)
+	line("Subject: Your saved search %q has new results", strings.ReplaceAll(n.SearchName, "\n", " "))
+	line("Date: %s", n.CheckedAt.Format(time.RFC1123Z))
+	line("MIME-Version: 1.0")
+	line("Content-Type: text/plain; charset=utf-8")
+	line("")
+	line("The results of %q changed (%s); it now finds %d universities.", n.SearchName, Summary(n.Changes), n.Total)
+	if len(n.Changes.Added) > 0 {
+		line("")
+		line("New matches:")
+		for _, m := range n.Changes.Added {
+			line("  + %s (%s)", m.NameEn, fees(m.Fees))
+		}
+	}
+	if len(n.Changes.Removed) > 0 {
+		line("")
+		line("No longer matching:")
+		for _, m := range n.Changes.Removed {
+			line("  - %s", m.NameEn)
+		}
+	}
+	if len(n.Changes.FeeChanges) > 0 {
+		line("")
+		line("Fee changes:")
+		for _, fc := range n.Changes.FeeChanges {
+			line("  * %s: %s -> %s", fc.NameEn, fees(fc.Before), fees(fc.After))
+		}
+	}
+	return []byte(b.String())
+}
+
+// Summary counts the changes of a diff, e.g. "2 new, 1 dropped, 1 fee change"
+func Summary(d models.SearchDiff) string {
+	parts := []string{fmt.Sprintf("%d new", len(d.Added)), fmt.Sprintf("%d dropped", len(d.Removed))}
+	if n := len(d.FeeChanges); n == 1 {
+		parts = append(parts, "1 fee change")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d fee changes", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fees(f models.FeesRange) string {
+	if f.Min == f.Max {
+		return fmt.Sprintf("%d %s", f.Max, f.Currency)
+	}
+	return fmt.Sprintf("%d-%d %s", f.Min, f.Max, f.Currency)
+}
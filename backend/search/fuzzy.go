@@ -0,0 +1,216 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// fuzzyPenalty scales the score of a term matched with edits, per edit
+const fuzzyPenalty = 0.6
+
+// maxEdits is the typo budget for a term of the given length in runes
+func maxEdits(length int) int {
+	switch {
+	case length <= 2:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// EditDistance returns the optimal string alignment distance between a and b
+// (Levenshtein plus adjacent transpositions), counted in runes
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// Three rolling rows: two back, previous and current
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// trigrams returns the padded character trigrams of s
+func trigrams(s string) []string {
+	runes := []rune(" " + s + " ")
+	if len(runes) < 3 {
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+// similarity is the Dice coefficient of the trigram sets of a and b
+func similarity(a, b string) float64 {
+	ga, gb := trigrams(a), trigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	set := make(map[string]int, len(ga))
+	for _, g := range ga {
+		set[g]++
+	}
+	shared := 0
+	for _, g := range gb {
+		if set[g] > 0 {
+			set[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ga)+len(gb))
+}
+
+// variant is an indexed term that stands in for a query term
+type variant struct {
+	term   string
+	weight float64
+}
+
+// variants returns the indexed terms a query token matches: its own term when
+// indexed, otherwise the terms of indexed words within the token's typo
+// budget, weighted down per edit. Words are compared before stemming so that
+// "engineerng" still reaches "engineering".
+func (ix *Index) variants(tok token) []variant {
+	if _, found := ix.postings[tok.term]; found {
+		return []variant{{term: tok.term, weight: 1}}
+	}
+
+	length := utf8.RuneCountInString(tok.word)
+	budget := maxEdits(length)
+	if budget == 0 {
+		return nil
+	}
+
+	candidates := make(map[string]bool)
+	for _, gram := range trigrams(tok.word) {
+		for _, candidate := range ix.wordGrams[gram] {
+			candidates[candidate] = true
+		}
+	}
+
+	weights := make(map[string]float64)
+	for candidate := range candidates {
+		diff := utf8.RuneCountInString(candidate) - length
+		if diff > budget || -diff > budget {
+			continue
+		}
+		d := EditDistance(tok.word, candidate)
+		if d > budget {
+			continue
+		}
+		weight := 1.0
+		for i := 0; i < d; i++ {
+			weight *= fuzzyPenalty
+		}
+		if term := ix.words[candidate]; weight > weights[term] {
+			weights[term] = weight
+		}
+	}
+
+	result := make([]variant, 0, len(weights))
+	for term, weight := range weights {
+		result = append(result, variant{term: term, weight: weight})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].term < result[j].term })
+	return result
+}
+
+// SuggestionKind tells what a suggestion names
+type SuggestionKind string
+
+const (
+	SuggestUniversity SuggestionKind = "university"
+	SuggestFaculty    SuggestionKind = "faculty"
+)
+
+// Suggestion is a name close to a query that found nothing
+type Suggestion struct {
+	Text  string
+	Kind  SuggestionKind
+	ID    string // university ID for university suggestions
+	Score float64
+}
+
+// nameEntry is a university or faculty name used for suggestions
+type nameEntry struct {
+	text       string
+	normalized string
+	kind       SuggestionKind
+	id         string
+}
+
+// addName records a name for suggestions, skipping duplicates
+func (ix *Index) addName(text string, kind SuggestionKind, id string) {
+	normalized := strings.Join(Tokenize(text), " ")
+	if normalized == "" {
+		return
+	}
+	key := string(kind) + "\x00" + normalized
+	if ix.nameSeen[key] {
+		return
+	}
+	ix.nameSeen[key] = true
+	ix.names = append(ix.names, nameEntry{text: text, normalized: normalized, kind: kind, id: id})
+}
+
+// minSuggestionScore drops suggestions that share too little with the query
+const minSuggestionScore = 0.3
+
+// Suggest returns up to limit university and faculty names closest to query
+// by trigram similarity of their normalised forms
+func (ix *Index) Suggest(query string, limit int) []Suggestion {
+	normalized := strings.Join(Tokenize(query), " ")
+	if normalized == "" || limit <= 0 {
+		return nil
+	}
+
+	var result []Suggestion
+	for _, name := range ix.names {
+		score := similarity(normalized, name.normalized)
+		if score < minSuggestionScore {
+			continue
+		}
+		result = append(result, Suggestion{Text: name.text, Kind: name.kind, ID: name.id, Score: round(score)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Text < result[j].Text
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"cairo", "cairo", 0},
+		{"cairo", "kairo", 1},
+		{"engineering", "engineerng", 1},
+		{"engineering", "enginering", 1},
+		{"medicine", "medcine", 1},
+		{"cario", "cairo", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"هندسة", "هندسه", 1},
+		{"القاهرة", "القاهره", 1},
+		{"طب", "بط", 1},
+	}
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := EditDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	for length, want := range map[int]int{1: 0, 2: 0, 3: 1, 5: 1, 6: 2, 12: 2} {
+		if got := maxEdits(length); got != want {
+			t.Errorf("maxEdits(%d) = %d, want %d", length, got, want)
+		}
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{" a "}},
+		{"cat", []string{" ca", "cat", "at "}},
+		{"طب", []string{" طب", "طب "}},
+	}
+	for _, tt := range tests {
+		if got := trigrams(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("trigrams(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := similarity("cairo", "cairo"); got != 1 {
+		t.Errorf("identical strings: %g, want 1", got)
+	}
+	if got := similarity("cairo", "xyz"); got != 0 {
+		t.Errorf("disjoint strings: %g, want 0", got)
+	}
+	close, far := similarity("cairo univers", "cairo"), similarity("ain sham univers", "cairo")
+	if close <= far {
+		t.Errorf("similarity ranks %g (close) below %g (far)", close, far)
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	ix := Build(testCatalogue())
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"missing letter", "enginering", []string{"3", "1", "2"}},
+		{"transposition", "cario giza", []string{"1"}},
+		{"arabic typo", "الهندصة", []string{"3", "1"}},
+		{"budget exceeded", "enginxxxxng", []string{}},
+		{"short words get no typos", "ar", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hitIDs(ix.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	// A typo scores below the exact spelling
+	exact, typo := ix.Search("medicine"), ix.Search("medcine")
+	if len(exact) == 0 || len(typo) != len(exact) || typo[0].Score >= exact[0].Score {
+		t.Fatalf("medicine = %v, medcine = %v", exact, typo)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := Build(testCatalogue())
+
+	got := ix.Suggest("Alexandria Universty", 3)
+	if len(got) == 0 || got[0].Text != "Alexandria University" || got[0].Kind != SuggestUniversity || got[0].ID != "4" {
+		t.Fatalf("Suggest = %+v, want Alexandria University first", got)
+	}
+	if len(got) > 3 {
+		t.Fatalf("Suggest returned %d suggestions, limit 3", len(got))
+	}
+
+	faculties := ix.Suggest("enginering", 5)
+	if len(faculties) == 0 || faculties[0].Kind != SuggestFaculty || faculties[0].Text != "Engineering" {
+		t.Fatalf("Suggest(enginering) = %+v, want the Engineering faculty first", faculties)
+	}
+	for i, s := range faculties {
+		if s.Text == "Engineering" && i > 0 {
+			t.Fatalf("duplicate faculty name suggested: %+v", faculties)
+		}
+	}
+
+	if got := ix.Suggest("zzzz", 5); len(got) != 0 {
+		t.Fatalf("unrelated query suggested %+v", got)
+	}
+	if got := ix.Suggest("cairo", 0); got != nil {
+		t.Fatalf("limit 0 suggested %+v", got)
+	}
+}
@@ -0,0 +1,339 @@
+// Package search implements the in-process full-text index over the
+// university catalogue, with Arabic orthographic normalisation and English
+// stemming.
+package search
+
+import (
+	"math"
+	"sort"
+
+	"roadtouniversities/models"
+)
+
+// Field boosts: a match in a university name outweighs a faculty or program
+// match, which outweighs a description match
+const (
+	BoostName        = 5.0
+	BoostFaculty     = 3.0
+	BoostSpecialty   = 2.5
+	BoostLocation    = 2.0
+	BoostDescription = 1.0
+)
+
+// BoostPhrase multiplies the score of a university whose name holds the
+// query as a phrase. A university named exactly as the query ranks above
+// every other match.
+const BoostPhrase = 1.5
+
+// field identifies which part of a document a term came from
+type field int
+
+const (
+	fieldName field = iota
+	fieldFaculty
+	fieldSpecialty
+	fieldLocation
+	fieldDescription
+	numFields
+)
+
+var fieldBoosts = [numFields]float64{
+	fieldName:        BoostName,
+	fieldFaculty:     BoostFaculty,
+	fieldSpecialty:   BoostSpecialty,
+	fieldLocation:    BoostLocation,
+	fieldDescription: BoostDescription,
+}
+
+// posting records how often a term occurs in one field of one document
+type posting struct {
+	doc   int
+	field field
+	freq  int
+}
+
+// Hit is a matching university with its relevance score
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is an inverted index over the bilingual text of universities and
+// their detailed faculties. It is immutable once built and safe for
+// concurrent use.
+type Index struct {
+	ids       []string
+	postings  map[string][]posting
+	fieldLens [][numFields]int
+	avgLens   [numFields]float64
+	// nameTerms holds the terms of each document's Arabic and English names
+	nameTerms [][2][]string
+
+	// words maps each indexed word, before stemming, to its term, and
+	// wordGrams maps trigrams to those words for typo-tolerant lookup
+	words     map[string]string
+	wordGrams map[string][]string
+	// names holds university and faculty names for suggestions
+	names    []nameEntry
+	nameSeen map[string]bool
+}
+
+// Build indexes the given universities
+func Build(unis []models.University) *Index {
+	ix := &Index{
+		ids:       make([]string, len(unis)),
+		postings:  make(map[string][]posting),
+		fieldLens: make([][numFields]int, len(unis)),
+		nameTerms: make([][2][]string, len(unis)),
+		words:     make(map[string]string),
+		wordGrams: make(map[string][]string),
+		nameSeen:  make(map[string]bool),
+	}
+
+	for doc, uni := range unis {
+		ix.ids[doc] = uni.ID
+		ix.nameTerms[doc] = [2][]string{Tokenize(uni.Name), Tokenize(uni.NameEn)}
+		ix.addNames(uni)
+		for f, texts := range documentFields(uni) {
+			counts := make(map[string]int)
+			for _, text := range texts {
+				for _, tok := range tokenize(text) {
+					counts[tok.term]++
+					ix.fieldLens[doc][f]++
+					ix.words[tok.word] = tok.term
+				}
+			}
+			for term, freq := range counts {
+				ix.postings[term] = append(ix.postings[term], posting{doc: doc, field: field(f), freq: freq})
+			}
+		}
+	}
+
+	for word := range ix.words {
+		for _, gram := range trigrams(word) {
+			ix.wordGrams[gram] = append(ix.wordGrams[gram], word)
+		}
+	}
+
+	if len(unis) > 0 {
+		for _, lens := range ix.fieldLens {
+			for f, n := range lens {
+				ix.avgLens[f] += float64(n)
+			}
+		}
+		for f := range ix.avgLens {
+			ix.avgLens[f] /= float64(len(unis))
+		}
+	}
+	return ix
+}
+
+// documentFields collects the text of each indexed field of a university
+func documentFields(uni models.University) [numFields][]string {
+	var fields [numFields][]string
+
+	fields[fieldName] = []string{uni.Name, uni.NameEn}
+	fields[fieldFaculty] = append(append([]string{}, uni.Faculties...), uni.FacultiesEn...)
+	fields[fieldSpecialty] = append([]string{}, uni.Specialties...)
+	fields[fieldLocation] = []string{uni.Location, uni.LocationEn}
+	fields[fieldDescription] = []string{uni.Description, uni.DescriptionEn}
+
+	for key, faculty := range uni.DetailedFaculties {
+		fields[fieldFaculty] = append(fields[fieldFaculty], key, faculty.NameEn)
+		for _, dept := range faculty.Departments {
+			fields[fieldSpecialty] = append(fields[fieldSpecialty], dept.Name, dept.NameEn)
+		}
+		for _, spec := range faculty.Specializations {
+			fields[fieldSpecialty] = append(fields[fieldSpecialty], spec.Name, spec.NameEn)
+		}
+		fields[fieldDescription] = append(fields[fieldDescription], faculty.Description, faculty.DescriptionEn)
+	}
+	return fields
+}
+
+// addNames records the university's names and faculty names for suggestions
+func (ix *Index) addNames(uni models.University) {
+	ix.addName(uni.Name, SuggestUniversity, uni.ID)
+	ix.addName(uni.NameEn, SuggestUniversity, uni.ID)
+	for _, name := range uni.Faculties {
+		ix.addName(name, SuggestFaculty, "")
+	}
+	for _, name := range uni.FacultiesEn {
+		ix.addName(name, SuggestFaculty, "")
+	}
+}
+
+// Len returns the number of indexed universities
+func (ix *Index) Len() int {
+	return len(ix.ids)
+}
+
+// Terms returns every indexed term
+func (ix *Index) Terms() []string {
+	terms := make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// Search returns the universities matching every term of query, ordered by
+// descending BM25 score with field boosts, raised for a query matching the
+// name as a phrase or exactly. Terms missing from the index are matched
+// against indexed terms within a small edit distance, at a reduced score. An
+// empty query matches nothing.
+func (ix *Index) Search(query string) []Hit {
+	all := tokenize(query)
+	tokens := uniqueTokens(all)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for i, tok := range tokens {
+		// Best variant score per document for this query term
+		best := make(map[int]float64)
+		for _, v := range ix.variants(tok) {
+			for doc, score := range ix.termScores(v.term) {
+				if weighted := score * v.weight; weighted > best[doc] {
+					best[doc] = weighted
+				}
+			}
+		}
+		for doc, score := range best {
+			if matched[doc] == i {
+				matched[doc] = i + 1
+				scores[doc] += score
+			}
+		}
+	}
+
+	terms := make([]string, len(all))
+	for i, tok := range all {
+		terms[i] = tok.term
+	}
+	required := len(tokens)
+	top := 0.0
+	var exact []int
+	for doc, score := range scores {
+		if matched[doc] != required {
+			delete(scores, doc)
+			continue
+		}
+		switch ix.nameMatch(doc, terms) {
+		case matchExact:
+			exact = append(exact, doc)
+		case matchPhrase:
+			scores[doc] = score * BoostPhrase
+		}
+		top = math.Max(top, scores[doc])
+	}
+	for _, doc := range exact {
+		scores[doc] += top
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		hits = append(hits, Hit{ID: ix.ids[doc], Score: round(score)})
+	}
+	sortHits(hits)
+	return hits
+}
+
+// How a query matches a university name
+const (
+	matchNone = iota
+	matchPhrase
+	matchExact
+)
+
+// nameMatch reports whether terms are one of the document's names, or occur
+// in one as a phrase of at least two terms
+func (ix *Index) nameMatch(doc int, terms []string) int {
+	match := matchNone
+	for _, name := range ix.nameTerms[doc] {
+		if equalTerms(name, terms) {
+			return matchExact
+		}
+		if len(terms) < 2 {
+			continue
+		}
+		for i := 0; i+len(terms) <= len(name); i++ {
+			if equalTerms(name[i:i+len(terms)], terms) {
+				match = matchPhrase
+			}
+		}
+	}
+	return match
+}
+
+func equalTerms(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// termScores computes each document's score for a single term
+func (ix *Index) termScores(term string) map[int]float64 {
+	const k1, b = 1.2, 0.75
+
+	postings := ix.postings[term]
+	if len(postings) == 0 {
+		return nil
+	}
+
+	docs := make(map[int]bool)
+	for _, p := range postings {
+		docs[p.doc] = true
+	}
+	n := float64(len(ix.ids))
+	df := float64(len(docs))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	scores := make(map[int]float64, len(docs))
+	for _, p := range postings {
+		tf := float64(p.freq)
+		norm := 1.0
+		if avg := ix.avgLens[p.field]; avg > 0 {
+			norm = 1 - b + b*float64(ix.fieldLens[p.doc][p.field])/avg
+		}
+		scores[p.doc] += fieldBoosts[p.field] * idf * tf * (k1 + 1) / (tf + k1*norm)
+	}
+	return scores
+}
+
+// uniqueTokens drops tokens whose term already appeared
+func uniqueTokens(tokens []token) []token {
+	seen := make(map[string]bool, len(tokens))
+	var result []token
+	for _, tok := range tokens {
+		if !seen[tok.term] {
+			seen[tok.term] = true
+			result = append(result, tok)
+		}
+	}
+	return result
+}
+
+// sortHits orders hits by descending score, then ID
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+}
+
+// round keeps scores readable in responses
+func round(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
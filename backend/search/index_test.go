@@ -0,0 +1,142 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func testCatalogue() []models.University {
+	return []models.University{
+		{
+			ID: "1", Name: "جامعة القاهرة", NameEn: "Cairo University",
+			Location: "الجيزة", LocationEn: "Giza",
+			Faculties: []string{"الطب", "الهندسة"}, FacultiesEn: []string{"Medicine", "Engineering"},
+			Description: "أقدم جامعة حكومية", DescriptionEn: "The oldest public university",
+		},
+		{
+			ID: "2", Name: "الجامعة الأمريكية بالقاهرة", NameEn: "American University in Cairo",
+			Location: "القاهرة الجديدة", LocationEn: "New Cairo",
+			Faculties: []string{"إدارة الأعمال"}, FacultiesEn: []string{"Business"},
+			DescriptionEn: "A private university in Cairo with engineering programs",
+		},
+		{
+			ID: "3", Name: "جامعة عين شمس", NameEn: "Ain Shams University",
+			Location: "القاهرة", LocationEn: "Cairo",
+			Faculties: []string{"الهندسة", "الآداب"}, FacultiesEn: []string{"Engineering", "Arts"},
+			DetailedFaculties: map[string]models.Faculty{
+				"الهندسة": {
+					NameEn:      "Faculty of Engineering",
+					Departments: []models.Department{{Name: "هندسة الحاسبات", NameEn: "Computer Engineering"}},
+				},
+			},
+		},
+		{
+			ID: "4", Name: "جامعة الإسكندرية", NameEn: "Alexandria University",
+			Location: "الإسكندرية", LocationEn: "Alexandria",
+			Faculties: []string{"الطب"}, FacultiesEn: []string{"Medicine"},
+		},
+	}
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	ix := Build(testCatalogue())
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty query", "", nil},
+		{"stop words only", "the of", nil},
+		{"no match", "pharmacy", []string{}},
+		{"every term must match", "medicine alexandria", []string{"4"}},
+		{"faculty outweighs description", "engineering", []string{"3", "1", "2"}},
+		{"arabic variants and articles", "هندسه", []string{"3", "1"}},
+		{"stemmed english", "engineers", []string{"3", "1", "2"}},
+		{"department names", "computer", []string{"3"}},
+		{"exact arabic name ranks first", "جامعه القاهره", []string{"1", "2", "3"}},
+		{"exact english name ranks first", "cairo university", []string{"1", "2", "3"}},
+		{"phrase in name ranks first", "american university", []string{"2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := ix.Search(tt.query)
+			if tt.want == nil {
+				if hits != nil {
+					t.Fatalf("Search(%q) = %v, want nil", tt.query, hits)
+				}
+				return
+			}
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, hits, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchScores(t *testing.T) {
+	ix := Build(testCatalogue())
+
+	// A rare term weighs more than a common one
+	rare := ix.Search("giza")
+	common := ix.Search("cairo")
+	if len(rare) != 1 || len(common) < 2 {
+		t.Fatalf("giza = %v, cairo = %v", rare, common)
+	}
+	for _, h := range common {
+		if h.ID == "1" && h.Score >= rare[0].Score {
+			t.Fatalf("common term scored %g, rare term %g", h.Score, rare[0].Score)
+		}
+	}
+
+	// Repeating a query term does not raise the score
+	once := ix.Search("medicine")
+	twice := ix.Search("medicine medicine")
+	if !reflect.DeepEqual(once, twice) {
+		t.Fatalf("repeated term changed hits: %v vs %v", once, twice)
+	}
+
+	// Exact name matches outrank phrase and plain matches
+	hits := ix.Search("cairo university")
+	if len(hits) < 2 || hits[0].Score <= hits[1].Score {
+		t.Fatalf("exact name hit not ahead: %v", hits)
+	}
+}
+
+func TestNameMatch(t *testing.T) {
+	ix := Build(testCatalogue())
+	tests := []struct {
+		doc   int
+		query string
+		want  int
+	}{
+		{0, "جامعة القاهرة", matchExact},
+		{0, "Cairo University", matchExact},
+		{1, "American University", matchPhrase},
+		{1, "University in Cairo", matchPhrase},
+		{1, "Cairo University", matchNone},
+		{1, "cairo", matchNone},
+		{2, "عين شمس", matchPhrase},
+	}
+	for _, tt := range tests {
+		if got := ix.nameMatch(tt.doc, Tokenize(tt.query)); got != tt.want {
+			t.Errorf("nameMatch(%s, %q) = %d, want %d", ix.ids[tt.doc], tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	ix := Build(nil)
+	if hits := ix.Search("cairo"); ix.Len() != 0 || len(hits) != 0 {
+		t.Fatalf("empty index: len %d, hits %v", ix.Len(), hits)
+	}
+}
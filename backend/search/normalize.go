@@ -0,0 +1,112 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Arabic definite-article and proclitic prefixes stripped from tokens,
+// longest first
+var arabicPrefixes = []string{"وال", "بال", "كال", "فال", "لل", "ال"}
+
+// englishStopWords are too common to help ranking
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "for": true, "in": true,
+	"of": true, "on": true, "the": true, "to": true, "with": true,
+}
+
+// arabicStopWords are common Arabic particles
+var arabicStopWords = map[string]bool{
+	"في": true, "من": true, "علي": true, "الي": true, "عن": true, "و": true,
+}
+
+// NormalizeArabic folds Arabic orthographic variants: it removes tashkeel and
+// tatweel, unifies alef forms (أ إ آ ٱ → ا), teh marbuta (ة → ه), alef maksura
+// (ى → ي) and hamza carriers (ؤ → و, ئ → ي). Latin text is lower-cased.
+func NormalizeArabic(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x064B && r <= 0x0652, r == 0x0670, r == 0x0640:
+			// tashkeel, superscript alef and tatweel
+			continue
+		case r == 'أ' || r == 'إ' || r == 'آ' || r == 'ٱ':
+			r = 'ا'
+		case r == 'ة':
+			r = 'ه'
+		case r == 'ى':
+			r = 'ي'
+		case r == 'ؤ':
+			r = 'و'
+		case r == 'ئ':
+			r = 'ي'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Tokenize splits text into normalised search terms: Arabic tokens lose the
+// definite article, English tokens are stemmed and stop words are dropped.
+func Tokenize(text string) []string {
+	tokens := tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.term
+	}
+	return terms
+}
+
+// token pairs a normalised word with the index term derived from it
+type token struct {
+	word string
+	term string
+}
+
+func tokenize(text string) []token {
+	words := strings.FieldsFunc(NormalizeArabic(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]token, 0, len(words))
+	for _, word := range words {
+		if term := normalizeTerm(word); term != "" {
+			tokens = append(tokens, token{word: word, term: term})
+		}
+	}
+	return tokens
+}
+
+// normalizeTerm reduces a single normalised word to its index term
+func normalizeTerm(word string) string {
+	if isArabic(word) {
+		if arabicStopWords[word] {
+			return ""
+		}
+		return stripArabicPrefix(word)
+	}
+	if englishStopWords[word] {
+		return ""
+	}
+	return Stem(word)
+}
+
+// stripArabicPrefix removes a leading article when at least two letters remain
+func stripArabicPrefix(word string) string {
+	for _, prefix := range arabicPrefixes {
+		if rest, found := strings.CutPrefix(word, prefix); found && len([]rune(rest)) >= 2 {
+			return rest
+		}
+	}
+	return word
+}
+
+func isArabic(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Arabic, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeArabic(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"alef forms", "أإآٱ", "اااا"},
+		{"teh marbuta", "جامعة", "جامعه"},
+		{"alef maksura", "مستشفى", "مستشفي"},
+		{"hamza carriers", "مؤسسة شئون", "موسسه شيون"},
+		{"tashkeel", "الجَامِعَةُ", "الجامعه"},
+		{"superscript alef", "هٰذا", "هذا"},
+		{"tatweel", "جـــامعة", "جامعه"},
+		{"latin is lower-cased", "Cairo UNIVERSITY", "cairo university"},
+		{"mixed", "Faculty of الطبّ", "faculty of الطب"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeArabic(tt.in); got != tt.want {
+				t.Fatalf("NormalizeArabic(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"definite article", "الهندسة", []string{"هندسه"}},
+		{"proclitic articles", "والطب بالقاهرة للعلوم", []string{"طب", "قاهره", "علوم"}},
+		{"short word keeps its article", "الم", []string{"الم"}},
+		{"arabic stop words", "كلية في القاهرة", []string{"كليه", "قاهره"}},
+		{"english stems and stop words", "Faculty of Engineering", []string{"faculti", "engin"}},
+		{"punctuation splits", "cairo,giza;alex-andria", []string{"cairo", "giza", "alex", "andria"}},
+		{"digits are kept", "Class of 2024", []string{"class", "2024"}},
+		{"variants share a term", "جامعة جامعه", []string{"جامعه", "جامعه"}},
+		{"only stop words", "the of and", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package search
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// Porter's published examples
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"caress", "caress"},
+		{"cats", "cat"},
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"hopping", "hop"},
+		{"falling", "fall"},
+		{"filing", "file"},
+		{"happy", "happi"},
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"digitizer", "digit"},
+		{"triplicate", "triplic"},
+		{"hopeful", "hope"},
+		{"goodness", "good"},
+		{"revival", "reviv"},
+		{"adoption", "adopt"},
+		{"controll", "control"},
+		{"rate", "rate"},
+		// Catalogue words sharing a stem
+		{"engineering", "engin"},
+		{"engineer", "engin"},
+		{"engineers", "engin"},
+		{"medicine", "medicin"},
+		{"medical", "medic"},
+		// Left alone
+		{"it", "it"},
+		{"2024", "2024"},
+		{"b2b", "b2b"},
+		{"طب", "طب"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
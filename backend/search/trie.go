@@ -0,0 +1,257 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"roadtouniversities/models"
+)
+
+const (
+	SuggestSpecialty SuggestionKind = "specialty"
+	SuggestCity      SuggestionKind = "city"
+)
+
+// MaxCompletions caps the completions returned for one prefix
+const MaxCompletions = 20
+
+// kindOrder breaks ties between completions of equal popularity
+var kindOrder = map[SuggestionKind]int{
+	SuggestUniversity: 0,
+	SuggestFaculty:    1,
+	SuggestSpecialty:  2,
+	SuggestCity:       3,
+}
+
+// Completion is a catalogue name offered while the user types. Name and
+// NameEn hold the Arabic and English forms; either may be empty.
+type Completion struct {
+	Kind         SuggestionKind
+	Name         string
+	NameEn       string
+	UniversityID string // set for university completions
+	Count        int    // universities the name appears in
+}
+
+// trieNode is a node of the prefix tree. Each node keeps its best
+// completions precomputed, so a lookup costs one walk down the prefix.
+type trieNode struct {
+	children map[rune]*trieNode
+	refs     []completionRef
+}
+
+// completionRef points at a completion from a trie node; leading marks a key
+// that starts at the beginning of the name rather than at a later word
+type completionRef struct {
+	entry   int
+	leading bool
+}
+
+// Completer answers prefix queries over university, faculty, specialty and
+// city names in both languages. It is immutable once built and safe for
+// concurrent use.
+type Completer struct {
+	root    *trieNode
+	entries []Completion
+	byKey   map[string]int
+}
+
+// BuildCompleter collects the names of the given universities into a prefix
+// tree. Every word of a name is a key, so "shams" finds "Ain Shams
+// University", and Arabic words are also keyed without their article.
+func BuildCompleter(unis []models.University) *Completer {
+	cp := &Completer{
+		root:  &trieNode{},
+		byKey: make(map[string]int),
+	}
+
+	for _, uni := range unis {
+		seen := make(map[int]bool)
+		add := func(kind SuggestionKind, name, nameEn, id string) {
+			if i, ok := cp.add(kind, name, nameEn, id); ok && !seen[i] {
+				seen[i] = true
+				cp.entries[i].Count++
+			}
+		}
+
+		add(SuggestUniversity, uni.Name, uni.NameEn, uni.ID)
+		for i, name := range uni.Faculties {
+			nameEn := ""
+			if i < len(uni.FacultiesEn) {
+				nameEn = uni.FacultiesEn[i]
+			}
+			add(SuggestFaculty, name, nameEn, "")
+		}
+		for _, name := range uni.Specialties {
+			add(SuggestSpecialty, name, "", "")
+		}
+		add(SuggestCity, City(uni.Location), City(uni.LocationEn), "")
+	}
+
+	cp.finish(cp.root)
+	return cp
+}
+
+// add registers a completion, merging it with an earlier one of the same
+// kind and name, and returns its index
+func (cp *Completer) add(kind SuggestionKind, name, nameEn, id string) (int, bool) {
+	name, nameEn = strings.TrimSpace(name), strings.TrimSpace(nameEn)
+	if name == "" && nameEn == "" {
+		return 0, false
+	}
+
+	// Universities are distinct even when named alike; other kinds merge on
+	// their English name when they have one
+	key := string(kind) + "\x00" + completionKey(nameEn)
+	switch {
+	case kind == SuggestUniversity:
+		key = string(kind) + "\x00" + id
+	case nameEn == "":
+		key = string(kind) + "\x00\x00" + completionKey(name)
+	}
+	if i, found := cp.byKey[key]; found {
+		if cp.entries[i].Name == "" {
+			cp.entries[i].Name = name
+			cp.insert(name, i)
+		}
+		return i, true
+	}
+
+	i := len(cp.entries)
+	cp.entries = append(cp.entries, Completion{Kind: kind, Name: name, NameEn: nameEn, UniversityID: id})
+	cp.byKey[key] = i
+	cp.insert(name, i)
+	cp.insert(nameEn, i)
+	return i, true
+}
+
+// insert adds a key for every word start of text
+func (cp *Completer) insert(text string, entry int) {
+	normalized := completionKey(text)
+	if normalized == "" {
+		return
+	}
+
+	words := strings.Split(normalized, " ")
+	for w := range words {
+		suffix := strings.Join(words[w:], " ")
+		cp.insertKey(suffix, completionRef{entry: entry, leading: w == 0})
+		if stripped := stripArabicPrefix(words[w]); stripped != words[w] {
+			cp.insertKey(strings.Join(append([]string{stripped}, words[w+1:]...), " "), completionRef{entry: entry})
+		}
+	}
+}
+
+func (cp *Completer) insertKey(key string, ref completionRef) {
+	node := cp.root
+	for _, r := range key {
+		child := node.children[r]
+		if child == nil {
+			if node.children == nil {
+				node.children = make(map[rune]*trieNode)
+			}
+			child = &trieNode{}
+			node.children[r] = child
+		}
+		node = child
+		node.refs = append(node.refs, ref)
+	}
+}
+
+// finish ranks and trims the completions held at each node
+func (cp *Completer) finish(node *trieNode) {
+	if len(node.refs) > 0 {
+		// Keep one ref per entry, preferring a leading match
+		best := make(map[int]bool, len(node.refs))
+		for _, ref := range node.refs {
+			best[ref.entry] = best[ref.entry] || ref.leading
+		}
+		refs := make([]completionRef, 0, len(best))
+		for entry, leading := range best {
+			refs = append(refs, completionRef{entry: entry, leading: leading})
+		}
+		sort.Slice(refs, func(i, j int) bool { return cp.less(refs[i], refs[j]) })
+		if len(refs) > MaxCompletions {
+			refs = refs[:MaxCompletions]
+		}
+		node.refs = refs
+	}
+	for _, child := range node.children {
+		cp.finish(child)
+	}
+}
+
+// less ranks matches at the start of a name first, then names shared by
+// more universities, then shorter names
+func (cp *Completer) less(a, b completionRef) bool {
+	if a.leading != b.leading {
+		return a.leading
+	}
+	ea, eb := cp.entries[a.entry], cp.entries[b.entry]
+	if ea.Count != eb.Count {
+		return ea.Count > eb.Count
+	}
+	if kindOrder[ea.Kind] != kindOrder[eb.Kind] {
+		return kindOrder[ea.Kind] < kindOrder[eb.Kind]
+	}
+	la, lb := utf8.RuneCountInString(ea.NameEn+ea.Name), utf8.RuneCountInString(eb.NameEn+eb.Name)
+	if la != lb {
+		return la < lb
+	}
+	return ea.NameEn+ea.Name < eb.NameEn+eb.Name
+}
+
+// Complete returns up to limit completions for prefix, best first
+func (cp *Completer) Complete(prefix string, limit int) []Completion {
+	key := completionKey(prefix)
+	if key == "" || limit <= 0 {
+		return nil
+	}
+
+	node := cp.root
+	for _, r := range key {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+
+	if limit > len(node.refs) {
+		limit = len(node.refs)
+	}
+	result := make([]Completion, limit)
+	for i, ref := range node.refs[:limit] {
+		result[i] = cp.entries[ref.entry]
+	}
+	return result
+}
+
+// Len returns the number of distinct completions
+func (cp *Completer) Len() int {
+	return len(cp.entries)
+}
+
+// completionKey normalises text for prefix matching: Arabic variants are
+// folded, punctuation dropped and spaces collapsed. Unlike Tokenize it keeps
+// stop words and does not stem, since the user is still typing.
+func completionKey(text string) string {
+	words := strings.FieldsFunc(NormalizeArabic(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// City takes the city from a "City, Country" location
+func City(location string) string {
+	if i := strings.IndexAny(location, ",،"); i >= 0 {
+		location = location[:i]
+	}
+	return strings.TrimSpace(location)
+}
+
+// IsArabicText reports whether s contains Arabic letters
+func IsArabicText(s string) bool {
+	return isArabic(s)
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"roadtouniversities/models"
+)
+
+func testCompleter() *Completer {
+	return BuildCompleter([]models.University{
+		{
+			ID: "1", Name: "جامعة القاهرة", NameEn: "Cairo University", Location: "الجيزة، مصر", LocationEn: "Giza, Egypt",
+			Faculties: []string{"الطب", "الهندسة"}, FacultiesEn: []string{"Medicine", "Engineering"},
+			Specialties: []string{"طب الأسنان"},
+		},
+		{
+			ID: "2", Name: "جامعة عين شمس", NameEn: "Ain Shams University", Location: "القاهرة، مصر", LocationEn: "Cairo, Egypt",
+			Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"},
+		},
+		{
+			ID: "3", Name: "الجامعة الأمريكية بالقاهرة", NameEn: "American University in Cairo", Location: "القاهرة، مصر", LocationEn: "Cairo, Egypt",
+			Faculties: []string{"الهندسة"}, FacultiesEn: []string{"Engineering"},
+		},
+	})
+}
+
+func completionNames(completions []Completion) []string {
+	names := make([]string, len(completions))
+	for i, c := range completions {
+		names[i] = string(c.Kind) + ":" + c.NameEn + "|" + c.Name
+	}
+	return names
+}
+
+func TestComplete(t *testing.T) {
+	cp := testCompleter()
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"empty prefix", "", 5, nil},
+		{"zero limit", "ca", 0, nil},
+		{"no match", "xyz", 5, nil},
+		{"leading matches first", "cai", 5, []string{
+			"city:Cairo|القاهرة",
+			"university:Cairo University|جامعة القاهرة",
+			"university:American University in Cairo|الجامعة الأمريكية بالقاهرة",
+		}},
+		{"later word", "sha", 5, []string{"university:Ain Shams University|جامعة عين شمس"}},
+		{"case insensitive", "ENG", 5, []string{"faculty:Engineering|الهندسة"}},
+		{"limit", "cai", 1, []string{"city:Cairo|القاهرة"}},
+		{"arabic without article", "هندس", 5, []string{"faculty:Engineering|الهندسة"}},
+		{"arabic variants", "القاهره", 5, []string{
+			"city:Cairo|القاهرة",
+			"university:Cairo University|جامعة القاهرة",
+		}},
+		{"proclitic", "قاهر", 5, []string{
+			"city:Cairo|القاهرة",
+			"university:Cairo University|جامعة القاهرة",
+			"university:American University in Cairo|الجامعة الأمريكية بالقاهرة",
+		}},
+		{"specialty", "طب ال", 5, []string{"specialty:|طب الأسنان"}},
+		{"multi-word prefix", "cairo uni", 5, []string{"university:Cairo University|جامعة القاهرة"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cp.Complete(tt.prefix, tt.limit)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("Complete(%q) = %v, want nil", tt.prefix, completionNames(got))
+				}
+				return
+			}
+			if names := completionNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("Complete(%q) = %q, want %q", tt.prefix, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteCounts(t *testing.T) {
+	cp := testCompleter()
+
+	// Faculties shared by universities merge on their English name
+	got := cp.Complete("engineering", 5)
+	if len(got) != 1 || got[0].Count != 3 {
+		t.Fatalf("Complete(engineering) = %+v, want one faculty in 3 universities", got)
+	}
+	cities := cp.Complete("cairo", 5)
+	if len(cities) == 0 || cities[0].Kind != SuggestCity || cities[0].Count != 2 {
+		t.Fatalf("Complete(cairo) = %+v, want the city in 2 universities first", cities)
+	}
+	if cp.Len() != 8 {
+		t.Fatalf("Len = %d, want 8", cp.Len())
+	}
+}
+
+func TestCompleteCapsNodes(t *testing.T) {
+	var unis []models.University
+	for i := 0; i < MaxCompletions+5; i++ {
+		id := string(rune('a' + i))
+		unis = append(unis, models.University{ID: id, NameEn: "Test University " + id})
+	}
+	cp := BuildCompleter(unis)
+	if got := cp.Complete("test", 100); len(got) != MaxCompletions {
+		t.Fatalf("Complete returned %d completions, want %d", len(got), MaxCompletions)
+	}
+}
+
+func TestCity(t *testing.T) {
+	tests := map[string]string{
+		"Cairo, Egypt": "Cairo",
+		"القاهرة، مصر": "القاهرة",
+		" Giza ":       "Giza",
+		"":             "",
+		"New Cairo":    "New Cairo",
+	}
+	for in, want := range tests {
+		if got := City(in); got != want {
+			t.Errorf("City(%q) = %q, want %q", in, got, want)
+		}
+	}
+}